@@ -0,0 +1,134 @@
+// Package addresshistory provides ready-made handlers for the paginated history of an address,
+// i.e. the outputs it received and spent. The history is served from a Store,
+// which is implemented by the storage adapter of the app, e.g. an SQL database.
+// Only the Store interface is provided, the package contains no storage adapter.
+// The transaction history combines the entries of the Store with lookups at the node.
+package addresshistory
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/iotaledger/inx-app/pkg/httpserver"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// ParameterAddress is used to identify an address by its bech32 representation.
+	ParameterAddress = "address"
+
+	// QueryParameterPageSize is used to specify the maximum amount of history entries in the response.
+	QueryParameterPageSize = "pageSize"
+
+	// QueryParameterCursor is used to continue a previous request, it is the cursor returned in the previous response.
+	QueryParameterCursor = "cursor"
+
+	// RouteAddressHistory is the route to get the history of an address.
+	// GET returns the received and spent outputs of the address, newest first.
+	RouteAddressHistory = "/addresses/:" + ParameterAddress + "/history"
+)
+
+const (
+	// DefaultPageSize is the page size used if none is specified.
+	DefaultPageSize = 100
+	// MaxPageSize is the maximum allowed page size.
+	MaxPageSize = 1000
+)
+
+// EntryType is the type of a history entry.
+type EntryType string
+
+const (
+	// EntryTypeReceived means the address received the output.
+	EntryTypeReceived EntryType = "received"
+	// EntryTypeSpent means the output of the address was spent.
+	EntryTypeSpent EntryType = "spent"
+)
+
+// Entry is a single entry of the history of an address.
+type Entry struct {
+	// Type is either EntryTypeReceived or EntryTypeSpent.
+	Type EntryType
+	// OutputID is the ID of the received or spent output.
+	OutputID iotago.OutputID
+	// MilestoneIndex is the index of the milestone that confirmed the entry.
+	MilestoneIndex iotago.MilestoneIndex
+	// MilestoneTimestamp is the unix timestamp of the milestone that confirmed the entry.
+	MilestoneTimestamp uint32
+}
+
+// Store is the storage the history is served from.
+type Store interface {
+	// AddressHistory returns at most pageSize entries of the history of the address, newest first,
+	// starting at the given cursor. The page size is at least 1. An empty cursor starts at the newest entry.
+	// The returned cursor continues the history, it is empty if there are no more entries.
+	AddressHistory(ctx context.Context, address iotago.Address, pageSize int, cursor string) ([]*Entry, string, error)
+}
+
+// EntryResponse defines a single entry of the history of an address.
+type EntryResponse struct {
+	// Type is either "received" or "spent".
+	Type EntryType `json:"type"`
+	// OutputID is the hex encoded ID of the output.
+	OutputID string `json:"outputId"`
+	// MilestoneIndex is the index of the milestone that confirmed the entry.
+	MilestoneIndex uint32 `json:"milestoneIndex"`
+	// MilestoneTimestamp is the unix timestamp of the milestone that confirmed the entry.
+	MilestoneTimestamp uint32 `json:"milestoneTimestamp"`
+}
+
+// AddressHistoryResponse defines the response of a GET RouteAddressHistory REST API call.
+type AddressHistoryResponse struct {
+	// Address is the bech32 encoded address.
+	Address string `json:"address"`
+	// Items are the entries of the history, newest first.
+	Items []*EntryResponse `json:"items"`
+	// Cursor continues the history in the next request, it is omitted on the last page.
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// RegisterRoutes registers the address history routes on the given group.
+func RegisterRoutes(routeGroup *echo.Group, nodeBridge *nodebridge.NodeBridge, store Store) {
+	routeGroup.GET(RouteAddressHistory, AddressHistoryHandler(nodeBridge, store))
+	routeGroup.GET(RouteAddressTransactions, TransactionHistoryHandler(nodeBridge, store))
+}
+
+// AddressHistoryHandler returns a handler that serves the paginated history of an address from the store.
+func AddressHistoryHandler(nodeBridge *nodebridge.NodeBridge, store Store) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		hrp := nodeBridge.ProtocolParameters().Bech32HRP
+		address, err := httpserver.ParseBech32AddressParam(c, hrp, ParameterAddress)
+		if err != nil {
+			return err
+		}
+
+		pageSize, err := httpserver.ParsePageSizeQueryParam(c, DefaultPageSize, MaxPageSize)
+		if err != nil {
+			return err
+		}
+
+		entries, cursor, err := store.AddressHistory(c.Request().Context(), address, pageSize, c.QueryParam(QueryParameterCursor))
+		if err != nil {
+			return err
+		}
+
+		response := &AddressHistoryResponse{
+			Address: address.Bech32(hrp),
+			Items:   make([]*EntryResponse, 0, len(entries)),
+			Cursor:  cursor,
+		}
+		for _, entry := range entries {
+			response.Items = append(response.Items, &EntryResponse{
+				Type:               entry.Type,
+				OutputID:           entry.OutputID.ToHex(),
+				MilestoneIndex:     entry.MilestoneIndex,
+				MilestoneTimestamp: entry.MilestoneTimestamp,
+			})
+		}
+
+		return httpserver.JSONResponse(c, http.StatusOK, response)
+	}
+}
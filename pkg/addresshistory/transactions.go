@@ -0,0 +1,323 @@
+package addresshistory
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/serializer/v2"
+	"github.com/iotaledger/inx-app/pkg/httpserver"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+	inx "github.com/iotaledger/inx/go"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// RouteAddressTransactions is the route to get the transaction history of an address.
+// GET returns the incoming and outgoing transfers of the address with their counterparties, newest first.
+const RouteAddressTransactions = "/addresses/:" + ParameterAddress + "/transactions"
+
+// ErrNoTransactionPayload is returned if the block that created an output of the history does not contain a transaction.
+var ErrNoTransactionPayload = errors.New("block does not contain a transaction")
+
+// TransactionDirection is the direction of a transaction from the view of an address.
+type TransactionDirection string
+
+const (
+	// TransactionDirectionIncoming means the address received tokens without spending any of its outputs.
+	TransactionDirectionIncoming TransactionDirection = "incoming"
+	// TransactionDirectionOutgoing means the address spent at least one of its outputs.
+	TransactionDirectionOutgoing TransactionDirection = "outgoing"
+)
+
+// Counterparty is an address on the other side of a transaction.
+type Counterparty struct {
+	// Address is the address of the counterparty.
+	Address iotago.Address
+	// Amount is the amount of base tokens the counterparty sent for incoming transactions and received for outgoing ones.
+	Amount uint64
+}
+
+// Transaction is a transaction of the history of an address.
+type Transaction struct {
+	// TransactionID is the ID of the transaction.
+	TransactionID iotago.TransactionID
+	// BlockID is the ID of the block that contains the transaction.
+	BlockID iotago.BlockID
+	// Direction is the direction of the transaction from the view of the address.
+	Direction TransactionDirection
+	// BalanceChange is the change of the base token balance of the address caused by the transaction.
+	BalanceChange int64
+	// Counterparties are the senders of incoming and the recipients of outgoing transactions.
+	Counterparties []*Counterparty
+	// MilestoneIndex is the index of the milestone that confirmed the transaction.
+	MilestoneIndex iotago.MilestoneIndex
+	// MilestoneTimestamp is the unix timestamp of the milestone that confirmed the transaction.
+	MilestoneTimestamp uint32
+}
+
+// ledgerOutput returns the output of the response, regardless whether it is spent.
+func ledgerOutput(response *inx.OutputResponse) *inx.LedgerOutput {
+	if spent := response.GetSpent(); spent != nil {
+		return spent.GetOutput()
+	}
+
+	return response.GetOutput()
+}
+
+// addCounterparty adds the amount to the counterparty with the given address, counterparties keep the order they were added in.
+func addCounterparty(counterparties []*Counterparty, address iotago.Address, amount uint64) []*Counterparty {
+	for _, counterparty := range counterparties {
+		if counterparty.Address.Equal(address) {
+			counterparty.Amount += amount
+
+			return counterparties
+		}
+	}
+
+	return append(counterparties, &Counterparty{Address: address, Amount: amount})
+}
+
+// ResolveTransaction reconstructs the transaction with the given ID from the view of the address by looking up
+// the transaction, its inputs and its outputs at the node.
+func ResolveTransaction(ctx context.Context, nodeBridge *nodebridge.NodeBridge, address iotago.Address, transactionID iotago.TransactionID) (*Transaction, error) {
+	// every transaction has at least one output, which points to the block containing the transaction
+	firstOutput, err := nodeBridge.Output(ctx, iotago.OutputIDFromTransactionIDAndIndex(transactionID, 0))
+	if err != nil {
+		return nil, errors.Wrapf(err, "looking up the outputs of transaction %s failed", transactionID.ToHex())
+	}
+	firstLedgerOutput := ledgerOutput(firstOutput)
+
+	block, err := nodeBridge.Block(ctx, firstLedgerOutput.UnwrapBlockID())
+	if err != nil {
+		return nil, errors.Wrapf(err, "looking up the block of transaction %s failed", transactionID.ToHex())
+	}
+
+	transactionPayload, ok := block.Payload.(*iotago.Transaction)
+	if !ok || transactionPayload.Essence == nil {
+		return nil, errors.WithMessagef(ErrNoTransactionPayload, "transaction %s", transactionID.ToHex())
+	}
+
+	transaction := &Transaction{
+		TransactionID:      transactionID,
+		BlockID:            firstLedgerOutput.UnwrapBlockID(),
+		Direction:          TransactionDirectionIncoming,
+		Counterparties:     make([]*Counterparty, 0),
+		MilestoneIndex:     firstLedgerOutput.GetMilestoneIndexBooked(),
+		MilestoneTimestamp: firstLedgerOutput.GetMilestoneTimestampBooked(),
+	}
+
+	senders := make([]*Counterparty, 0)
+	for _, input := range transactionPayload.Essence.Inputs {
+		utxoInput, ok := input.(*iotago.UTXOInput)
+		if !ok {
+			continue
+		}
+
+		inputResponse, err := nodeBridge.Output(ctx, utxoInput.ID())
+		if err != nil {
+			return nil, errors.Wrapf(err, "looking up input %s of transaction %s failed", utxoInput.ID().ToHex(), transactionID.ToHex())
+		}
+
+		output, err := ledgerOutput(inputResponse).UnwrapOutput(serializer.DeSeriModeNoValidation, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		owner, ok := nodebridge.OutputOwner(output)
+		if !ok {
+			continue
+		}
+
+		if owner.Equal(address) {
+			transaction.Direction = TransactionDirectionOutgoing
+			transaction.BalanceChange -= int64(output.Deposit())
+
+			continue
+		}
+		senders = addCounterparty(senders, owner, output.Deposit())
+	}
+
+	recipients := make([]*Counterparty, 0)
+	for _, output := range transactionPayload.Essence.Outputs {
+		owner, ok := nodebridge.OutputOwner(output)
+		if !ok {
+			continue
+		}
+
+		if owner.Equal(address) {
+			transaction.BalanceChange += int64(output.Deposit())
+
+			continue
+		}
+		recipients = addCounterparty(recipients, owner, output.Deposit())
+	}
+
+	if transaction.Direction == TransactionDirectionOutgoing {
+		transaction.Counterparties = recipients
+	} else {
+		transaction.Counterparties = senders
+	}
+
+	return transaction, nil
+}
+
+// transactionIDOfEntry returns the ID of the transaction that created or spent the output of the entry.
+func transactionIDOfEntry(ctx context.Context, nodeBridge *nodebridge.NodeBridge, entry *Entry) (iotago.TransactionID, error) {
+	if entry.Type == EntryTypeReceived {
+		return entry.OutputID.TransactionID(), nil
+	}
+
+	response, err := nodeBridge.Output(ctx, entry.OutputID)
+	if err != nil {
+		return iotago.TransactionID{}, errors.Wrapf(err, "looking up spent output %s failed", entry.OutputID.ToHex())
+	}
+
+	spent := response.GetSpent()
+	if spent == nil {
+		return iotago.TransactionID{}, errors.Errorf("output %s of the history is not spent", entry.OutputID.ToHex())
+	}
+
+	return spent.UnwrapTransactionIDSpent(), nil
+}
+
+// TransactionHistory reconstructs at most pageSize history entries of the address from the store as transactions, newest first,
+// by looking up the transactions at the node. The entries of the same transaction are merged, so the page can contain fewer transactions.
+// The returned cursor continues the history, it is empty if there are no more entries.
+// The cursor remembers the last transaction, so it is not repeated if its entries are split over two pages.
+func TransactionHistory(ctx context.Context, nodeBridge *nodebridge.NodeBridge, store Store, address iotago.Address, pageSize int, cursor string) ([]*Transaction, string, error) {
+	if pageSize < 1 {
+		return nil, "", errors.WithMessagef(httpserver.ErrInvalidParameter, "invalid page size: %d, must be at least 1", pageSize)
+	}
+
+	var lastTransactionHex string
+	storeCursor := cursor
+	if cursor != "" {
+		var found bool
+		if lastTransactionHex, storeCursor, found = strings.Cut(cursor, ":"); !found {
+			return nil, "", errors.WithMessagef(httpserver.ErrInvalidParameter, "invalid cursor: %s", cursor)
+		}
+	}
+
+	entries, nextStoreCursor, err := store.AddressHistory(ctx, address, pageSize, storeCursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	transactions := make([]*Transaction, 0)
+	seen := make(map[iotago.TransactionID]struct{})
+	for _, entry := range entries {
+		transactionID, err := transactionIDOfEntry(ctx, nodeBridge, entry)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if _, exists := seen[transactionID]; exists || transactionID.ToHex() == lastTransactionHex {
+			continue
+		}
+		seen[transactionID] = struct{}{}
+
+		transaction, err := ResolveTransaction(ctx, nodeBridge, address, transactionID)
+		if err != nil {
+			return nil, "", err
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	if nextStoreCursor == "" {
+		return transactions, "", nil
+	}
+
+	if len(transactions) > 0 {
+		lastTransactionHex = transactions[len(transactions)-1].TransactionID.ToHex()
+	}
+
+	return transactions, lastTransactionHex + ":" + nextStoreCursor, nil
+}
+
+// CounterpartyResponse defines a counterparty of a transaction.
+type CounterpartyResponse struct {
+	// Address is the bech32 encoded address of the counterparty.
+	Address string `json:"address"`
+	// Amount is the amount of base tokens the counterparty sent or received.
+	Amount string `json:"amount"`
+}
+
+// TransactionResponse defines a single transaction of the history of an address.
+type TransactionResponse struct {
+	// TransactionID is the hex encoded ID of the transaction.
+	TransactionID string `json:"transactionId"`
+	// BlockID is the hex encoded ID of the block that contains the transaction.
+	BlockID string `json:"blockId"`
+	// Direction is either "incoming" or "outgoing".
+	Direction TransactionDirection `json:"direction"`
+	// BalanceChange is the change of the base token balance of the address.
+	BalanceChange string `json:"balanceChange"`
+	// Counterparties are the senders of incoming and the recipients of outgoing transactions.
+	Counterparties []*CounterpartyResponse `json:"counterparties"`
+	// MilestoneIndex is the index of the milestone that confirmed the transaction.
+	MilestoneIndex uint32 `json:"milestoneIndex"`
+	// MilestoneTimestamp is the unix timestamp of the milestone that confirmed the transaction.
+	MilestoneTimestamp uint32 `json:"milestoneTimestamp"`
+}
+
+// TransactionHistoryResponse defines the response of a GET RouteAddressTransactions REST API call.
+type TransactionHistoryResponse struct {
+	// Address is the bech32 encoded address.
+	Address string `json:"address"`
+	// Items are the transactions, newest first.
+	Items []*TransactionResponse `json:"items"`
+	// Cursor continues the history in the next request, it is omitted on the last page.
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// TransactionHistoryHandler returns a handler that serves the paginated transaction history of an address.
+func TransactionHistoryHandler(nodeBridge *nodebridge.NodeBridge, store Store) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		hrp := nodeBridge.ProtocolParameters().Bech32HRP
+		address, err := httpserver.ParseBech32AddressParam(c, hrp, ParameterAddress)
+		if err != nil {
+			return err
+		}
+
+		pageSize, err := httpserver.ParsePageSizeQueryParam(c, DefaultPageSize, MaxPageSize)
+		if err != nil {
+			return err
+		}
+
+		transactions, cursor, err := TransactionHistory(c.Request().Context(), nodeBridge, store, address, pageSize, c.QueryParam(QueryParameterCursor))
+		if err != nil {
+			return err
+		}
+
+		response := &TransactionHistoryResponse{
+			Address: address.Bech32(hrp),
+			Items:   make([]*TransactionResponse, 0, len(transactions)),
+			Cursor:  cursor,
+		}
+		for _, transaction := range transactions {
+			counterparties := make([]*CounterpartyResponse, 0, len(transaction.Counterparties))
+			for _, counterparty := range transaction.Counterparties {
+				counterparties = append(counterparties, &CounterpartyResponse{
+					Address: counterparty.Address.Bech32(hrp),
+					Amount:  strconv.FormatUint(counterparty.Amount, 10),
+				})
+			}
+
+			response.Items = append(response.Items, &TransactionResponse{
+				TransactionID:      transaction.TransactionID.ToHex(),
+				BlockID:            transaction.BlockID.ToHex(),
+				Direction:          transaction.Direction,
+				BalanceChange:      strconv.FormatInt(transaction.BalanceChange, 10),
+				Counterparties:     counterparties,
+				MilestoneIndex:     transaction.MilestoneIndex,
+				MilestoneTimestamp: transaction.MilestoneTimestamp,
+			})
+		}
+
+		return httpserver.JSONResponse(c, http.StatusOK, response)
+	}
+}
@@ -0,0 +1,407 @@
+// Package admin provides an optional route group exposing runtime information of an app,
+// like the connection to the node, the lag of the streams, cache statistics and the configuration,
+// as well as actions like restarting a stream, flushing a cache or throttling a replay.
+// All routes are protected, they are only served if an auth middleware is given.
+package admin
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/configuration"
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/inx-app/pkg/cache"
+	"github.com/iotaledger/inx-app/pkg/httpserver"
+	"github.com/iotaledger/inx-app/pkg/journal"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+)
+
+const (
+	// ParameterStream is used to identify a stream by its name.
+	ParameterStream = "stream"
+
+	// ParameterCache is used to identify a cache by its name.
+	ParameterCache = "cache"
+
+	// ParameterThrottle is used to identify a replay throttle by its name.
+	ParameterThrottle = "throttle"
+
+	// QueryParameterMilestonesPerSecond is used to specify the maximum replay rate, 0 means unlimited.
+	QueryParameterMilestonesPerSecond = "milestonesPerSecond"
+
+	// RouteStatus is the route to get the connection state of the app.
+	// GET returns the state of the connection to the node and its status.
+	RouteStatus = "/status"
+
+	// RouteStreams is the route to get the streams run by the stream watchdog.
+	// GET returns the progress and lag of the streams.
+	RouteStreams = "/streams"
+
+	// RouteStreamRestart is the route to restart a stream.
+	// POST restarts the stream, it resumes from the last consumed milestone.
+	RouteStreamRestart = "/streams/:" + ParameterStream + "/restart"
+
+	// RouteCaches is the route to get the registered caches.
+	// GET returns the size and statistics of the caches.
+	RouteCaches = "/caches"
+
+	// RouteCacheFlush is the route to flush a cache.
+	// POST evicts all entries of the cache.
+	RouteCacheFlush = "/caches/:" + ParameterCache + "/flush"
+
+	// RouteThrottles is the route to get the registered replay throttles.
+	// GET returns the rate and pause state of the throttles.
+	RouteThrottles = "/throttles"
+
+	// RouteThrottlePause is the route to pause a replay.
+	// POST pauses the replay before the next milestone.
+	RouteThrottlePause = "/throttles/:" + ParameterThrottle + "/pause"
+
+	// RouteThrottleResume is the route to resume a paused replay.
+	// POST resumes the replay.
+	RouteThrottleResume = "/throttles/:" + ParameterThrottle + "/resume"
+
+	// RouteThrottleRate is the route to change the rate of a replay.
+	// POST sets the maximum rate to the milestonesPerSecond query parameter.
+	RouteThrottleRate = "/throttles/:" + ParameterThrottle + "/rate"
+
+	// RouteConfig is the route to get the configuration of the app.
+	// GET returns the loaded configuration with sensitive values redacted.
+	RouteConfig = "/config"
+)
+
+// DefaultRedactedConfigKeywords are redacted in the configuration if they are part of the last segment of a key,
+// in addition to the masked keys.
+var DefaultRedactedConfigKeywords = []string{"password", "secret", "token", "salt", "privatekey", "apikey"}
+
+// Cache is a cache that can be inspected and flushed via the admin API, e.g. a cache.LedgerCache.
+// Caches that also provide Stats() cache.MetadataStats, like a cache.MetadataCache, report their statistics.
+type Cache interface {
+	// Size returns the amount of cached entries.
+	Size() int
+	// Flush evicts all entries.
+	Flush()
+}
+
+// StatusResponse defines the response of a GET RouteStatus REST API call.
+type StatusResponse struct {
+	// NodeConnection is the state of the gRPC connection to the node, e.g. "READY".
+	NodeConnection string `json:"nodeConnection"`
+	// NodeHealthy is whether the node reports itself as healthy.
+	NodeHealthy bool `json:"nodeHealthy"`
+	// NodeSynced is whether the node is synced.
+	NodeSynced bool `json:"nodeSynced"`
+	// ConfirmedMilestoneIndex is the confirmed milestone index of the node.
+	ConfirmedMilestoneIndex uint32 `json:"confirmedMilestoneIndex"`
+	// LedgerIndex is the ledger index of the node.
+	LedgerIndex uint32 `json:"ledgerIndex"`
+}
+
+// StreamsResponse defines the response of a GET RouteStreams REST API call.
+type StreamsResponse struct {
+	// Streams are the streams run by the stream watchdog, sorted by name.
+	Streams []*nodebridge.StreamInfo `json:"streams"`
+}
+
+// CacheResponse defines a single cache in the response of a GET RouteCaches REST API call.
+type CacheResponse struct {
+	// Name is the name the cache was registered with.
+	Name string `json:"name"`
+	// Size is the amount of cached entries.
+	Size int `json:"size"`
+	// Stats are the counters of the cache, if the cache provides them.
+	Stats *cache.MetadataStats `json:"stats,omitempty"`
+}
+
+// CachesResponse defines the response of a GET RouteCaches REST API call.
+type CachesResponse struct {
+	// Caches are the registered caches, sorted by name.
+	Caches []*CacheResponse `json:"caches"`
+}
+
+// ThrottleResponse defines a single replay throttle in the response of a GET RouteThrottles REST API call.
+type ThrottleResponse struct {
+	// Name is the name the throttle was registered with.
+	Name string `json:"name"`
+	*journal.ThrottleStatus
+}
+
+// ThrottlesResponse defines the response of a GET RouteThrottles REST API call.
+type ThrottlesResponse struct {
+	// Throttles are the registered replay throttles, sorted by name.
+	Throttles []*ThrottleResponse `json:"throttles"`
+}
+
+// Admin serves the admin API.
+type Admin struct {
+	nodeBridge *nodebridge.NodeBridge
+
+	streamWatchdog *nodebridge.StreamWatchdog
+	caches         map[string]Cache
+	throttles      map[string]*journal.Throttle
+	config         *configuration.Configuration
+	maskedKeys     []string
+}
+
+// WithStreamWatchdog exposes the streams run by the given watchdog.
+func WithStreamWatchdog(streamWatchdog *nodebridge.StreamWatchdog) options.Option[Admin] {
+	return func(a *Admin) {
+		a.streamWatchdog = streamWatchdog
+	}
+}
+
+// WithCache exposes the given cache under the given name.
+func WithCache(name string, c Cache) options.Option[Admin] {
+	return func(a *Admin) {
+		a.caches[name] = c
+	}
+}
+
+// WithThrottle exposes the given replay throttle under the given name.
+func WithThrottle(name string, throttle *journal.Throttle) options.Option[Admin] {
+	return func(a *Admin) {
+		a.throttles[name] = throttle
+	}
+}
+
+// WithConfig exposes the given configuration.
+// The masked keys, e.g. the Masked keys of the component definitions, and their children are redacted.
+func WithConfig(config *configuration.Configuration, maskedKeys ...string) options.Option[Admin] {
+	return func(a *Admin) {
+		a.config = config
+		a.maskedKeys = maskedKeys
+	}
+}
+
+// NewAdmin creates a new Admin.
+func NewAdmin(nodeBridge *nodebridge.NodeBridge, opts ...options.Option[Admin]) *Admin {
+	return options.Apply(&Admin{
+		nodeBridge: nodeBridge,
+		caches:     make(map[string]Cache),
+		throttles:  make(map[string]*journal.Throttle),
+	}, opts)
+}
+
+// RouteTable returns the routes of the admin API below the given API route, e.g. "myapp-admin/v1".
+// All routes use httpserver.AuthPolicyProtected, which is enforced by the given auth middleware.
+// If the auth middleware is nil, registering the routes fails.
+func (a *Admin) RouteTable(apiRoute string, authMiddleware echo.MiddlewareFunc) *httpserver.RouteTable {
+	route := func(method string, path string, handler echo.HandlerFunc, summary string) *httpserver.Route {
+		return &httpserver.Route{
+			Method:  method,
+			Path:    path,
+			Handler: handler,
+			Summary: summary,
+			Tags:    []string{"admin"},
+			Auth:    httpserver.AuthPolicyProtected,
+			Cache:   httpserver.CachePolicyNoStore,
+		}
+	}
+
+	routes := []*httpserver.Route{
+		route(http.MethodGet, RouteStatus, a.statusHandler, "Returns the state of the connection to the node."),
+		route(http.MethodGet, RouteStreams, a.streamsHandler, "Returns the progress and lag of the streams."),
+		route(http.MethodPost, RouteStreamRestart, a.streamRestartHandler, "Restarts a stream."),
+		route(http.MethodGet, RouteCaches, a.cachesHandler, "Returns the size and statistics of the caches."),
+		route(http.MethodPost, RouteCacheFlush, a.cacheFlushHandler, "Evicts all entries of a cache."),
+		route(http.MethodGet, RouteThrottles, a.throttlesHandler, "Returns the rate and pause state of the replay throttles."),
+		route(http.MethodPost, RouteThrottlePause, a.throttlePauseHandler, "Pauses a replay."),
+		route(http.MethodPost, RouteThrottleResume, a.throttleResumeHandler, "Resumes a paused replay."),
+		route(http.MethodPost, RouteThrottleRate, a.throttleRateHandler, "Sets the maximum rate of a replay."),
+		route(http.MethodGet, RouteConfig, a.configHandler, "Returns the configuration with sensitive values redacted."),
+	}
+
+	var opts []options.Option[httpserver.RouteTable]
+	if authMiddleware != nil {
+		opts = append(opts, httpserver.WithAuthPreset(httpserver.AuthPolicyProtected, authMiddleware))
+	}
+
+	return httpserver.NewRouteTable(apiRoute, routes, opts...)
+}
+
+func (a *Admin) statusHandler(c echo.Context) error {
+	nodeStatus := a.nodeBridge.NodeStatus()
+
+	return httpserver.JSONResponse(c, http.StatusOK, &StatusResponse{
+		NodeConnection:          a.nodeBridge.ConnectionState().String(),
+		NodeHealthy:             a.nodeBridge.IsNodeHealthy(),
+		NodeSynced:              a.nodeBridge.IsNodeSynced(),
+		ConfirmedMilestoneIndex: a.nodeBridge.ConfirmedMilestoneIndex(),
+		LedgerIndex:             nodeStatus.GetLedgerIndex(),
+	})
+}
+
+func (a *Admin) streamsHandler(c echo.Context) error {
+	response := &StreamsResponse{
+		Streams: make([]*nodebridge.StreamInfo, 0),
+	}
+	if a.streamWatchdog != nil {
+		response.Streams = a.streamWatchdog.Streams()
+	}
+
+	return httpserver.JSONResponse(c, http.StatusOK, response)
+}
+
+func (a *Admin) streamRestartHandler(c echo.Context) error {
+	name := c.Param(ParameterStream)
+
+	if a.streamWatchdog == nil {
+		return errors.WithMessagef(echo.ErrNotFound, "stream not found: %s", name)
+	}
+
+	if err := a.streamWatchdog.RestartStream(name); err != nil {
+		if errors.Is(err, nodebridge.ErrStreamNotFound) {
+			return errors.WithMessagef(echo.ErrNotFound, "stream not found: %s", name)
+		}
+
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (a *Admin) cachesHandler(c echo.Context) error {
+	response := &CachesResponse{
+		Caches: make([]*CacheResponse, 0, len(a.caches)),
+	}
+
+	for name, registeredCache := range a.caches {
+		cacheResponse := &CacheResponse{
+			Name: name,
+			Size: registeredCache.Size(),
+		}
+		if statsProvider, ok := registeredCache.(interface{ Stats() cache.MetadataStats }); ok {
+			stats := statsProvider.Stats()
+			cacheResponse.Stats = &stats
+		}
+		response.Caches = append(response.Caches, cacheResponse)
+	}
+
+	sort.Slice(response.Caches, func(i, j int) bool {
+		return response.Caches[i].Name < response.Caches[j].Name
+	})
+
+	return httpserver.JSONResponse(c, http.StatusOK, response)
+}
+
+func (a *Admin) cacheFlushHandler(c echo.Context) error {
+	name := c.Param(ParameterCache)
+
+	registeredCache, exists := a.caches[name]
+	if !exists {
+		return errors.WithMessagef(echo.ErrNotFound, "cache not found: %s", name)
+	}
+	registeredCache.Flush()
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (a *Admin) throttlesHandler(c echo.Context) error {
+	response := &ThrottlesResponse{
+		Throttles: make([]*ThrottleResponse, 0, len(a.throttles)),
+	}
+
+	for name, throttle := range a.throttles {
+		response.Throttles = append(response.Throttles, &ThrottleResponse{
+			Name:           name,
+			ThrottleStatus: throttle.Status(),
+		})
+	}
+
+	sort.Slice(response.Throttles, func(i, j int) bool {
+		return response.Throttles[i].Name < response.Throttles[j].Name
+	})
+
+	return httpserver.JSONResponse(c, http.StatusOK, response)
+}
+
+func (a *Admin) throttle(c echo.Context) (*journal.Throttle, error) {
+	name := c.Param(ParameterThrottle)
+
+	throttle, exists := a.throttles[name]
+	if !exists {
+		return nil, errors.WithMessagef(echo.ErrNotFound, "throttle not found: %s", name)
+	}
+
+	return throttle, nil
+}
+
+func (a *Admin) throttlePauseHandler(c echo.Context) error {
+	throttle, err := a.throttle(c)
+	if err != nil {
+		return err
+	}
+	throttle.Pause()
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (a *Admin) throttleResumeHandler(c echo.Context) error {
+	throttle, err := a.throttle(c)
+	if err != nil {
+		return err
+	}
+	throttle.Resume()
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (a *Admin) throttleRateHandler(c echo.Context) error {
+	throttle, err := a.throttle(c)
+	if err != nil {
+		return err
+	}
+
+	rateParam := c.QueryParam(QueryParameterMilestonesPerSecond)
+	milestonesPerSecond, err := strconv.ParseFloat(rateParam, 64)
+	if err != nil || milestonesPerSecond < 0 {
+		return errors.WithMessagef(httpserver.ErrInvalidParameter, "invalid %s: %s", QueryParameterMilestonesPerSecond, rateParam)
+	}
+	throttle.SetRate(milestonesPerSecond)
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (a *Admin) configHandler(c echo.Context) error {
+	if a.config == nil {
+		return errors.WithMessage(echo.ErrNotFound, "configuration not available")
+	}
+
+	return httpserver.JSONResponse(c, http.StatusOK, a.redactedConfig())
+}
+
+// redactedConfig returns the flattened configuration with the masked keys and sensitive looking keys redacted.
+func (a *Admin) redactedConfig() map[string]interface{} {
+	settings := a.config.All()
+
+	for key := range settings {
+		if a.redacted(strings.ToLower(key)) {
+			settings[key] = httpserver.RedactedValue
+		}
+	}
+
+	return settings
+}
+
+func (a *Admin) redacted(key string) bool {
+	for _, maskedKey := range a.maskedKeys {
+		maskedKey = strings.ToLower(maskedKey)
+		if key == maskedKey || strings.HasPrefix(key, maskedKey+".") {
+			return true
+		}
+	}
+
+	lastSegment := key[strings.LastIndex(key, ".")+1:]
+	for _, keyword := range DefaultRedactedConfigKeywords {
+		if strings.Contains(lastSegment, keyword) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,139 @@
+package backpressure
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// SourcePubSub is the source of messages dropped by the pub/sub bus.
+	SourcePubSub = "pubsub"
+	// SourceWebSocket is the source of messages dropped or delayed for websocket clients.
+	SourceWebSocket = "websocket"
+	// SourceWebhook is the source of deliveries dropped or delayed for webhook targets.
+	SourceWebhook = "webhook"
+)
+
+// Stats are the counters of a single source.
+type Stats struct {
+	// Dropped is the amount of events that were dropped because the consumer was too slow.
+	Dropped uint64 `json:"dropped"`
+	// Delayed is the amount of events that were delivered late because the consumer was too slow.
+	Delayed uint64 `json:"delayed"`
+}
+
+type sourceCounters struct {
+	dropped uint64
+	delayed uint64
+}
+
+// Metrics counts events that were dropped or delayed due to slow consumers, per source.
+// It allows operators to distinguish node problems from app-side congestion.
+// Metrics implements prometheus.Collector, so it can be registered directly.
+type Metrics struct {
+	sourcesMutex sync.RWMutex
+	sources      map[string]*sourceCounters
+
+	droppedDesc *prometheus.Desc
+	delayedDesc *prometheus.Desc
+}
+
+// NewMetrics creates new Metrics. The namespace is used as prefix of the prometheus metric names.
+func NewMetrics(namespace string) *Metrics {
+	return &Metrics{
+		sources: make(map[string]*sourceCounters),
+		droppedDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "backpressure", "events_dropped_total"),
+			"The total amount of events dropped because of slow consumers.",
+			[]string{"source"},
+			nil,
+		),
+		delayedDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "backpressure", "events_delayed_total"),
+			"The total amount of events delayed because of slow consumers.",
+			[]string{"source"},
+			nil,
+		),
+	}
+}
+
+func (m *Metrics) source(source string) *sourceCounters {
+	m.sourcesMutex.RLock()
+	counters, exists := m.sources[source]
+	m.sourcesMutex.RUnlock()
+
+	if exists {
+		return counters
+	}
+
+	m.sourcesMutex.Lock()
+	defer m.sourcesMutex.Unlock()
+
+	if counters, exists = m.sources[source]; !exists {
+		counters = &sourceCounters{}
+		m.sources[source] = counters
+	}
+
+	return counters
+}
+
+// Dropped records that an event of the given source was dropped.
+// It is safe to call on nil Metrics.
+func (m *Metrics) Dropped(source string) {
+	if m == nil {
+		return
+	}
+
+	atomic.AddUint64(&m.source(source).dropped, 1)
+}
+
+// Delayed records that an event of the given source was delayed.
+// It is safe to call on nil Metrics.
+func (m *Metrics) Delayed(source string) {
+	if m == nil {
+		return
+	}
+
+	atomic.AddUint64(&m.source(source).delayed, 1)
+}
+
+// Stats returns the counters of all sources.
+func (m *Metrics) Stats() map[string]Stats {
+	m.sourcesMutex.RLock()
+	defer m.sourcesMutex.RUnlock()
+
+	stats := make(map[string]Stats, len(m.sources))
+	for source, counters := range m.sources {
+		stats[source] = Stats{
+			Dropped: atomic.LoadUint64(&counters.dropped),
+			Delayed: atomic.LoadUint64(&counters.delayed),
+		}
+	}
+
+	return stats
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- m.droppedDesc
+	ch <- m.delayedDesc
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	stats := m.Stats()
+
+	sources := make([]string, 0, len(stats))
+	for source := range stats {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	for _, source := range sources {
+		ch <- prometheus.MustNewConstMetric(m.droppedDesc, prometheus.CounterValue, float64(stats[source].Dropped), source)
+		ch <- prometheus.MustNewConstMetric(m.delayedDesc, prometheus.CounterValue, float64(stats[source].Delayed), source)
+	}
+}
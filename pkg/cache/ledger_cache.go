@@ -0,0 +1,360 @@
+package cache
+
+import (
+	"context"
+	"sync"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/hive.go/core/generics/options"
+	lrucache "github.com/iotaledger/hive.go/core/lru_cache"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+	"github.com/iotaledger/inx-app/pkg/workerpool"
+	inx "github.com/iotaledger/inx/go"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// EntryKind is the kind of a cached object.
+type EntryKind byte
+
+const (
+	// EntryKindOutput is a cached output.
+	EntryKindOutput EntryKind = iota
+	// EntryKindBlock is a cached block.
+	EntryKindBlock
+)
+
+// Entry describes a cached object for the pruning policies.
+type Entry struct {
+	// Kind is the kind of the cached object.
+	Kind EntryKind
+	// CachedIndex is the confirmed milestone index at the time the object was cached.
+	CachedIndex uint32
+	// BookedIndex is the milestone index at which an output was booked, 0 for blocks.
+	BookedIndex uint32
+	// SpentIndex is the milestone index at which an output was spent, 0 if it is unspent.
+	SpentIndex uint32
+}
+
+// LedgerState is the state of the node the pruning policies are evaluated against.
+type LedgerState struct {
+	// ConfirmedIndex is the confirmed milestone index of the node.
+	ConfirmedIndex uint32
+	// TanglePruningIndex is the index below which the node pruned blocks.
+	TanglePruningIndex uint32
+	// LedgerPruningIndex is the index below which the node pruned spent outputs.
+	LedgerPruningIndex uint32
+}
+
+// PruningPolicy reports whether the entry should be evicted in the given ledger state.
+type PruningPolicy func(entry *Entry, state *LedgerState) bool
+
+// EvictSpentOlderThan evicts outputs that were spent more than the given amount of milestones ago.
+func EvictSpentOlderThan(milestones uint32) PruningPolicy {
+	return func(entry *Entry, state *LedgerState) bool {
+		return entry.Kind == EntryKindOutput && entry.SpentIndex != 0 && entry.SpentIndex+milestones < state.ConfirmedIndex
+	}
+}
+
+// EvictOlderThan evicts blocks that were cached more than the given amount of milestones ago.
+func EvictOlderThan(milestones uint32) PruningPolicy {
+	return func(entry *Entry, state *LedgerState) bool {
+		return entry.Kind == EntryKindBlock && entry.CachedIndex+milestones < state.ConfirmedIndex
+	}
+}
+
+// EvictBelowPruningIndex evicts blocks and spent outputs the node already pruned,
+// so the cache does not serve data the node can't serve anymore.
+func EvictBelowPruningIndex() PruningPolicy {
+	return func(entry *Entry, state *LedgerState) bool {
+		switch entry.Kind {
+		case EntryKindOutput:
+			return entry.SpentIndex != 0 && entry.SpentIndex < state.LedgerPruningIndex
+		case EntryKindBlock:
+			return entry.CachedIndex < state.TanglePruningIndex
+		default:
+			return false
+		}
+	}
+}
+
+// LedgerCache caches outputs and blocks fetched from the node.
+// Besides the LRU size bounds, entries are evicted by pruning policies tied to the ledger state,
+// which keeps the memory of long-running apps flat.
+type LedgerCache struct {
+	nodeBridge *nodebridge.NodeBridge
+
+	outputCacheSize    int
+	blockCacheSize     int
+	outputsConcurrency int
+	pruningPolicies    []PruningPolicy
+	outputs            *lrucache.LRUCache
+	blocks             *lrucache.LRUCache
+	entriesMutex       sync.Mutex
+	outputEntries      map[iotago.OutputID]*Entry
+	blockEntries       map[iotago.BlockID]*Entry
+	onConfirmedIndex   *events.Closure
+}
+
+// WithOutputCacheSize sets the maximum amount of cached outputs.
+func WithOutputCacheSize(size int) options.Option[LedgerCache] {
+	return func(c *LedgerCache) {
+		c.outputCacheSize = size
+	}
+}
+
+// WithBlockCacheSize sets the maximum amount of cached blocks.
+func WithBlockCacheSize(size int) options.Option[LedgerCache] {
+	return func(c *LedgerCache) {
+		c.blockCacheSize = size
+	}
+}
+
+// WithOutputsConcurrency sets the maximum amount of concurrent requests to the node in Outputs.
+func WithOutputsConcurrency(concurrency int) options.Option[LedgerCache] {
+	return func(c *LedgerCache) {
+		c.outputsConcurrency = concurrency
+	}
+}
+
+// WithPruningPolicies sets the policies that are evaluated on every confirmed milestone.
+func WithPruningPolicies(policies ...PruningPolicy) options.Option[LedgerCache] {
+	return func(c *LedgerCache) {
+		c.pruningPolicies = policies
+	}
+}
+
+// NewLedgerCache creates a new LedgerCache.
+// By default, spent outputs are evicted after 100 milestones and everything below the node's pruning index is evicted.
+func NewLedgerCache(nodeBridge *nodebridge.NodeBridge, opts ...options.Option[LedgerCache]) *LedgerCache {
+	c := options.Apply(&LedgerCache{
+		nodeBridge:         nodeBridge,
+		outputCacheSize:    10_000,
+		blockCacheSize:     10_000,
+		outputsConcurrency: 16,
+		pruningPolicies:    []PruningPolicy{EvictSpentOlderThan(100), EvictBelowPruningIndex()},
+		outputEntries:      make(map[iotago.OutputID]*Entry),
+		blockEntries:       make(map[iotago.BlockID]*Entry),
+	}, opts)
+
+	c.outputs = lrucache.NewLRUCache(c.outputCacheSize, &lrucache.Options{
+		EvictionCallback: func(key interface{}, _ interface{}) {
+			c.entriesMutex.Lock()
+			defer c.entriesMutex.Unlock()
+
+			//nolint:forcetypeassert // only output IDs are used as keys
+			delete(c.outputEntries, key.(iotago.OutputID))
+		},
+	})
+	c.blocks = lrucache.NewLRUCache(c.blockCacheSize, &lrucache.Options{
+		EvictionCallback: func(key interface{}, _ interface{}) {
+			c.entriesMutex.Lock()
+			defer c.entriesMutex.Unlock()
+
+			//nolint:forcetypeassert // only block IDs are used as keys
+			delete(c.blockEntries, key.(iotago.BlockID))
+		},
+	})
+
+	return c
+}
+
+func bookedIndex(response *inx.OutputResponse) uint32 {
+	if response.GetSpent() != nil {
+		return response.GetSpent().GetOutput().GetMilestoneIndexBooked()
+	}
+
+	return response.GetOutput().GetMilestoneIndexBooked()
+}
+
+func spentIndex(response *inx.OutputResponse) uint32 {
+	if response.GetSpent() == nil {
+		return 0
+	}
+
+	return response.GetSpent().GetMilestoneIndexSpent()
+}
+
+// Output returns the output with the given ID from the cache or the node.
+func (c *LedgerCache) Output(ctx context.Context, outputID iotago.OutputID) (*inx.OutputResponse, error) {
+	if cached := c.outputs.Get(outputID); cached != nil {
+		//nolint:forcetypeassert // only output responses are cached
+		return cached.(*inx.OutputResponse), nil
+	}
+
+	response, err := c.nodeBridge.Output(ctx, outputID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.setOutput(outputID, response)
+
+	return response, nil
+}
+
+// Outputs returns the outputs with the given IDs from the cache or the node.
+// The outputs that are not cached are fetched concurrently, bounded by the outputs concurrency.
+// Failed lookups don't fail the whole batch, their errors are returned per output ID instead.
+func (c *LedgerCache) Outputs(ctx context.Context, outputIDs ...iotago.OutputID) (map[iotago.OutputID]*inx.OutputResponse, map[iotago.OutputID]error) {
+	outputs := make(map[iotago.OutputID]*inx.OutputResponse, len(outputIDs))
+	errs := make(map[iotago.OutputID]error)
+
+	// duplicates are only fetched once
+	missing := make(map[iotago.OutputID]struct{})
+	for _, outputID := range outputIDs {
+		if cached := c.outputs.Get(outputID); cached != nil {
+			//nolint:forcetypeassert // only output responses are cached
+			outputs[outputID] = cached.(*inx.OutputResponse)
+
+			continue
+		}
+		missing[outputID] = struct{}{}
+	}
+
+	var resultsMutex sync.Mutex
+	group := workerpool.NewGroup(c.outputsConcurrency)
+
+	for outputID := range missing {
+		outputID := outputID
+		if err := group.Go(ctx, func() {
+			response, err := c.Output(ctx, outputID)
+
+			resultsMutex.Lock()
+			defer resultsMutex.Unlock()
+
+			if err != nil {
+				errs[outputID] = err
+
+				return
+			}
+			outputs[outputID] = response
+		}); err != nil {
+			resultsMutex.Lock()
+			errs[outputID] = err
+			resultsMutex.Unlock()
+		}
+	}
+	group.Wait()
+
+	return outputs, errs
+}
+
+func (c *LedgerCache) setOutput(outputID iotago.OutputID, response *inx.OutputResponse) {
+	c.entriesMutex.Lock()
+	c.outputEntries[outputID] = &Entry{
+		Kind:        EntryKindOutput,
+		CachedIndex: c.nodeBridge.ConfirmedMilestoneIndex(),
+		BookedIndex: bookedIndex(response),
+		SpentIndex:  spentIndex(response),
+	}
+	c.entriesMutex.Unlock()
+
+	c.outputs.Set(outputID, response)
+}
+
+// Block returns the block with the given ID from the cache or the node.
+func (c *LedgerCache) Block(ctx context.Context, blockID iotago.BlockID) (*iotago.Block, error) {
+	if cached := c.blocks.Get(blockID); cached != nil {
+		//nolint:forcetypeassert // only blocks are cached
+		return cached.(*iotago.Block), nil
+	}
+
+	block, err := c.nodeBridge.Block(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.entriesMutex.Lock()
+	c.blockEntries[blockID] = &Entry{
+		Kind:        EntryKindBlock,
+		CachedIndex: c.nodeBridge.ConfirmedMilestoneIndex(),
+	}
+	c.entriesMutex.Unlock()
+
+	c.blocks.Set(blockID, block)
+
+	return block, nil
+}
+
+// ConsumeLedgerUpdate marks the cached outputs that were consumed by the ledger update as spent,
+// so they are served with their spent information and can be evicted by the pruning policies.
+func (c *LedgerCache) ConsumeLedgerUpdate(update *nodebridge.LedgerUpdate) error {
+	for _, spent := range update.Consumed {
+		outputID := spent.GetOutput().UnwrapOutputID()
+		if !c.outputs.Contains(outputID) {
+			continue
+		}
+
+		c.setOutput(outputID, &inx.OutputResponse{
+			LedgerIndex: update.MilestoneIndex,
+			//nolint:nosnakecase // grpc uses underscores
+			Payload: &inx.OutputResponse_Spent{Spent: spent},
+		})
+	}
+
+	return nil
+}
+
+// Prune evaluates the pruning policies against the current state of the node and evicts the matching entries.
+func (c *LedgerCache) Prune() {
+	nodeStatus := c.nodeBridge.NodeStatus()
+	state := &LedgerState{
+		ConfirmedIndex:     c.nodeBridge.ConfirmedMilestoneIndex(),
+		TanglePruningIndex: nodeStatus.GetTanglePruningIndex(),
+		LedgerPruningIndex: nodeStatus.GetLedgerPruningIndex(),
+	}
+
+	evict := func(entry *Entry) bool {
+		for _, policy := range c.pruningPolicies {
+			if policy(entry, state) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	c.entriesMutex.Lock()
+	outputIDs := make([]iotago.OutputID, 0)
+	for outputID, entry := range c.outputEntries {
+		if evict(entry) {
+			outputIDs = append(outputIDs, outputID)
+		}
+	}
+	blockIDs := make([]iotago.BlockID, 0)
+	for blockID, entry := range c.blockEntries {
+		if evict(entry) {
+			blockIDs = append(blockIDs, blockID)
+		}
+	}
+	c.entriesMutex.Unlock()
+
+	// the eviction callbacks remove the entries
+	for _, outputID := range outputIDs {
+		c.outputs.Delete(outputID)
+	}
+	for _, blockID := range blockIDs {
+		c.blocks.Delete(blockID)
+	}
+}
+
+// Size returns the amount of cached outputs and blocks.
+func (c *LedgerCache) Size() int {
+	return c.outputs.GetSize() + c.blocks.GetSize()
+}
+
+// Flush evicts all cached outputs and blocks.
+func (c *LedgerCache) Flush() {
+	c.outputs.DeleteAll()
+	c.blocks.DeleteAll()
+}
+
+// Run prunes the cache on every confirmed milestone until the given context is done.
+func (c *LedgerCache) Run(ctx context.Context) {
+	c.onConfirmedIndex = events.NewClosure(func(_ *nodebridge.Milestone) {
+		c.Prune()
+	})
+
+	c.nodeBridge.Events.ConfirmedMilestoneChanged.Hook(c.onConfirmedIndex)
+	<-ctx.Done()
+	c.nodeBridge.Events.ConfirmedMilestoneChanged.Detach(c.onConfirmedIndex)
+}
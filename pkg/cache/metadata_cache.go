@@ -0,0 +1,247 @@
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/hive.go/core/generics/options"
+	lrucache "github.com/iotaledger/hive.go/core/lru_cache"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+	inx "github.com/iotaledger/inx/go"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// MetadataStats are the counters of a MetadataCache.
+type MetadataStats struct {
+	// Hits is the amount of lookups served from the cache.
+	Hits uint64 `json:"hits"`
+	// Misses is the amount of lookups of blocks that were not cached.
+	Misses uint64 `json:"misses"`
+	// Stale is the amount of lookups of cached metadata that was refetched because it exceeded the max age.
+	Stale uint64 `json:"stale"`
+}
+
+// HitRate returns the ratio of lookups served from the cache, 0 if there were no lookups.
+func (s MetadataStats) HitRate() float64 {
+	total := s.Hits + s.Misses + s.Stale
+	if total == 0 {
+		return 0
+	}
+
+	return float64(s.Hits) / float64(total)
+}
+
+type cachedMetadata struct {
+	metadata  *inx.BlockMetadata
+	fetchedAt time.Time
+}
+
+// final reports whether the metadata can't change anymore, which is the case once the block got referenced.
+func (c *cachedMetadata) final() bool {
+	return c.metadata.GetReferencedByMilestoneIndex() != 0
+}
+
+// MetadataCache caches the metadata of blocks fetched from the node.
+// Metadata of referenced blocks never changes, so it is served until it is evicted from the LRU.
+// Metadata of unreferenced blocks is refetched once it is older than the max age.
+// MetadataCache implements prometheus.Collector, so it can be registered directly.
+type MetadataCache struct {
+	nodeBridge *nodebridge.NodeBridge
+
+	cacheSize int
+	maxAge    time.Duration
+	cache     *lrucache.LRUCache
+
+	hits   uint64
+	misses uint64
+	stale  uint64
+
+	hitsDesc   *prometheus.Desc
+	missesDesc *prometheus.Desc
+	staleDesc  *prometheus.Desc
+}
+
+// WithMetadataCacheSize sets the maximum amount of cached metadata.
+func WithMetadataCacheSize(size int) options.Option[MetadataCache] {
+	return func(c *MetadataCache) {
+		c.cacheSize = size
+	}
+}
+
+// WithMetadataMaxAge sets the duration after which the metadata of unreferenced blocks is refetched.
+func WithMetadataMaxAge(maxAge time.Duration) options.Option[MetadataCache] {
+	return func(c *MetadataCache) {
+		c.maxAge = maxAge
+	}
+}
+
+// NewMetadataCache creates a new MetadataCache. The namespace is used as prefix of the prometheus metric names.
+func NewMetadataCache(nodeBridge *nodebridge.NodeBridge, namespace string, opts ...options.Option[MetadataCache]) *MetadataCache {
+	c := options.Apply(&MetadataCache{
+		nodeBridge: nodeBridge,
+		cacheSize:  10_000,
+		maxAge:     time.Second,
+		hitsDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "metadata_cache", "hits_total"),
+			"The total amount of block metadata lookups served from the cache.",
+			nil,
+			nil,
+		),
+		missesDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "metadata_cache", "misses_total"),
+			"The total amount of block metadata lookups of blocks that were not cached.",
+			nil,
+			nil,
+		),
+		staleDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "metadata_cache", "stale_total"),
+			"The total amount of block metadata lookups that were refetched because the cached metadata was stale.",
+			nil,
+			nil,
+		),
+	}, opts)
+
+	c.cache = lrucache.NewLRUCache(c.cacheSize)
+
+	return c
+}
+
+// BlockMetadata returns the metadata of the block with the given ID from the cache or the node.
+func (c *MetadataCache) BlockMetadata(ctx context.Context, blockID iotago.BlockID) (*inx.BlockMetadata, error) {
+	if cached := c.cache.Get(blockID); cached != nil {
+		//nolint:forcetypeassert // only cached metadata is stored
+		entry := cached.(*cachedMetadata)
+		if entry.final() || time.Since(entry.fetchedAt) < c.maxAge {
+			atomic.AddUint64(&c.hits, 1)
+
+			return entry.metadata, nil
+		}
+		atomic.AddUint64(&c.stale, 1)
+	} else {
+		atomic.AddUint64(&c.misses, 1)
+	}
+
+	metadata, err := c.nodeBridge.BlockMetadata(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+	c.Update(metadata)
+
+	return metadata, nil
+}
+
+// Update stores metadata received from other sources, e.g. the solid blocks stream.
+func (c *MetadataCache) Update(metadata *inx.BlockMetadata) {
+	c.cache.Set(metadata.UnwrapBlockID(), &cachedMetadata{
+		metadata:  metadata,
+		fetchedAt: time.Now(),
+	})
+}
+
+// Stats returns the counters of the cache.
+func (c *MetadataCache) Stats() MetadataStats {
+	return MetadataStats{
+		Hits:   atomic.LoadUint64(&c.hits),
+		Misses: atomic.LoadUint64(&c.misses),
+		Stale:  atomic.LoadUint64(&c.stale),
+	}
+}
+
+// Size returns the amount of cached metadata.
+func (c *MetadataCache) Size() int {
+	return c.cache.GetSize()
+}
+
+// Flush evicts all cached metadata, the counters are kept.
+func (c *MetadataCache) Flush() {
+	c.cache.DeleteAll()
+}
+
+// Describe implements prometheus.Collector.
+func (c *MetadataCache) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hitsDesc
+	ch <- c.missesDesc
+	ch <- c.staleDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *MetadataCache) Collect(ch chan<- prometheus.Metric) {
+	stats := c.Stats()
+
+	ch <- prometheus.MustNewConstMetric(c.hitsDesc, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(c.missesDesc, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(c.staleDesc, prometheus.CounterValue, float64(stats.Stale))
+}
+
+// WaitForBlockConfirmation blocks until the block with the given ID got referenced by a milestone
+// and returns its final metadata. The metadata is checked on every confirmed milestone.
+func (c *MetadataCache) WaitForBlockConfirmation(ctx context.Context, blockID iotago.BlockID) (*inx.BlockMetadata, error) {
+	confirmed := make(chan struct{}, 1)
+	onConfirmedMilestone := events.NewClosure(func(_ *nodebridge.Milestone) {
+		select {
+		case confirmed <- struct{}{}:
+		default:
+		}
+	})
+
+	c.nodeBridge.Events.ConfirmedMilestoneChanged.Hook(onConfirmedMilestone)
+	defer c.nodeBridge.Events.ConfirmedMilestoneChanged.Detach(onConfirmedMilestone)
+
+	for {
+		metadata, err := c.BlockMetadata(ctx, blockID)
+		if err != nil {
+			return nil, err
+		}
+		if metadata.GetReferencedByMilestoneIndex() != 0 {
+			return metadata, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-confirmed:
+		}
+	}
+}
+
+// TraversePastCone walks the past cone of the given block in breadth-first order using the cached metadata.
+// The condition decides whether the parents of a block are visited, e.g. to stop at referenced blocks.
+// The consumer is called for every visited block, the traversal stops if it returns an error.
+func (c *MetadataCache) TraversePastCone(ctx context.Context, startBlockID iotago.BlockID, condition func(metadata *inx.BlockMetadata) bool, consumer func(metadata *inx.BlockMetadata) error) error {
+	visited := map[iotago.BlockID]struct{}{startBlockID: {}}
+	queue := iotago.BlockIDs{startBlockID}
+
+	for len(queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		blockID := queue[0]
+		queue = queue[1:]
+
+		metadata, err := c.BlockMetadata(ctx, blockID)
+		if err != nil {
+			return err
+		}
+		if err := consumer(metadata); err != nil {
+			return err
+		}
+		if !condition(metadata) {
+			continue
+		}
+
+		for _, parent := range metadata.UnwrapParents() {
+			if _, seen := visited[parent]; seen {
+				continue
+			}
+			visited[parent] = struct{}{}
+			queue = append(queue, parent)
+		}
+	}
+
+	return nil
+}
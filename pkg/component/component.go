@@ -0,0 +1,125 @@
+package component
+
+import (
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/dig"
+
+	"github.com/iotaledger/hive.go/core/app"
+	"github.com/iotaledger/hive.go/core/app/pkg/shutdown"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+)
+
+// Dependencies are the dependencies that are injected into every component.
+type Dependencies struct {
+	dig.In
+	NodeBridge      *nodebridge.NodeBridge
+	Echo            *echo.Echo                `optional:"true"`
+	ShutdownHandler *shutdown.ShutdownHandler `optional:"true"`
+}
+
+// Context is passed to the Configure and Run hooks of a component.
+// It gives access to the logger, the daemon and the injected dependencies.
+type Context struct {
+	*app.Plugin
+	Dependencies
+
+	subsystems map[string]*ParametersComponent
+}
+
+// ParametersComponent are the parameters every component gets registered under its identifier.
+type ParametersComponent struct {
+	Enabled bool `usage:"whether the component is enabled"`
+}
+
+// Definition describes a component.
+type Definition struct {
+	// The name of the component.
+	Name string
+	// Whether the component is enabled if not configured otherwise.
+	Enabled bool
+	// The additional config parameters of the component.
+	// The identifier of the component must not be used as a namespace, it is reserved for the ParametersComponent.
+	Params map[string]any
+	// The configuration values to mask.
+	Masked []string
+	// The optional subsystems of the component by name, with whether they are enabled if not configured otherwise.
+	// Every subsystem can be toggled via the "<identifier>.<name>.enabled" config parameter, see NewSubsystem.
+	Subsystems map[string]bool
+	// Provide gets called in the provide stage of app initialization (enabled components only).
+	Provide func(c *dig.Container) error
+	// Configure gets called in the configure stage of app initialization (enabled components only).
+	Configure func(ctx *Context) error
+	// Run gets called in the run stage of app initialization (enabled components only).
+	Run func(ctx *Context) error
+}
+
+// NewPlugin creates a hive.go app plugin from the given definition.
+// The plugin can be toggled via the "<identifier>.enabled" config parameter, its subsystems via "<identifier>.<name>.enabled".
+func NewPlugin(def *Definition) *app.Plugin {
+	componentParams := &ParametersComponent{
+		Enabled: def.Enabled,
+	}
+
+	plugin := &app.Plugin{
+		Component: &app.Component{
+			Name: def.Name,
+		},
+	}
+	identifier := plugin.Identifier()
+
+	params := map[string]any{
+		identifier: componentParams,
+	}
+	for namespace, pointerToStruct := range def.Params {
+		if namespace == identifier {
+			panic(fmt.Sprintf("component \"%s\" uses the reserved namespace \"%s\" for its parameters", def.Name, identifier))
+		}
+		params[namespace] = pointerToStruct
+	}
+
+	ctx := &Context{
+		Plugin:     plugin,
+		subsystems: make(map[string]*ParametersComponent, len(def.Subsystems)),
+	}
+
+	for name, enabled := range def.Subsystems {
+		namespace := identifier + "." + name
+		if _, exists := params[namespace]; exists {
+			panic(fmt.Sprintf("subsystem \"%s\" of component \"%s\" uses the namespace \"%s\" of other parameters", name, def.Name, namespace))
+		}
+
+		subsystemParams := &ParametersComponent{
+			Enabled: enabled,
+		}
+		params[namespace] = subsystemParams
+		ctx.subsystems[name] = subsystemParams
+	}
+
+	plugin.Params = &app.ComponentParams{
+		Params: params,
+		Masked: def.Masked,
+	}
+	plugin.IsEnabled = func() bool {
+		return componentParams.Enabled
+	}
+	plugin.DepsFunc = func(deps Dependencies) {
+		ctx.Dependencies = deps
+	}
+	plugin.Provide = def.Provide
+
+	if def.Configure != nil {
+		plugin.Configure = func() error {
+			return def.Configure(ctx)
+		}
+	}
+
+	if def.Run != nil {
+		plugin.Run = func() error {
+			return def.Run(ctx)
+		}
+	}
+
+	return plugin
+}
@@ -0,0 +1,85 @@
+package component
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrSubsystemDisabled is returned when a disabled subsystem is accessed.
+var ErrSubsystemDisabled = errors.New("subsystem disabled")
+
+// SubsystemEnabled returns whether the subsystem with the given name is enabled.
+// Subsystems that are not declared in the Definition of the component are disabled.
+func (c *Context) SubsystemEnabled(name string) bool {
+	subsystemParams, exists := c.subsystems[name]
+
+	return exists && subsystemParams.Enabled
+}
+
+// Subsystem is an optional part of a component, like a tracker, an exporter or the metrics,
+// that can be toggled via config and is only initialized on first use.
+// A minimal app does not pay memory or CPU for the subsystems it disables or never uses.
+type Subsystem[T any] struct {
+	ctx  *Context
+	name string
+	init func() (T, error)
+
+	mutex       sync.Mutex
+	initialized bool
+	value       T
+	err         error
+}
+
+// NewSubsystem creates a new Subsystem of the component. The name must be declared in Definition.Subsystems.
+// The init function is called once, when the enabled subsystem is accessed for the first time.
+func NewSubsystem[T any](ctx *Context, name string, init func() (T, error)) *Subsystem[T] {
+	return &Subsystem[T]{
+		ctx:  ctx,
+		name: name,
+		init: init,
+	}
+}
+
+// Name returns the name of the subsystem.
+func (s *Subsystem[T]) Name() string {
+	return s.name
+}
+
+// Enabled returns whether the subsystem is enabled by the config.
+func (s *Subsystem[T]) Enabled() bool {
+	return s.ctx.SubsystemEnabled(s.name)
+}
+
+// Get returns the subsystem, it is initialized on the first call.
+// It returns ErrSubsystemDisabled if the subsystem is disabled, and the error of the initialization if it failed.
+func (s *Subsystem[T]) Get() (T, error) {
+	if !s.Enabled() {
+		var empty T
+
+		return empty, fmt.Errorf("%w: %s", ErrSubsystemDisabled, s.name)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.initialized {
+		s.value, s.err = s.init()
+		s.initialized = true
+	}
+
+	return s.value, s.err
+}
+
+// IfInitialized calls the function with the subsystem if it was initialized successfully,
+// e.g. to stop a background worker or to collect metrics only of the subsystems that were used.
+func (s *Subsystem[T]) IfInitialized(f func(value T)) {
+	s.mutex.Lock()
+	initialized := s.initialized && s.err == nil
+	value := s.value
+	s.mutex.Unlock()
+
+	if initialized {
+		f(value)
+	}
+}
@@ -0,0 +1,270 @@
+package database
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/iotaledger/hive.go/core/kvstore"
+)
+
+const (
+	backupVersion   byte = 1
+	backupBatchSize      = 10_000
+	// maxBackupChunkLength limits the memory allocated for a single key or value of a malformed archive.
+	maxBackupChunkLength = 64 << 20
+)
+
+var backupMagic = []byte("INXAPPDB")
+
+var (
+	// ErrInvalidBackup is returned when a backup archive is malformed.
+	ErrInvalidBackup = errors.New("invalid backup")
+	// ErrDatabaseNotEmpty is returned when a backup is restored into a database that already contains data.
+	ErrDatabaseNotEmpty = errors.New("database is not empty")
+)
+
+// Backup writes all data of the database into a single compressed archive.
+// MilestoneBatch commits are blocked while the backup is written, so the archive
+// represents the state at the returned ledger index.
+func (d *Database) Backup(w io.Writer) (uint32, error) {
+	d.ledgerIndexMutex.RLock()
+	defer d.ledgerIndexMutex.RUnlock()
+
+	ledgerIndex, err := d.ledgerIndexWithoutLocking()
+	if err != nil {
+		return 0, err
+	}
+
+	gzipWriter := gzip.NewWriter(w)
+	bufferedWriter := bufio.NewWriter(gzipWriter)
+
+	header := make([]byte, len(backupMagic)+5)
+	copy(header, backupMagic)
+	header[len(backupMagic)] = backupVersion
+	binary.LittleEndian.PutUint32(header[len(backupMagic)+1:], ledgerIndex)
+	if _, err := bufferedWriter.Write(header); err != nil {
+		return 0, err
+	}
+
+	lengthBuf := make([]byte, binary.MaxVarintLen64)
+	writeChunk := func(chunk []byte) error {
+		n := binary.PutUvarint(lengthBuf, uint64(len(chunk)))
+		if _, err := bufferedWriter.Write(lengthBuf[:n]); err != nil {
+			return err
+		}
+		_, err := bufferedWriter.Write(chunk)
+
+		return err
+	}
+
+	var innerErr error
+	if err := d.store.Iterate(kvstore.EmptyPrefix, func(key kvstore.Key, value kvstore.Value) bool {
+		// the health check key is only written temporarily
+		if bytes.Equal(key, []byte{storePrefixInternal, internalKeyHealthCheck}) {
+			return true
+		}
+
+		if err := writeChunk(key); err != nil {
+			innerErr = err
+
+			return false
+		}
+		if err := writeChunk(value); err != nil {
+			innerErr = err
+
+			return false
+		}
+
+		return true
+	}); err != nil {
+		return 0, err
+	}
+	if innerErr != nil {
+		return 0, innerErr
+	}
+
+	// keys are never empty, so an empty key marks the end of the archive
+	if err := writeChunk(nil); err != nil {
+		return 0, err
+	}
+
+	if err := bufferedWriter.Flush(); err != nil {
+		return 0, err
+	}
+
+	if err := gzipWriter.Close(); err != nil {
+		return 0, err
+	}
+
+	return ledgerIndex, nil
+}
+
+// Restore reads a backup archive created with Backup into the database and returns its ledger index.
+// The database has to be empty. If the archive is malformed, the partially restored data is removed again.
+func (d *Database) Restore(r io.Reader) (uint32, error) {
+	d.ledgerIndexMutex.Lock()
+	defer d.ledgerIndexMutex.Unlock()
+
+	empty := true
+	if err := d.store.IterateKeys(kvstore.EmptyPrefix, func(_ kvstore.Key) bool {
+		empty = false
+
+		return false
+	}); err != nil {
+		return 0, err
+	}
+	if !empty {
+		return 0, ErrDatabaseNotEmpty
+	}
+
+	ledgerIndex, err := d.restoreWithoutLocking(r)
+	if err != nil {
+		// the batches that were already committed must not be mistaken for a complete database
+		if clearErr := d.store.Clear(); clearErr != nil {
+			return 0, fmt.Errorf("%w, unable to clear the partially restored database: %s", err, clearErr.Error())
+		}
+
+		return 0, err
+	}
+
+	return ledgerIndex, nil
+}
+
+func (d *Database) restoreWithoutLocking(r io.Reader) (uint32, error) {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidBackup, err.Error())
+	}
+	defer gzipReader.Close()
+
+	bufferedReader := bufio.NewReader(gzipReader)
+
+	header := make([]byte, len(backupMagic)+5)
+	if _, err := io.ReadFull(bufferedReader, header); err != nil {
+		return 0, fmt.Errorf("%w: unable to read header: %s", ErrInvalidBackup, err.Error())
+	}
+	if !bytes.Equal(header[:len(backupMagic)], backupMagic) {
+		return 0, fmt.Errorf("%w: unknown file format", ErrInvalidBackup)
+	}
+	if header[len(backupMagic)] != backupVersion {
+		return 0, fmt.Errorf("%w: unsupported version %d", ErrInvalidBackup, header[len(backupMagic)])
+	}
+	ledgerIndex := binary.LittleEndian.Uint32(header[len(backupMagic)+1:])
+
+	readChunk := func() ([]byte, error) {
+		length, err := binary.ReadUvarint(bufferedReader)
+		if err != nil {
+			return nil, err
+		}
+		if length > maxBackupChunkLength {
+			return nil, fmt.Errorf("chunk length %d exceeds the maximum of %d", length, maxBackupChunkLength)
+		}
+		chunk := make([]byte, length)
+		if _, err := io.ReadFull(bufferedReader, chunk); err != nil {
+			return nil, err
+		}
+
+		return chunk, nil
+	}
+
+	batch, err := d.store.Batched()
+	if err != nil {
+		return 0, err
+	}
+
+	batchCount := 0
+	for {
+		key, err := readChunk()
+		if err != nil {
+			batch.Cancel()
+
+			return 0, fmt.Errorf("%w: unable to read key: %s", ErrInvalidBackup, err.Error())
+		}
+		if len(key) == 0 {
+			break
+		}
+
+		value, err := readChunk()
+		if err != nil {
+			batch.Cancel()
+
+			return 0, fmt.Errorf("%w: unable to read value: %s", ErrInvalidBackup, err.Error())
+		}
+
+		if err := batch.Set(key, value); err != nil {
+			batch.Cancel()
+
+			return 0, err
+		}
+
+		batchCount++
+		if batchCount >= backupBatchSize {
+			if err := batch.Commit(); err != nil {
+				return 0, err
+			}
+			if batch, err = d.store.Batched(); err != nil {
+				return 0, err
+			}
+			batchCount = 0
+		}
+	}
+
+	if err := batch.Commit(); err != nil {
+		return 0, err
+	}
+
+	if err := d.store.Flush(); err != nil {
+		return 0, err
+	}
+
+	return ledgerIndex, nil
+}
+
+// BackupToFile writes a backup archive of the database to the given file path.
+func (d *Database) BackupToFile(filePath string) (uint32, error) {
+	tempFilePath := filePath + ".tmp"
+
+	file, err := os.OpenFile(tempFilePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return 0, err
+	}
+
+	ledgerIndex, err := d.Backup(file)
+	if err != nil {
+		_ = file.Close()
+		_ = os.Remove(tempFilePath)
+
+		return 0, err
+	}
+
+	if err := file.Close(); err != nil {
+		_ = os.Remove(tempFilePath)
+
+		return 0, err
+	}
+
+	// the archive is only moved to the final path if it was written completely
+	if err := os.Rename(tempFilePath, filePath); err != nil {
+		return 0, err
+	}
+
+	return ledgerIndex, nil
+}
+
+// RestoreFromFile restores a backup archive from the given file path into the database.
+func (d *Database) RestoreFromFile(filePath string) (uint32, error) {
+	//nolint:gosec // the file path is given by the operator
+	file, err := os.Open(filePath)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	return d.Restore(file)
+}
@@ -0,0 +1,216 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/iotaledger/hive.go/core/kvstore"
+	"github.com/iotaledger/hive.go/core/kvstore/mapdb"
+	"github.com/iotaledger/hive.go/core/kvstore/pebble"
+	"github.com/iotaledger/hive.go/core/logger"
+)
+
+// Engine is a database engine.
+type Engine string
+
+const (
+	// EngineMapDB is an in-memory database, its content is lost on shutdown.
+	EngineMapDB Engine = "mapdb"
+	// EnginePebble is a persistent database.
+	EnginePebble Engine = "pebble"
+)
+
+const (
+	storePrefixInternal  byte = 0
+	storePrefixNamespace byte = 1
+
+	internalKeyLedgerIndex byte = 0
+	internalKeyHealthCheck byte = 1
+)
+
+var (
+	// ErrUnknownEngine is returned when an unsupported database engine is configured.
+	ErrUnknownEngine = errors.New("unknown database engine")
+	// ErrInvalidNamespace is returned when a namespace name is invalid.
+	ErrInvalidNamespace = errors.New("invalid namespace")
+	// ErrHealthCheckFailed is returned when the database can't be written or read.
+	ErrHealthCheckFailed = errors.New("database health check failed")
+)
+
+// Database is the storage layer for all stateful subsystems of an app.
+// The subsystems get their own namespace, the writes of all namespaces that belong
+// to the same milestone can be applied atomically by using a MilestoneBatch.
+type Database struct {
+	// the logger used to log events.
+	*logger.WrappedLogger
+
+	engine Engine
+	store  kvstore.KVStore
+
+	// is used to serialize MilestoneBatch commits and ledger index reads.
+	ledgerIndexMutex sync.RWMutex
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// StoreWithEngine creates a kvstore with the given engine at the given path.
+func StoreWithEngine(path string, engine Engine) (kvstore.KVStore, error) {
+	switch engine {
+	case EngineMapDB:
+		return mapdb.NewMapDB(), nil
+
+	case EnginePebble:
+		db, err := pebble.CreateDB(path)
+		if err != nil {
+			return nil, err
+		}
+
+		return pebble.New(db), nil
+
+	default:
+		return nil, fmt.Errorf("%w: \"%s\"", ErrUnknownEngine, engine)
+	}
+}
+
+// New creates a new Database with the given engine at the given path.
+func New(log *logger.Logger, path string, engine Engine) (*Database, error) {
+	store, err := StoreWithEngine(path, engine)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWithStore(log, store, engine), nil
+}
+
+// NewWithStore creates a new Database on top of an existing kvstore.
+func NewWithStore(log *logger.Logger, store kvstore.KVStore, engine Engine) *Database {
+	return &Database{
+		WrappedLogger: logger.NewWrappedLogger(log),
+		engine:        engine,
+		store:         store,
+	}
+}
+
+// Engine returns the engine of the database.
+func (d *Database) Engine() Engine {
+	return d.engine
+}
+
+// KVStore returns the underlying kvstore.
+// Subsystems should use their own Namespace instead.
+func (d *Database) KVStore() kvstore.KVStore {
+	return d.store
+}
+
+func namespaceRealm(name string) (kvstore.Realm, error) {
+	if name == "" || bytes.IndexByte([]byte(name), 0) >= 0 {
+		return nil, fmt.Errorf("%w: \"%s\"", ErrInvalidNamespace, name)
+	}
+
+	// the terminating zero byte prevents namespaces from being prefixes of each other
+	realm := make([]byte, 0, len(name)+2)
+	realm = append(realm, storePrefixNamespace)
+	realm = append(realm, name...)
+	realm = append(realm, 0)
+
+	return realm, nil
+}
+
+// Namespace returns the kvstore of the namespace with the given name.
+func (d *Database) Namespace(name string) (kvstore.KVStore, error) {
+	realm, err := namespaceRealm(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.store.WithRealm(realm)
+}
+
+// LedgerIndex returns the index of the last milestone that was committed with a MilestoneBatch.
+// It returns 0 if no milestone was committed yet.
+func (d *Database) LedgerIndex() (uint32, error) {
+	d.ledgerIndexMutex.RLock()
+	defer d.ledgerIndexMutex.RUnlock()
+
+	return d.ledgerIndexWithoutLocking()
+}
+
+func (d *Database) ledgerIndexWithoutLocking() (uint32, error) {
+	value, err := d.store.Get([]byte{storePrefixInternal, internalKeyLedgerIndex})
+	if err != nil {
+		if errors.Is(err, kvstore.ErrKeyNotFound) {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	if len(value) != 4 {
+		return 0, fmt.Errorf("invalid ledger index length: %d", len(value))
+	}
+
+	return binary.LittleEndian.Uint32(value), nil
+}
+
+// CheckHealth checks whether the database can be written and read.
+func (d *Database) CheckHealth() error {
+	key := []byte{storePrefixInternal, internalKeyHealthCheck}
+	value := []byte(d.engine)
+
+	if err := d.store.Set(key, value); err != nil {
+		return fmt.Errorf("%w: write failed: %s", ErrHealthCheckFailed, err.Error())
+	}
+
+	readValue, err := d.store.Get(key)
+	if err != nil {
+		return fmt.Errorf("%w: read failed: %s", ErrHealthCheckFailed, err.Error())
+	}
+
+	if !bytes.Equal(readValue, value) {
+		return fmt.Errorf("%w: read value does not match written value", ErrHealthCheckFailed)
+	}
+
+	if err := d.store.Delete(key); err != nil {
+		return fmt.Errorf("%w: delete failed: %s", ErrHealthCheckFailed, err.Error())
+	}
+
+	return nil
+}
+
+// Flush persists all pending writes.
+func (d *Database) Flush() error {
+	return d.store.Flush()
+}
+
+// Close flushes and closes the database.
+// It is safe to call Close multiple times.
+func (d *Database) Close() error {
+	d.closeOnce.Do(func() {
+		if err := d.store.Flush(); err != nil {
+			d.closeErr = err
+
+			return
+		}
+		d.closeErr = d.store.Close()
+	})
+
+	return d.closeErr
+}
+
+// Run closes the database once the given context is done, so all pending writes are persisted on shutdown.
+func (d *Database) Run(ctx context.Context) {
+	<-ctx.Done()
+
+	d.LogInfo("Flushing and closing database ...")
+	if err := d.Close(); err != nil {
+		d.LogErrorf("Flushing and closing database ... failed: %s", err)
+
+		return
+	}
+	d.LogInfo("Flushing and closing database ... done")
+}
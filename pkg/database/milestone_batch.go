@@ -0,0 +1,129 @@
+package database
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/iotaledger/hive.go/core/kvstore"
+)
+
+var (
+	// ErrMilestoneBatchOutOfOrder is returned when a milestone batch does not directly follow the current ledger index.
+	ErrMilestoneBatchOutOfOrder = errors.New("milestone batch out of order")
+	// ErrMilestoneBatchDone is returned when a milestone batch is used after it was committed or canceled.
+	ErrMilestoneBatchDone = errors.New("milestone batch already committed or canceled")
+)
+
+// MilestoneBatch collects the writes of all namespaces that belong to a single milestone.
+// The writes and the new ledger index are applied atomically on commit.
+type MilestoneBatch struct {
+	database       *Database
+	milestoneIndex uint32
+	mutations      kvstore.BatchedMutations
+	done           bool
+}
+
+// NewMilestoneBatch creates a batch for the milestone with the given index.
+func (d *Database) NewMilestoneBatch(milestoneIndex uint32) (*MilestoneBatch, error) {
+	mutations, err := d.store.Batched()
+	if err != nil {
+		return nil, err
+	}
+
+	return &MilestoneBatch{
+		database:       d,
+		milestoneIndex: milestoneIndex,
+		mutations:      mutations,
+	}, nil
+}
+
+// MilestoneIndex returns the index of the milestone the batch belongs to.
+func (b *MilestoneBatch) MilestoneIndex() uint32 {
+	return b.milestoneIndex
+}
+
+func namespacedKey(namespace string, key kvstore.Key) (kvstore.Key, error) {
+	realm, err := namespaceRealm(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(realm, key...), nil
+}
+
+// Set sets the key of the given namespace to the value.
+func (b *MilestoneBatch) Set(namespace string, key kvstore.Key, value kvstore.Value) error {
+	if b.done {
+		return ErrMilestoneBatchDone
+	}
+
+	namespaced, err := namespacedKey(namespace, key)
+	if err != nil {
+		return err
+	}
+
+	return b.mutations.Set(namespaced, value)
+}
+
+// Delete deletes the key of the given namespace.
+func (b *MilestoneBatch) Delete(namespace string, key kvstore.Key) error {
+	if b.done {
+		return ErrMilestoneBatchDone
+	}
+
+	namespaced, err := namespacedKey(namespace, key)
+	if err != nil {
+		return err
+	}
+
+	return b.mutations.Delete(namespaced)
+}
+
+// Cancel discards all writes of the batch.
+func (b *MilestoneBatch) Cancel() {
+	if b.done {
+		return
+	}
+	b.done = true
+	b.mutations.Cancel()
+}
+
+// Commit applies all writes of the batch and sets the ledger index of the database to the milestone index.
+// The milestone index must directly follow the current ledger index, unless the database is empty.
+func (b *MilestoneBatch) Commit() error {
+	if b.done {
+		return ErrMilestoneBatchDone
+	}
+
+	b.database.ledgerIndexMutex.Lock()
+	defer b.database.ledgerIndexMutex.Unlock()
+
+	value, err := b.database.store.Get([]byte{storePrefixInternal, internalKeyLedgerIndex})
+	if err != nil && !errors.Is(err, kvstore.ErrKeyNotFound) {
+		b.Cancel()
+
+		return err
+	}
+
+	if len(value) == 4 {
+		if ledgerIndex := binary.LittleEndian.Uint32(value); b.milestoneIndex != ledgerIndex+1 {
+			b.Cancel()
+
+			return fmt.Errorf("%w: ledger index %d, milestone index %d", ErrMilestoneBatchOutOfOrder, ledgerIndex, b.milestoneIndex)
+		}
+	}
+
+	ledgerIndexBytes := make([]byte, 4)
+	binary.LittleEndian.PutUint32(ledgerIndexBytes, b.milestoneIndex)
+
+	if err := b.mutations.Set([]byte{storePrefixInternal, internalKeyLedgerIndex}, ledgerIndexBytes); err != nil {
+		b.Cancel()
+
+		return err
+	}
+
+	b.done = true
+
+	return b.mutations.Commit()
+}
@@ -0,0 +1,190 @@
+// Package diagnostics assembles a summary of the app and the node it is connected to,
+// which is logged on startup and served via the info endpoint, so misconfigured deployments are easy to spot.
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/core/logger"
+	"github.com/iotaledger/inx-app/pkg/httpserver"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+)
+
+// RouteInfo is the route to get the diagnostics report.
+// GET returns the report.
+const RouteInfo = "/info"
+
+// NodeInfo describes the node the app is connected to.
+type NodeInfo struct {
+	// Name is the name of the node software.
+	Name string `json:"name,omitempty"`
+	// Version is the version of the node software.
+	Version string `json:"version,omitempty"`
+	// Error is the reason the name and version could not be read from the node.
+	Error string `json:"error,omitempty"`
+	// NetworkName is the name of the network the node belongs to.
+	NetworkName string `json:"networkName"`
+	// Bech32HRP is the human readable part of the addresses of the network.
+	Bech32HRP string `json:"bech32Hrp"`
+	// ProtocolVersion is the current protocol version of the node.
+	ProtocolVersion byte `json:"protocolVersion"`
+	// SupportedProtocolVersions are the protocol versions the node supports.
+	SupportedProtocolVersions []uint32 `json:"supportedProtocolVersions"`
+	// ConfirmedMilestoneIndex is the confirmed milestone index of the node.
+	ConfirmedMilestoneIndex uint32 `json:"confirmedMilestoneIndex"`
+	// LedgerIndex is the ledger index of the node.
+	LedgerIndex uint32 `json:"ledgerIndex"`
+	// TanglePruningIndex is the index up to which the node pruned the tangle.
+	TanglePruningIndex uint32 `json:"tanglePruningIndex"`
+	// LedgerPruningIndex is the index up to which the node pruned the ledger.
+	LedgerPruningIndex uint32 `json:"ledgerPruningIndex"`
+}
+
+// Report is the diagnostics report.
+type Report struct {
+	// AppName is the name of the app.
+	AppName string `json:"appName"`
+	// AppVersion is the version of the app.
+	AppVersion string `json:"appVersion"`
+	// StartedAt is the time the app was started.
+	StartedAt time.Time `json:"startedAt"`
+	// Node describes the node the app is connected to.
+	Node *NodeInfo `json:"node"`
+	// Routes are the registered routes, e.g. "GET /api/myapp/v1/blocks/:blockID".
+	Routes []string `json:"routes"`
+	// Subsystems are the enabled subsystems of the app, e.g. the enabled components.
+	Subsystems []string `json:"subsystems"`
+}
+
+// Diagnostics assembles diagnostics reports.
+type Diagnostics struct {
+	nodeBridge *nodebridge.NodeBridge
+
+	appName    string
+	appVersion string
+	startedAt  time.Time
+	echo       *echo.Echo
+	subsystems []string
+}
+
+// WithApp sets the name and version of the app.
+func WithApp(name string, version string) options.Option[Diagnostics] {
+	return func(d *Diagnostics) {
+		d.appName = name
+		d.appVersion = version
+	}
+}
+
+// WithEcho lists the routes registered at the given Echo instance.
+func WithEcho(e *echo.Echo) options.Option[Diagnostics] {
+	return func(d *Diagnostics) {
+		d.echo = e
+	}
+}
+
+// WithSubsystems lists the given enabled subsystems.
+func WithSubsystems(subsystems ...string) options.Option[Diagnostics] {
+	return func(d *Diagnostics) {
+		d.subsystems = append(d.subsystems, subsystems...)
+	}
+}
+
+// NewDiagnostics creates a new Diagnostics.
+func NewDiagnostics(nodeBridge *nodebridge.NodeBridge, opts ...options.Option[Diagnostics]) *Diagnostics {
+	return options.Apply(&Diagnostics{
+		nodeBridge: nodeBridge,
+		startedAt:  time.Now(),
+	}, opts)
+}
+
+// Report assembles the current diagnostics report.
+// The name and version of the node are read via the node's REST API; if that fails, the reason is part of the report.
+func (d *Diagnostics) Report(ctx context.Context) *Report {
+	protoParams := d.nodeBridge.ProtocolParameters()
+	nodeStatus := d.nodeBridge.NodeStatus()
+
+	node := &NodeInfo{
+		NetworkName:               protoParams.NetworkName,
+		Bech32HRP:                 string(protoParams.Bech32HRP),
+		ProtocolVersion:           protoParams.Version,
+		SupportedProtocolVersions: d.nodeBridge.NodeConfig.GetSupportedProtocolVersions(),
+		ConfirmedMilestoneIndex:   d.nodeBridge.ConfirmedMilestoneIndex(),
+		LedgerIndex:               nodeStatus.GetLedgerIndex(),
+		TanglePruningIndex:        nodeStatus.GetTanglePruningIndex(),
+		LedgerPruningIndex:        nodeStatus.GetLedgerPruningIndex(),
+	}
+
+	if info, err := d.nodeBridge.INXNodeClient().Info(ctx); err != nil {
+		node.Error = err.Error()
+	} else {
+		node.Name = info.Name
+		node.Version = info.Version
+	}
+
+	report := &Report{
+		AppName:    d.appName,
+		AppVersion: d.appVersion,
+		StartedAt:  d.startedAt,
+		Node:       node,
+		Routes:     make([]string, 0),
+		Subsystems: append(make([]string, 0, len(d.subsystems)), d.subsystems...),
+	}
+
+	if d.echo != nil {
+		for _, route := range d.echo.Routes() {
+			report.Routes = append(report.Routes, fmt.Sprintf("%s %s", route.Method, route.Path))
+		}
+		sort.Strings(report.Routes)
+	}
+
+	return report
+}
+
+// LogReport logs the current diagnostics report, it is meant to be called on startup.
+func (d *Diagnostics) LogReport(ctx context.Context, log *logger.Logger) {
+	report := d.Report(ctx)
+	node := report.Node
+
+	nodeSoftware := fmt.Sprintf("%s %s", node.Name, node.Version)
+	if node.Error != "" {
+		nodeSoftware = "unknown (" + node.Error + ")"
+	}
+
+	log.Infow("Startup diagnostics",
+		"app", fmt.Sprintf("%s %s", report.AppName, report.AppVersion),
+		"node", nodeSoftware,
+		"network", node.NetworkName,
+		"bech32HRP", node.Bech32HRP,
+		"protocolVersion", node.ProtocolVersion,
+		"supportedProtocolVersions", node.SupportedProtocolVersions,
+		"confirmedMilestoneIndex", node.ConfirmedMilestoneIndex,
+		"ledgerIndex", node.LedgerIndex,
+		"tanglePruningIndex", node.TanglePruningIndex,
+		"ledgerPruningIndex", node.LedgerPruningIndex,
+		"subsystems", strings.Join(report.Subsystems, ", "),
+	)
+
+	for _, route := range report.Routes {
+		log.Infof("Registered route: %s", route)
+	}
+}
+
+// Handler returns a handler that serves the current diagnostics report.
+func (d *Diagnostics) Handler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return httpserver.JSONResponse(c, http.StatusOK, d.Report(c.Request().Context()))
+	}
+}
+
+// RegisterRoutes registers the info route on the given group.
+func (d *Diagnostics) RegisterRoutes(routeGroup *echo.Group) {
+	routeGroup.GET(RouteInfo, d.Handler())
+}
@@ -0,0 +1,196 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/core/logger"
+	"github.com/iotaledger/inx-app/pkg/httpserver"
+	"github.com/iotaledger/inx-app/pkg/pubsub"
+)
+
+// ErrStreamNotAllowed is returned if the server rejected the subscription to a stream, e.g. because it is not exported.
+var ErrStreamNotAllowed = errors.New("stream not allowed")
+
+// Client consumes the streams exported by the Server of another extension.
+// It reconnects and resubscribes to all streams after the connection was lost.
+type Client struct {
+	// the logger used to log events.
+	*logger.WrappedLogger
+
+	url               string
+	streams           []string
+	header            http.Header
+	dialer            *websocket.Dialer
+	reconnectInterval time.Duration
+	readTimeout       time.Duration
+}
+
+// WithAPIKey authenticates the client with the given API key.
+func WithAPIKey(apiKey string) options.Option[Client] {
+	return func(c *Client) {
+		c.header.Set(httpserver.HeaderAPIKey, apiKey)
+	}
+}
+
+// WithBearerToken authenticates the client with the given bearer token, e.g. a JWT.
+func WithBearerToken(token string) options.Option[Client] {
+	return func(c *Client) {
+		c.header.Set("Authorization", "Bearer "+token)
+	}
+}
+
+// WithDialer sets the dialer used to connect to the server, e.g. with a TLS config.
+func WithDialer(dialer *websocket.Dialer) options.Option[Client] {
+	return func(c *Client) {
+		c.dialer = dialer
+	}
+}
+
+// WithReconnectInterval sets the duration to wait before reconnecting after the connection was lost.
+func WithReconnectInterval(reconnectInterval time.Duration) options.Option[Client] {
+	return func(c *Client) {
+		c.reconnectInterval = reconnectInterval
+	}
+}
+
+// WithReadTimeout sets the duration after which the connection is considered lost if neither an event nor a ping
+// of the server was received. It must be longer than the ping interval of the server.
+func WithReadTimeout(readTimeout time.Duration) options.Option[Client] {
+	return func(c *Client) {
+		c.readTimeout = readTimeout
+	}
+}
+
+// NewClient creates a new Client that subscribes to the given streams of the server at the WebSocket URL,
+// e.g. "ws://localhost:9311/api/tracker/v1/federation".
+func NewClient(log *logger.Logger, url string, streams []string, opts ...options.Option[Client]) *Client {
+	return options.Apply(&Client{
+		WrappedLogger:     logger.NewWrappedLogger(log),
+		url:               url,
+		streams:           streams,
+		header:            make(http.Header),
+		dialer:            websocket.DefaultDialer,
+		reconnectInterval: 5 * time.Second,
+		readTimeout:       90 * time.Second,
+	}, opts)
+}
+
+// Run passes the events of the subscribed streams to the handler until the context is canceled.
+// The handler is called sequentially in the order the events were received. Events published while the client
+// was disconnected are lost, consumers that need every event must catch up via the API of the other extension.
+// It returns an error if the server rejected the subscription to a stream, and nil if the context was canceled.
+func (c *Client) Run(ctx context.Context, handler func(event *Event)) error {
+	for {
+		err := c.consume(ctx, handler)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if errors.Is(err, ErrStreamNotAllowed) {
+			return err
+		}
+
+		c.LogWarnf("connection to %s lost: %s, reconnecting in %s", c.url, err, c.reconnectInterval)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(c.reconnectInterval):
+		}
+	}
+}
+
+// PublishTo returns a handler for Run that publishes the events on the topics of the events in the given bus,
+// so the local consumers subscribe to them like to the events of the extension itself.
+func PublishTo(bus *pubsub.Bus) func(event *Event) {
+	return func(event *Event) {
+		bus.Publish(event.Topic, event)
+	}
+}
+
+// consume connects to the server and passes the events to the handler until the connection fails.
+func (c *Client) consume(ctx context.Context, handler func(event *Event)) error {
+	conn, resp, err := c.dialer.DialContext(ctx, c.url, c.header)
+	if err != nil {
+		if resp != nil {
+			return errors.Wrapf(err, "connecting failed with status code %d", resp.StatusCode)
+		}
+
+		return errors.Wrap(err, "connecting failed")
+	}
+	if resp != nil && resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+	defer func() { _ = conn.Close() }()
+
+	// the blocking read is interrupted by closing the connection
+	stopClose := make(chan struct{})
+	defer close(stopClose)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-stopClose:
+		}
+	}()
+
+	if err := c.extendReadDeadline(conn); err != nil {
+		return err
+	}
+	conn.SetPingHandler(func(appData string) error {
+		if err := c.extendReadDeadline(conn); err != nil {
+			return err
+		}
+
+		return conn.WriteControl(websocket.PongMessage, []byte(appData), time.Now().Add(c.readTimeout))
+	})
+
+	for _, stream := range c.streams {
+		if err := conn.WriteJSON(&httpserver.WebSocketMessage{Type: httpserver.WebSocketMessageTypeSubscribe, Topic: stream}); err != nil {
+			return errors.Wrapf(err, "subscribing to stream %s failed", stream)
+		}
+	}
+
+	for {
+		message := &httpserver.WebSocketMessage{}
+		if err := conn.ReadJSON(message); err != nil {
+			return errors.Wrap(err, "reading message failed")
+		}
+
+		if err := c.extendReadDeadline(conn); err != nil {
+			return err
+		}
+
+		switch message.Type {
+		case httpserver.WebSocketMessageTypeSubscribed:
+			c.LogInfof("subscribed to stream %s of %s", message.Topic, c.url)
+
+		case httpserver.WebSocketMessageTypeError:
+			if message.Error == httpserver.ErrWebSocketTopicNotAllowed.Error() {
+				return errors.Wrapf(ErrStreamNotAllowed, "stream %s", message.Topic)
+			}
+			c.LogWarnf("server %s reported an error: %s", c.url, message.Error)
+
+		case httpserver.WebSocketMessageTypePublish:
+			event := &Event{}
+			if err := json.Unmarshal(message.Data, event); err != nil {
+				c.LogWarnf("decoding event of stream %s failed: %s", message.Topic, err)
+
+				continue
+			}
+			event.Stream = message.Topic
+
+			handler(event)
+		}
+	}
+}
+
+func (c *Client) extendReadDeadline(conn *websocket.Conn) error {
+	return conn.SetReadDeadline(time.Now().Add(c.readTimeout))
+}
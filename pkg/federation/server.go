@@ -0,0 +1,184 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/core/logger"
+	"github.com/iotaledger/inx-app/pkg/httpserver"
+	"github.com/iotaledger/inx-app/pkg/pubsub"
+)
+
+// ConsumerName is the consumer name of the bus subscriptions of the Server.
+const ConsumerName = "federation"
+
+// ErrStreamAlreadyExported is returned if a stream is exported twice.
+var ErrStreamAlreadyExported = errors.New("stream already exported")
+
+// Event is a derived event of an extension, e.g. a notification about an output of a watched address.
+type Event struct {
+	// Stream is the exported stream the event was received on, it is the topic pattern of the stream, e.g. "watcher.#".
+	Stream string `json:"-"`
+	// Topic is the topic the event was published on in the bus of the publishing extension.
+	Topic string `json:"topic"`
+	// Payload is the JSON encoded payload of the event.
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Decode decodes the payload of the event into the given value.
+func (e *Event) Decode(v interface{}) error {
+	return json.Unmarshal(e.Payload, v)
+}
+
+// Server exports events of the bus of an extension to other extensions, so they can consume derived events
+// without consuming the raw node streams themselves.
+// Every exported stream is a topic pattern of the bus, clients subscribe to the streams by their pattern
+// via the WebSocket handler of the server. The handler is protected by the usual auth middlewares of the routes.
+type Server struct {
+	// the logger used to log events.
+	*logger.WrappedLogger
+
+	bus        *pubsub.Bus
+	hub        *httpserver.WebSocketHub
+	hubOptions []options.Option[httpserver.WebSocketHub]
+
+	streamsMutex sync.RWMutex
+	streams      map[string]*pubsub.Subscription
+}
+
+// WithWebSocketHubOptions sets the options of the WebSocketHub that serves the clients, e.g. the send queue size.
+func WithWebSocketHubOptions(hubOptions ...options.Option[httpserver.WebSocketHub]) options.Option[Server] {
+	return func(s *Server) {
+		s.hubOptions = append(s.hubOptions, hubOptions...)
+	}
+}
+
+// NewServer creates a new Server that exports events of the given bus.
+func NewServer(log *logger.Logger, bus *pubsub.Bus, opts ...options.Option[Server]) *Server {
+	s := options.Apply(&Server{
+		WrappedLogger: logger.NewWrappedLogger(log),
+		bus:           bus,
+		streams:       make(map[string]*pubsub.Subscription),
+	}, opts)
+
+	// only exported streams can be subscribed to
+	s.hub = httpserver.NewWebSocketHub(append(s.hubOptions, httpserver.WithWebSocketTopicFilter(s.exported))...)
+
+	return s
+}
+
+// Export exports the events of all topics matching the pattern as a stream, e.g. "watcher.#".
+// It must be called before Run.
+func (s *Server) Export(pattern string) error {
+	s.streamsMutex.Lock()
+	defer s.streamsMutex.Unlock()
+
+	if _, exists := s.streams[pattern]; exists {
+		return errors.Wrapf(ErrStreamAlreadyExported, "stream %s", pattern)
+	}
+
+	subscription, err := s.bus.Subscribe(pattern, pubsub.WithConsumer(ConsumerName))
+	if err != nil {
+		return err
+	}
+	s.streams[pattern] = subscription
+
+	return nil
+}
+
+// Streams returns the patterns of the exported streams.
+func (s *Server) Streams() []string {
+	s.streamsMutex.RLock()
+	defer s.streamsMutex.RUnlock()
+
+	streams := make([]string, 0, len(s.streams))
+	for pattern := range s.streams {
+		streams = append(streams, pattern)
+	}
+
+	return streams
+}
+
+func (s *Server) exported(stream string) bool {
+	s.streamsMutex.RLock()
+	defer s.streamsMutex.RUnlock()
+
+	_, exists := s.streams[stream]
+
+	return exists
+}
+
+// Handler returns the handler that serves the WebSocket clients of the server.
+func (s *Server) Handler() echo.HandlerFunc {
+	return s.hub.Handler()
+}
+
+// ClientsCount returns the amount of connected clients.
+func (s *Server) ClientsCount() int {
+	return s.hub.ClientsCount()
+}
+
+// Run forwards the events of the exported streams to the subscribed clients until the context is canceled.
+// The subscriptions of the streams are canceled and all clients are disconnected afterwards.
+func (s *Server) Run(ctx context.Context) {
+	s.streamsMutex.RLock()
+	streams := make(map[string]*pubsub.Subscription, len(s.streams))
+	for pattern, subscription := range s.streams {
+		streams[pattern] = subscription
+	}
+	s.streamsMutex.RUnlock()
+
+	var wg sync.WaitGroup
+	for pattern, subscription := range streams {
+		wg.Add(1)
+		go func(pattern string, subscription *pubsub.Subscription) {
+			defer wg.Done()
+			s.forward(ctx, pattern, subscription)
+		}(pattern, subscription)
+	}
+
+	<-ctx.Done()
+	for _, subscription := range streams {
+		subscription.Unsubscribe()
+	}
+	wg.Wait()
+
+	s.hub.Close()
+}
+
+func (s *Server) forward(ctx context.Context, pattern string, subscription *pubsub.Subscription) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case msg, ok := <-subscription.Messages():
+			if !ok {
+				return
+			}
+
+			// events nobody subscribed to are not even encoded
+			if !s.hub.HasSubscribers(pattern) {
+				continue
+			}
+
+			payload, err := json.Marshal(msg.Payload)
+			if err != nil {
+				subscription.Failed()
+				s.LogWarnf("encoding event of topic %s failed: %s", msg.Topic, err)
+
+				continue
+			}
+
+			if _, err := s.hub.Publish(pattern, &Event{Topic: msg.Topic, Payload: payload}); err != nil {
+				subscription.Failed()
+				s.LogWarnf("publishing event of topic %s failed: %s", msg.Topic, err)
+			}
+		}
+	}
+}
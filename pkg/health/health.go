@@ -0,0 +1,215 @@
+// Package health serves the health and readiness endpoints of an app.
+// It aggregates registered checks, like the connection to the node, the database and the sync state,
+// and reports the result of every check, so operators can see why an app is degraded.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/connectivity"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/inx-app/pkg/database"
+	"github.com/iotaledger/inx-app/pkg/httpserver"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+	"github.com/iotaledger/inx-app/pkg/syncstate"
+)
+
+const (
+	// RouteHealth is the route to check whether the app is healthy, e.g. for liveness probes.
+	// GET returns the results of the health checks, 503 if one of them failed.
+	RouteHealth = "/health"
+
+	// RouteReady is the route to check whether the app is ready to serve requests, e.g. for readiness probes.
+	// GET returns the results of the health and readiness checks, 503 if one of them failed.
+	RouteReady = "/ready"
+)
+
+var (
+	// ErrCheckTimeout is returned by a check that did not finish within the check timeout.
+	ErrCheckTimeout = errors.New("check timed out")
+	// ErrNodeNotConnected is returned by the NodeConnected check if the connection to the node is not ready.
+	ErrNodeNotConnected = errors.New("node not connected")
+	// ErrNotSynced is returned by the SyncState check if the app is not synced.
+	ErrNotSynced = errors.New("not synced")
+)
+
+// CheckFunc checks a part of the app, it returns an error describing the problem if the part is degraded.
+type CheckFunc func(ctx context.Context) error
+
+// check is a named CheckFunc.
+type check struct {
+	name  string
+	check CheckFunc
+}
+
+// CheckResult is the result of a single check.
+type CheckResult struct {
+	// Name is the name of the check.
+	Name string `json:"name"`
+	// Healthy tells whether the check succeeded.
+	Healthy bool `json:"healthy"`
+	// Error describes why the check failed.
+	Error string `json:"error,omitempty"`
+}
+
+// Response defines the response of a GET RouteHealth or RouteReady REST API call.
+type Response struct {
+	// Healthy tells whether all checks succeeded.
+	Healthy bool `json:"healthy"`
+	// Checks are the results of all checks, in the order they were registered.
+	Checks []*CheckResult `json:"checks"`
+}
+
+// Health aggregates the health and readiness checks of an app.
+type Health struct {
+	checkTimeout    time.Duration
+	healthChecks    []*check
+	readinessChecks []*check
+}
+
+// WithCheckTimeout sets the duration after which a check is considered failed.
+func WithCheckTimeout(checkTimeout time.Duration) options.Option[Health] {
+	return func(h *Health) {
+		h.checkTimeout = checkTimeout
+	}
+}
+
+// WithCheck registers a health check, it is part of RouteHealth and RouteReady.
+func WithCheck(name string, checkFunc CheckFunc) options.Option[Health] {
+	return func(h *Health) {
+		h.healthChecks = append(h.healthChecks, &check{name: name, check: checkFunc})
+	}
+}
+
+// WithReadinessCheck registers a readiness check, it is only part of RouteReady,
+// e.g. for the sync state, which does not require a restart of the app if it fails.
+func WithReadinessCheck(name string, checkFunc CheckFunc) options.Option[Health] {
+	return func(h *Health) {
+		h.readinessChecks = append(h.readinessChecks, &check{name: name, check: checkFunc})
+	}
+}
+
+// New creates a new Health.
+func New(opts ...options.Option[Health]) *Health {
+	return options.Apply(&Health{
+		checkTimeout: 5 * time.Second,
+	}, opts)
+}
+
+// RegisterRoutes mounts RouteHealth and RouteReady on the Echo instance.
+func (h *Health) RegisterRoutes(e *echo.Echo) {
+	e.GET(RouteHealth, h.HealthHandler())
+	e.GET(RouteReady, h.ReadyHandler())
+}
+
+// HealthHandler returns a handler that runs the health checks.
+func (h *Health) HealthHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return h.response(c, h.healthChecks)
+	}
+}
+
+// ReadyHandler returns a handler that runs the health and readiness checks.
+func (h *Health) ReadyHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		checks := make([]*check, 0, len(h.healthChecks)+len(h.readinessChecks))
+		checks = append(checks, h.healthChecks...)
+		checks = append(checks, h.readinessChecks...)
+
+		return h.response(c, checks)
+	}
+}
+
+func (h *Health) response(c echo.Context, checks []*check) error {
+	response := h.run(c.Request().Context(), checks)
+	if !response.Healthy {
+		return httpserver.JSONResponse(c, http.StatusServiceUnavailable, response)
+	}
+
+	return httpserver.JSONResponse(c, http.StatusOK, response)
+}
+
+// run runs the checks in parallel and waits for their results, at most for the check timeout.
+func (h *Health) run(ctx context.Context, checks []*check) *Response {
+	ctx, cancel := context.WithTimeout(ctx, h.checkTimeout)
+	defer cancel()
+
+	results := make([]*CheckResult, len(checks))
+
+	var wg sync.WaitGroup
+	wg.Add(len(checks))
+	for i, c := range checks {
+		go func(i int, c *check) {
+			defer wg.Done()
+			results[i] = runCheck(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	response := &Response{Healthy: true, Checks: results}
+	for _, result := range results {
+		if !result.Healthy {
+			response.Healthy = false
+		}
+	}
+
+	return response
+}
+
+// runCheck runs a single check, a check that ignores the context is abandoned after the context is done.
+func runCheck(ctx context.Context, c *check) *CheckResult {
+	checkErr := make(chan error, 1)
+	go func() {
+		checkErr <- c.check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-checkErr:
+	case <-ctx.Done():
+		err = fmt.Errorf("%w: %s", ErrCheckTimeout, ctx.Err())
+	}
+
+	if err != nil {
+		return &CheckResult{Name: c.name, Healthy: false, Error: err.Error()}
+	}
+
+	return &CheckResult{Name: c.name, Healthy: true}
+}
+
+// NodeConnected checks that the gRPC connection of the NodeBridge to the node is ready.
+func NodeConnected(nodeBridge *nodebridge.NodeBridge) CheckFunc {
+	return func(_ context.Context) error {
+		if state := nodeBridge.ConnectionState(); state != connectivity.Ready {
+			return fmt.Errorf("%w: connection state %s", ErrNodeNotConnected, state)
+		}
+
+		return nil
+	}
+}
+
+// DatabaseOpen checks that the database can be written and read.
+func DatabaseOpen(db *database.Database) CheckFunc {
+	return func(_ context.Context) error {
+		return db.CheckHealth()
+	}
+}
+
+// SyncState checks that the syncstate.Machine is in the synced phase.
+func SyncState(machine *syncstate.Machine) CheckFunc {
+	return func(_ context.Context) error {
+		status := machine.Status()
+		if status.Phase != syncstate.PhaseSynced {
+			return fmt.Errorf("%w: phase %s, lag %d", ErrNotSynced, status.Phase, status.Lag)
+		}
+
+		return nil
+	}
+}
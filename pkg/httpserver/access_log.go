@@ -0,0 +1,162 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+)
+
+const (
+	// AccessLogFormatJSON writes one JSON object per request.
+	AccessLogFormatJSON = "json"
+	// AccessLogFormatCLF writes one line per request in the Common Log Format.
+	AccessLogFormatCLF = "clf"
+)
+
+// clfTimeLayout is the layout of the timestamps in the Common Log Format.
+const clfTimeLayout = "02/Jan/2006:15:04:05 -0700"
+
+// ParametersAccessLog are the access log settings of the API, they can be registered as config parameters of a component.
+type ParametersAccessLog struct {
+	// Enabled is whether the handled requests are written to the access log.
+	Enabled bool `default:"false" usage:"whether the handled requests are written to the access log"`
+	// FilePath is the path of the access log file.
+	FilePath string `default:"access.log" usage:"the path of the access log file"`
+	// Format is the format of the log lines, either "json" or "clf".
+	Format string `default:"json" usage:"the format of the log lines, either \"json\" or \"clf\""`
+	// MaxSizeMB is the size in megabytes after which the file is rotated.
+	MaxSizeMB int `default:"100" usage:"the size in megabytes after which the file is rotated"`
+	// MaxAge is the age after which rotated files are deleted, 0 keeps them.
+	MaxAge time.Duration `default:"720h" usage:"the age after which rotated files are deleted, 0 keeps them"`
+	// MaxBackups is the maximum amount of rotated files that are kept, 0 keeps all.
+	MaxBackups int `default:"10" usage:"the maximum amount of rotated files that are kept, 0 keeps all"`
+	// Compress is whether rotated files are compressed with gzip.
+	Compress bool `default:"true" usage:"whether rotated files are compressed with gzip"`
+}
+
+// accessLogEntry is a single line of the access log in the JSON format.
+type accessLogEntry struct {
+	Time         time.Time `json:"time"`
+	RemoteIP     string    `json:"remoteIP"`
+	Method       string    `json:"method"`
+	URI          string    `json:"uri"`
+	Route        string    `json:"route"`
+	Protocol     string    `json:"protocol"`
+	Status       int       `json:"status"`
+	ResponseSize int64     `json:"responseSize"`
+	LatencyMs    float64   `json:"latencyMs"`
+	UserAgent    string    `json:"userAgent,omitempty"`
+	RequestID    string    `json:"requestId,omitempty"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// AccessLogger writes the handled requests to a file that is rotated by size and age.
+// Unlike the debug request logger it is meant to be enabled in production.
+type AccessLogger struct {
+	format string
+	writer io.WriteCloser
+}
+
+// NewAccessLogger creates a new AccessLogger configured by the given parameters.
+// The file is opened on the first request and must be closed with Close on shutdown.
+func NewAccessLogger(params *ParametersAccessLog) (*AccessLogger, error) {
+	switch params.Format {
+	case AccessLogFormatJSON, AccessLogFormatCLF:
+	default:
+		return nil, fmt.Errorf("unknown access log format: %s", params.Format)
+	}
+
+	return &AccessLogger{
+		format: params.Format,
+		writer: &lumberjack.Logger{
+			Filename:   params.FilePath,
+			MaxSize:    params.MaxSizeMB,
+			MaxAge:     int((params.MaxAge + 24*time.Hour - 1) / (24 * time.Hour)),
+			MaxBackups: params.MaxBackups,
+			Compress:   params.Compress,
+		},
+	}, nil
+}
+
+// WithAccessLog writes the handled requests to the given AccessLogger.
+func WithAccessLog(accessLogger *AccessLogger) options.Option[EchoOptions] {
+	return func(o *EchoOptions) {
+		o.accessLogger = accessLogger
+	}
+}
+
+// Middleware returns the middleware that writes the handled requests to the access log.
+// Sensitive query parameters are redacted with the DefaultRedactedQueryParams.
+func (l *AccessLogger) Middleware() echo.MiddlewareFunc {
+	return l.middleware(NewRedactor(DefaultRedactedQueryParams, DefaultRedactedHeaders))
+}
+
+func (l *AccessLogger) middleware(redactor *Redactor) echo.MiddlewareFunc {
+	return middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
+		LogLatency:      true,
+		LogProtocol:     true,
+		LogRemoteIP:     true,
+		LogMethod:       true,
+		LogURI:          true,
+		LogRoutePath:    true,
+		LogRequestID:    true,
+		LogUserAgent:    true,
+		LogStatus:       true,
+		LogError:        true,
+		LogResponseSize: true,
+		LogValuesFunc: func(_ echo.Context, v middleware.RequestLoggerValues) error {
+			v.URI = redactor.RedactURI(v.URI)
+
+			line, err := l.formatLine(v)
+			if err != nil {
+				return err
+			}
+
+			_, err = l.writer.Write(line)
+
+			return err
+		},
+	})
+}
+
+func (l *AccessLogger) formatLine(v middleware.RequestLoggerValues) ([]byte, error) {
+	if l.format == AccessLogFormatCLF {
+		return []byte(fmt.Sprintf("%s - - [%s] \"%s %s %s\" %d %d\n", v.RemoteIP, v.StartTime.Format(clfTimeLayout), v.Method, v.URI, v.Protocol, v.Status, v.ResponseSize)), nil
+	}
+
+	entry := &accessLogEntry{
+		Time:         v.StartTime,
+		RemoteIP:     v.RemoteIP,
+		Method:       v.Method,
+		URI:          v.URI,
+		Route:        v.RoutePath,
+		Protocol:     v.Protocol,
+		Status:       v.Status,
+		ResponseSize: v.ResponseSize,
+		LatencyMs:    float64(v.Latency.Microseconds()) / 1000,
+		UserAgent:    v.UserAgent,
+		RequestID:    v.RequestID,
+	}
+	if v.Error != nil {
+		entry.Error = v.Error.Error()
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(line, '\n'), nil
+}
+
+// Close closes the access log file.
+func (l *AccessLogger) Close() error {
+	return l.writer.Close()
+}
@@ -0,0 +1,163 @@
+package httpserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+)
+
+// HeaderAPIKey is the header the API key is read from by default.
+const HeaderAPIKey = "X-API-Key"
+
+var (
+	// ErrAPIKeyNotFound is returned by an APIKeyStore if the key is unknown.
+	ErrAPIKeyNotFound = errors.New("api key not found")
+
+	// ErrAPIKeyMissing is returned if the request does not contain an API key.
+	ErrAPIKeyMissing = echo.NewHTTPError(http.StatusUnauthorized, "missing api key")
+	// ErrAPIKeyInvalid is returned if the API key of the request is unknown.
+	ErrAPIKeyInvalid = echo.NewHTTPError(http.StatusUnauthorized, "invalid api key")
+	// ErrAPIKeyScopeMissing is returned if the API key of the request lacks the scope required by the route.
+	ErrAPIKeyScopeMissing = echo.NewHTTPError(http.StatusForbidden, "api key lacks the required scope")
+)
+
+// Scope is a permission granted to an API key.
+// Scopes are hierarchical, ScopeAdmin grants ScopeWrite and ScopeWrite grants ScopeRead.
+type Scope string
+
+const (
+	// ScopeRead grants access to read-only routes.
+	ScopeRead Scope = "read"
+	// ScopeWrite grants access to mutating routes.
+	ScopeWrite Scope = "write"
+	// ScopeAdmin grants access to all routes.
+	ScopeAdmin Scope = "admin"
+)
+
+const (
+	// AuthPolicyRead routes require an API key with ScopeRead, see APIKeyAuth.AuthPresets.
+	AuthPolicyRead AuthPolicy = "read"
+	// AuthPolicyWrite routes require an API key with ScopeWrite, see APIKeyAuth.AuthPresets.
+	AuthPolicyWrite AuthPolicy = "write"
+	// AuthPolicyAdmin routes require an API key with ScopeAdmin, see APIKeyAuth.AuthPresets.
+	AuthPolicyAdmin AuthPolicy = "admin"
+)
+
+// scopeLevels orders the scopes, a scope grants all scopes with a lower or equal level.
+var scopeLevels = map[Scope]int{
+	ScopeRead:  1,
+	ScopeWrite: 2,
+	ScopeAdmin: 3,
+}
+
+// Grants reports whether the scope grants the required scope.
+// Unknown scopes, also unknown required scopes, are never granted.
+func (s Scope) Grants(required Scope) bool {
+	level, known := scopeLevels[s]
+	if !known {
+		return false
+	}
+
+	requiredLevel, requiredKnown := scopeLevels[required]
+
+	return requiredKnown && level >= requiredLevel
+}
+
+// APIKeyStore resolves API keys to their scopes.
+type APIKeyStore interface {
+	// Scopes returns the scopes of the given API key, or ErrAPIKeyNotFound if the key is unknown.
+	Scopes(ctx context.Context, apiKey string) ([]Scope, error)
+}
+
+// StaticAPIKeyStore is an APIKeyStore with a fixed set of keys, e.g. loaded from the config.
+// The keys are only kept as hashes.
+type StaticAPIKeyStore struct {
+	scopes map[[sha256.Size]byte][]Scope
+}
+
+// NewStaticAPIKeyStore creates a new StaticAPIKeyStore from the given API keys and their scopes.
+func NewStaticAPIKeyStore(apiKeys map[string][]Scope) *StaticAPIKeyStore {
+	store := &StaticAPIKeyStore{
+		scopes: make(map[[sha256.Size]byte][]Scope, len(apiKeys)),
+	}
+	for apiKey, scopes := range apiKeys {
+		store.scopes[sha256.Sum256([]byte(apiKey))] = scopes
+	}
+
+	return store
+}
+
+// Scopes implements APIKeyStore.
+func (s *StaticAPIKeyStore) Scopes(_ context.Context, apiKey string) ([]Scope, error) {
+	scopes, exists := s.scopes[sha256.Sum256([]byte(apiKey))]
+	if !exists {
+		return nil, ErrAPIKeyNotFound
+	}
+
+	return scopes, nil
+}
+
+// APIKeyAuth checks the API keys of requests against an APIKeyStore.
+type APIKeyAuth struct {
+	store  APIKeyStore
+	header string
+}
+
+// WithAPIKeyHeader sets the header the API key is read from, it defaults to HeaderAPIKey.
+func WithAPIKeyHeader(header string) options.Option[APIKeyAuth] {
+	return func(a *APIKeyAuth) {
+		a.header = header
+	}
+}
+
+// NewAPIKeyAuth creates a new APIKeyAuth.
+func NewAPIKeyAuth(store APIKeyStore, opts ...options.Option[APIKeyAuth]) *APIKeyAuth {
+	return options.Apply(&APIKeyAuth{
+		store:  store,
+		header: HeaderAPIKey,
+	}, opts)
+}
+
+// Middleware returns the middleware that requires an API key granting the given scope,
+// e.g. for a route group with mutating routes.
+func (a *APIKeyAuth) Middleware(required Scope) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			apiKey := c.Request().Header.Get(a.header)
+			if apiKey == "" {
+				return ErrAPIKeyMissing
+			}
+
+			scopes, err := a.store.Scopes(c.Request().Context(), apiKey)
+			if err != nil {
+				if errors.Is(err, ErrAPIKeyNotFound) {
+					return ErrAPIKeyInvalid
+				}
+
+				return err
+			}
+
+			for _, scope := range scopes {
+				if scope.Grants(required) {
+					return next(c)
+				}
+			}
+
+			return ErrAPIKeyScopeMissing
+		}
+	}
+}
+
+// AuthPresets returns the RouteTable options that enforce AuthPolicyRead, AuthPolicyWrite and AuthPolicyAdmin.
+func (a *APIKeyAuth) AuthPresets() []options.Option[RouteTable] {
+	return []options.Option[RouteTable]{
+		WithAuthPreset(AuthPolicyRead, a.Middleware(ScopeRead)),
+		WithAuthPreset(AuthPolicyWrite, a.Middleware(ScopeWrite)),
+		WithAuthPreset(AuthPolicyAdmin, a.Middleware(ScopeAdmin)),
+	}
+}
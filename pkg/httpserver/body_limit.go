@@ -0,0 +1,125 @@
+package httpserver
+
+import (
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+)
+
+// WithBodyLimit limits the size of request bodies to defaultLimit bytes.
+// The group limits override the default for all routes below the given path prefixes, e.g. "/api/core/v2/blocks",
+// the longest matching prefix wins. A limit of zero or less disables the limit.
+func WithBodyLimit(defaultLimit int64, groupLimits map[string]int64) options.Option[EchoOptions] {
+	return func(o *EchoOptions) {
+		o.bodyLimitEnabled = true
+		o.bodyLimit = defaultLimit
+		o.bodyGroupLimits = groupLimits
+	}
+}
+
+// bodyLimitExceededError is returned to the handler if it reads past the limit.
+func bodyLimitExceededError(limit int64) error {
+	return errors.WithMessagef(echo.ErrStatusRequestEntityTooLarge, "request body exceeds the limit of %d bytes", limit)
+}
+
+// limitedBody fails reads past the limit and remembers whether the limit was exceeded.
+type limitedBody struct {
+	io.ReadCloser
+	limit    int64
+	read     int64
+	exceeded bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.exceeded {
+		return 0, bodyLimitExceededError(b.limit)
+	}
+
+	// read one byte more than allowed to detect bodies that exceed the limit
+	if remaining := b.limit + 1 - b.read; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if b.read > b.limit {
+		b.exceeded = true
+
+		return n - int(b.read-b.limit), bodyLimitExceededError(b.limit)
+	}
+
+	return n, err
+}
+
+// BodyLimitMiddleware limits the size of request bodies to the given amount of bytes.
+// Requests that exceed it are answered with status 413, even if the handler wrapped the read error.
+// A limit of zero or less disables the limit.
+func BodyLimitMiddleware(limit int64) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if limit <= 0 {
+				return next(c)
+			}
+
+			req := c.Request()
+			if req.ContentLength > limit {
+				return bodyLimitExceededError(limit)
+			}
+
+			if req.Body == nil {
+				return next(c)
+			}
+
+			body := &limitedBody{ReadCloser: req.Body, limit: limit}
+			req.Body = body
+
+			err := next(c)
+			if body.exceeded && !c.Response().Committed {
+				return bodyLimitExceededError(limit)
+			}
+
+			return err
+		}
+	}
+}
+
+// groupBodyLimitMiddleware applies the limit of the longest matching group prefix, or the default limit.
+func groupBodyLimitMiddleware(defaultLimit int64, groupLimits map[string]int64) echo.MiddlewareFunc {
+	prefixes := make([]string, 0, len(groupLimits))
+	middlewares := make(map[string]echo.MiddlewareFunc, len(groupLimits))
+	for prefix, limit := range groupLimits {
+		prefixes = append(prefixes, prefix)
+		middlewares[prefix] = BodyLimitMiddleware(limit)
+	}
+
+	// the longest prefix is the most specific one
+	sort.Slice(prefixes, func(i int, j int) bool {
+		return len(prefixes[i]) > len(prefixes[j])
+	})
+
+	defaultMiddleware := BodyLimitMiddleware(defaultLimit)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		defaultHandler := defaultMiddleware(next)
+		handlers := make(map[string]echo.HandlerFunc, len(middlewares))
+		for prefix, middleware := range middlewares {
+			handlers[prefix] = middleware(next)
+		}
+
+		return func(c echo.Context) error {
+			path := c.Request().URL.Path
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(path, prefix) {
+					return handlers[prefix](c)
+				}
+			}
+
+			return defaultHandler(c)
+		}
+	}
+}
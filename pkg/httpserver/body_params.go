@@ -0,0 +1,68 @@
+package httpserver
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// BindJSONBody binds the JSON request body to the given value.
+func BindJSONBody(c echo.Context, value interface{}) error {
+	if _, err := GetRequestContentType(c, echo.MIMEApplicationJSON); err != nil {
+		return err
+	}
+
+	if err := c.Bind(value); err != nil {
+		return errors.WithMessagef(ErrInvalidParameter, "invalid request body, error: %s", err)
+	}
+
+	return nil
+}
+
+// parseIDs parses the hex encoded IDs of a body parameter.
+// It fails if there are no IDs or more than maxCount, and reports the index of the first invalid ID.
+func parseIDs[T any](paramName string, values []string, maxCount int, kind string, parse func(string) (T, error)) ([]T, error) {
+	if len(values) == 0 {
+		return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "parameter \"%s\" not specified", paramName), paramName, ErrorReasonMissing, "")
+	}
+
+	if len(values) > maxCount {
+		return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "parameter \"%s\" contains too many elements, max. %d but is %d", paramName, maxCount, len(values)), paramName, ErrorReasonOutOfRange, "")
+	}
+
+	ids := make([]T, len(values))
+	for i, value := range values {
+		id, err := parse(strings.ToLower(value))
+		if err != nil {
+			return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid %s at %s[%d]: %s, error: %s", kind, paramName, i, value, err), fmt.Sprintf("%s[%d]", paramName, i), ErrorReasonInvalid, value)
+		}
+		ids[i] = id
+	}
+
+	return ids, nil
+}
+
+// ParseOutputIDsBodyParam parses the hex encoded output IDs of the body parameter with the given name.
+func ParseOutputIDsBodyParam(paramName string, values []string, maxCount int) (iotago.OutputIDs, error) {
+	return parseIDs(paramName, values, maxCount, "output ID", iotago.OutputIDFromHex)
+}
+
+// ParseBlockIDsBodyParam parses the hex encoded block IDs of the body parameter with the given name.
+func ParseBlockIDsBodyParam(paramName string, values []string, maxCount int) (iotago.BlockIDs, error) {
+	return parseIDs(paramName, values, maxCount, "block ID", iotago.BlockIDFromHexString)
+}
+
+// ParseAddressBodyParam parses the address of the body parameter with the given name, see ParseAddressParam for the accepted formats.
+func ParseAddressBodyParam(paramName string, value string, prefix iotago.NetworkPrefix) (iotago.Address, error) {
+	return parseAddress(value, prefix, paramName)
+}
+
+// ParseFixedHexBodyParam parses the hex encoded value of the body parameter with the given name,
+// which must have exactly the given length in bytes.
+func ParseFixedHexBodyParam(paramName string, value string, length int) ([]byte, error) {
+	return parseFixedHex(value, paramName, "value", length)
+}
@@ -0,0 +1,260 @@
+// Package clientgen generates Go client packages for the APIs of INX apps from their OpenAPI document,
+// so integrators don't hand-write HTTP clients that drift from the server.
+//
+// A typical setup generates the client from the RouteTable of the app with go:generate:
+//
+//	doc := routeTable.OpenAPI("proof", "v1")
+//	src, err := clientgen.GenerateGoClient(doc, "proofclient")
+package clientgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/iotaledger/inx-app/pkg/httpserver"
+)
+
+// reserved are the names of the parameters every generated method has.
+var reserved = map[string]struct{}{
+	"ctx":    {},
+	"query":  {},
+	"body":   {},
+	"result": {},
+	"c":      {},
+}
+
+type operation struct {
+	Name     string
+	Summary  string
+	Method   string
+	Path     string
+	PathExpr string
+	Params   []string
+	HasBody  bool
+}
+
+var clientTemplate = template.Must(template.New("client").Parse(`// Code generated by clientgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// APIErrorDetail describes which parameter of a request failed and why.
+type APIErrorDetail struct {
+	Field  string ` + "`json:\"field\"`" + `
+	Reason string ` + "`json:\"reason\"`" + `
+	Value  string ` + "`json:\"value,omitempty\"`" + `
+}
+
+// APIError is returned if the API responded with an error.
+type APIError struct {
+	StatusCode int              ` + "`json:\"-\"`" + `
+	Code       string           ` + "`json:\"code\"`" + `
+	Message    string           ` + "`json:\"message\"`" + `
+	Details    []APIErrorDetail ` + "`json:\"details,omitempty\"`" + `
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api error %d: %s", e.StatusCode, e.Message)
+}
+
+// Client is the client of the {{.Title}} API.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// New creates a new Client for the API at the given base URL, e.g. "http://localhost:14265".
+func New(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{baseURL: baseURL, httpClient: httpClient}
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, query url.Values, body interface{}, result interface{}) error {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyJSON, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		bodyReader = bytes.NewReader(bodyJSON)
+	}
+
+	requestURL := c.baseURL + path
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, bodyReader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		envelope := &struct {
+			Error *APIError ` + "`json:\"error\"`" + `
+		}{}
+		if err := json.NewDecoder(resp.Body).Decode(envelope); err != nil || envelope.Error == nil {
+			return &APIError{StatusCode: resp.StatusCode, Message: resp.Status}
+		}
+		envelope.Error.StatusCode = resp.StatusCode
+
+		return envelope.Error
+	}
+
+	if result == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+{{range .Operations}}
+// {{.Name}} calls {{.Method}} {{.Path}}.{{if .Summary}}
+// {{.Summary}}{{end}}
+func (c *Client) {{.Name}}(ctx context.Context, {{range .Params}}{{.}} string, {{end}}query url.Values{{if .HasBody}}, body interface{}{{end}}, result interface{}) error {
+	return c.do(ctx, "{{.Method}}", {{.PathExpr}}, query, {{if .HasBody}}body{{else}}nil{{end}}, result)
+}
+{{end}}`))
+
+// identifier converts the name to a Go identifier, exported if requested.
+func identifier(name string, exported bool) string {
+	var builder strings.Builder
+	upperNext := exported
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upperNext = true
+
+			continue
+		}
+		if builder.Len() == 0 && unicode.IsDigit(r) {
+			builder.WriteRune('_')
+		}
+		if upperNext {
+			r = unicode.ToUpper(r)
+			upperNext = false
+		} else if builder.Len() == 0 {
+			r = unicode.ToLower(r)
+		}
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
+
+// pathExpression returns the Go expression that builds the path with the escaped parameters.
+func pathExpression(path string, params map[string]string) string {
+	parts := make([]string, 0)
+	literal := ""
+	for _, segment := range strings.SplitAfter(path, "/") {
+		trimmed := strings.TrimSuffix(segment, "/")
+		if strings.HasPrefix(trimmed, "{") && strings.HasSuffix(trimmed, "}") {
+			parts = append(parts, strconv.Quote(literal), "url.PathEscape("+params[trimmed[1:len(trimmed)-1]]+")")
+			literal = strings.TrimPrefix(segment, trimmed)
+
+			continue
+		}
+		literal += segment
+	}
+	if literal != "" || len(parts) == 0 {
+		parts = append(parts, strconv.Quote(literal))
+	}
+
+	return strings.Join(parts, " + ")
+}
+
+// GenerateGoClient generates the source of a Go client package with one method per operation of the document.
+func GenerateGoClient(doc *httpserver.OpenAPIDocument, packageName string) ([]byte, error) {
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	operations := make([]*operation, 0)
+	names := make(map[string]struct{})
+	for _, path := range paths {
+		pathItem := doc.Paths[path]
+
+		methods := make([]string, 0, len(pathItem))
+		for method := range pathItem {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+
+		for _, method := range methods {
+			openAPIOperation := pathItem[method]
+
+			name := identifier(openAPIOperation.OperationID, true)
+			if name == "" {
+				return nil, fmt.Errorf("operation %s %s has no operation ID", method, path)
+			}
+			if _, exists := names[name]; exists {
+				return nil, fmt.Errorf("duplicate operation ID %s", openAPIOperation.OperationID)
+			}
+			names[name] = struct{}{}
+
+			params := make(map[string]string)
+			paramNames := make([]string, 0)
+			for _, param := range openAPIOperation.Parameters {
+				if param.In != "path" {
+					continue
+				}
+				paramName := identifier(param.Name, false)
+				if _, isReserved := reserved[paramName]; isReserved {
+					paramName += "Param"
+				}
+				params[param.Name] = paramName
+				paramNames = append(paramNames, paramName)
+			}
+
+			upperMethod := strings.ToUpper(method)
+			operations = append(operations, &operation{
+				Name:     name,
+				Summary:  openAPIOperation.Summary,
+				Method:   upperMethod,
+				Path:     path,
+				PathExpr: pathExpression(path, params),
+				Params:   paramNames,
+				HasBody:  upperMethod == "POST" || upperMethod == "PUT" || upperMethod == "PATCH",
+			})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := clientTemplate.Execute(&buf, map[string]interface{}{
+		"Package":    packageName,
+		"Title":      doc.Info.Title,
+		"Operations": operations,
+	}); err != nil {
+		return nil, err
+	}
+
+	return format.Source(buf.Bytes())
+}
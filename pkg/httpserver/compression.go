@@ -0,0 +1,311 @@
+package httpserver
+
+import (
+	"bufio"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+)
+
+const (
+	// EncodingGzip is the gzip content encoding.
+	EncodingGzip = "gzip"
+	// EncodingDeflate is the deflate content encoding, i.e. the zlib format.
+	EncodingDeflate = "deflate"
+)
+
+// DefaultCompressionExcludedContentTypes are the content types that are not compressed by default,
+// because they are already compressed or streamed.
+var DefaultCompressionExcludedContentTypes = []string{"image/", "video/", "audio/", "application/zip", "application/gzip", "application/x-gzip", "text/event-stream"}
+
+// CompressionOptions are the settings of the compression middleware.
+type CompressionOptions struct {
+	minSize              int
+	level                int
+	excludedContentTypes []string
+}
+
+// WithCompressionMinSize sets the minimum size of a response body in bytes to be compressed.
+func WithCompressionMinSize(minSize int) options.Option[CompressionOptions] {
+	return func(o *CompressionOptions) {
+		o.minSize = minSize
+	}
+}
+
+// WithCompressionLevel sets the compression level, e.g. gzip.BestSpeed.
+func WithCompressionLevel(level int) options.Option[CompressionOptions] {
+	return func(o *CompressionOptions) {
+		o.level = level
+	}
+}
+
+// WithCompressionExcludedContentTypes sets the content types, or prefixes of them like "image/", that are not compressed.
+// It replaces DefaultCompressionExcludedContentTypes.
+func WithCompressionExcludedContentTypes(contentTypes ...string) options.Option[CompressionOptions] {
+	return func(o *CompressionOptions) {
+		o.excludedContentTypes = contentTypes
+	}
+}
+
+// WithCompression adds the compression middleware to the Echo instance created by NewEcho.
+func WithCompression(opts ...options.Option[CompressionOptions]) options.Option[EchoOptions] {
+	return func(o *EchoOptions) {
+		o.compressionEnabled = true
+		o.compression = opts
+	}
+}
+
+// negotiateEncoding returns the preferred supported encoding of the Accept-Encoding header, or "" if none is acceptable.
+// Encodings with a quality of 0 are not acceptable, the wildcard "*" applies to all encodings that are not listed.
+func negotiateEncoding(acceptEncoding string) string {
+	qualities := make(map[string]float64)
+	wildcardQuality := -1.0
+
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		encoding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		encoding = strings.ToLower(strings.TrimSpace(encoding))
+
+		quality := 1.0
+		if params = strings.TrimSpace(params); strings.HasPrefix(params, "q=") {
+			parsedQuality, err := strconv.ParseFloat(strings.TrimPrefix(params, "q="), 64)
+			if err != nil {
+				continue
+			}
+			quality = parsedQuality
+		}
+
+		if encoding == "*" {
+			wildcardQuality = quality
+		} else {
+			qualities[encoding] = quality
+		}
+	}
+
+	bestEncoding := ""
+	bestQuality := 0.0
+
+	// gzip is preferred on equal quality
+	for _, encoding := range []string{EncodingGzip, EncodingDeflate} {
+		quality, listed := qualities[encoding]
+		if !listed {
+			quality = wildcardQuality
+		}
+
+		// a quality of 0 marks the encoding as not acceptable
+		if quality <= 0 {
+			continue
+		}
+
+		if quality > bestQuality {
+			bestEncoding = encoding
+			bestQuality = quality
+		}
+	}
+
+	return bestEncoding
+}
+
+// compressWriter is the part of the gzip and zlib writers used by the compression middleware.
+type compressWriter interface {
+	Write(p []byte) (int, error)
+	Flush() error
+	Close() error
+}
+
+// compressResponseWriter buffers the response until the minimum size is reached,
+// then it decides whether to compress, so small responses are sent as they are.
+type compressResponseWriter struct {
+	http.ResponseWriter
+
+	options  *CompressionOptions
+	encoding string
+
+	statusCode  int
+	wroteHeader bool
+	buffer      []byte
+	decided     bool
+	compressor  compressWriter
+}
+
+func (w *compressResponseWriter) excluded() bool {
+	header := w.Header()
+	if header.Get(echo.HeaderContentEncoding) != "" {
+		return true
+	}
+
+	contentType := header.Get(echo.HeaderContentType)
+	for _, excludedContentType := range w.options.excludedContentTypes {
+		if strings.HasPrefix(contentType, excludedContentType) {
+			return true
+		}
+	}
+
+	return w.statusCode == http.StatusNoContent || w.statusCode == http.StatusNotModified
+}
+
+// decide writes the header and the buffered body, compressed if allowed.
+func (w *compressResponseWriter) decide(compress bool) error {
+	w.decided = true
+
+	if compress && !w.excluded() {
+		header := w.Header()
+		header.Set(echo.HeaderContentEncoding, w.encoding)
+		header.Del(echo.HeaderContentLength)
+
+		var err error
+		if w.encoding == EncodingGzip {
+			w.compressor, err = gzip.NewWriterLevel(w.ResponseWriter, w.options.level)
+		} else {
+			w.compressor, err = zlib.NewWriterLevel(w.ResponseWriter, w.options.level)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(w.statusCode)
+
+	buffer := w.buffer
+	w.buffer = nil
+	if len(buffer) == 0 {
+		return nil
+	}
+
+	_, err := w.write(buffer)
+
+	return err
+}
+
+func (w *compressResponseWriter) write(b []byte) (int, error) {
+	if w.compressor != nil {
+		return w.compressor.Write(b)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *compressResponseWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+	w.wroteHeader = true
+}
+
+func (w *compressResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	if w.decided {
+		return w.write(b)
+	}
+
+	w.buffer = append(w.buffer, b...)
+	if len(w.buffer) >= w.options.minSize {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(b), nil
+}
+
+// Flush sends the buffered response, streamed responses are compressed regardless of the minimum size.
+func (w *compressResponseWriter) Flush() {
+	if !w.decided {
+		if err := w.decide(true); err != nil {
+			return
+		}
+	}
+
+	if w.compressor != nil {
+		_ = w.compressor.Flush()
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *compressResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+
+	return hijacker.Hijack()
+}
+
+// close sends responses below the minimum size uncompressed and finishes the compressed stream.
+// Nothing is sent if the handler neither wrote a header nor a body, so the status is not committed.
+func (w *compressResponseWriter) close() error {
+	if !w.wroteHeader {
+		return nil
+	}
+
+	if !w.decided {
+		if err := w.decide(false); err != nil {
+			return err
+		}
+	}
+
+	if w.compressor != nil {
+		return w.compressor.Close()
+	}
+
+	return nil
+}
+
+// CompressionMiddleware returns a middleware that compresses responses with gzip or deflate, honoring Accept-Encoding.
+// Responses below the minimum size (1 KiB by default) and excluded content types are sent uncompressed.
+func CompressionMiddleware(opts ...options.Option[CompressionOptions]) echo.MiddlewareFunc {
+	compressionOpts := options.Apply(&CompressionOptions{
+		minSize:              1024,
+		level:                gzip.DefaultCompression,
+		excludedContentTypes: DefaultCompressionExcludedContentTypes,
+	}, opts)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			request := c.Request()
+			if request.Header.Get(echo.HeaderUpgrade) != "" {
+				// websocket upgrades must not be compressed
+				return next(c)
+			}
+
+			response := c.Response()
+			response.Header().Add(echo.HeaderVary, echo.HeaderAcceptEncoding)
+
+			encoding := negotiateEncoding(request.Header.Get(echo.HeaderAcceptEncoding))
+			if encoding == "" {
+				return next(c)
+			}
+
+			writer := &compressResponseWriter{
+				ResponseWriter: response.Writer,
+				options:        compressionOpts,
+				encoding:       encoding,
+				statusCode:     http.StatusOK,
+			}
+			originalWriter := response.Writer
+			response.Writer = writer
+			defer func() {
+				response.Writer = originalWriter
+			}()
+
+			err := next(c)
+			if err != nil && !response.Committed {
+				// the error handler responds via the original writer after the deferred restore
+				return err
+			}
+
+			if closeErr := writer.close(); err == nil {
+				err = closeErr
+			}
+
+			return err
+		}
+	}
+}
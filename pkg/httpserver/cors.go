@@ -0,0 +1,101 @@
+package httpserver
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+)
+
+// ParametersCORS are the CORS settings of the API, they can be registered as config parameters of a component.
+type ParametersCORS struct {
+	// Enabled is whether CORS headers are added to the responses.
+	Enabled bool `default:"false" usage:"whether CORS headers are added to the responses"`
+	// AllowOrigins are the origins that may access the API, "*" allows all origins.
+	AllowOrigins []string `usage:"the origins that may access the API, \"*\" allows all origins"`
+	// AllowMethods are the methods that may be used to access the API, empty allows the common methods.
+	AllowMethods []string `usage:"the methods that may be used to access the API, empty allows the common methods"`
+	// AllowHeaders are the request headers that may be sent, empty allows the headers requested by the browser.
+	AllowHeaders []string `usage:"the request headers that may be sent, empty allows the headers requested by the browser"`
+	// ExposeHeaders are the response headers that scripts may read, in addition to the rate limit and cursor headers.
+	ExposeHeaders []string `usage:"the response headers that scripts may read, in addition to the rate limit and cursor headers"`
+	// AllowCredentials is whether requests may include cookies and authorization headers.
+	// It is rejected if all origins are allowed, see Validate.
+	AllowCredentials bool `default:"false" usage:"whether requests may include cookies and authorization headers"`
+	// MaxAge is the duration the result of a preflight request may be cached by browsers.
+	MaxAge time.Duration `default:"1h" usage:"the duration the result of a preflight request may be cached by browsers"`
+}
+
+// ErrCORSCredentialsWithWildcard is returned if credentials are allowed for all origins,
+// which would allow every site to send credentialed requests to the API.
+var ErrCORSCredentialsWithWildcard = errors.New("CORS credentials must not be allowed for all origins, the allowed origins must be listed explicitly")
+
+// DefaultCORSAllowMethods are the methods allowed if ParametersCORS.AllowMethods is empty.
+var DefaultCORSAllowMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// WithCORS adds the CORS middleware configured by the given parameters, if they are enabled.
+// It fails if the parameters are invalid, see ParametersCORS.Validate.
+func WithCORS(params *ParametersCORS) (options.Option[EchoOptions], error) {
+	var corsMiddleware echo.MiddlewareFunc
+	if params.Enabled {
+		var err error
+		if corsMiddleware, err = CORSMiddleware(params); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(o *EchoOptions) {
+		o.corsMiddleware = corsMiddleware
+	}, nil
+}
+
+// Validate checks that credentials are only allowed for explicitly listed origins.
+func (p *ParametersCORS) Validate() error {
+	if !p.AllowCredentials {
+		return nil
+	}
+
+	if len(p.AllowOrigins) == 0 {
+		return ErrCORSCredentialsWithWildcard
+	}
+	for _, origin := range p.AllowOrigins {
+		if origin == "*" {
+			return ErrCORSCredentialsWithWildcard
+		}
+	}
+
+	return nil
+}
+
+// CORSMiddleware returns the CORS middleware configured by the given parameters.
+// It fails if the parameters are invalid, see ParametersCORS.Validate.
+func CORSMiddleware(params *ParametersCORS) (echo.MiddlewareFunc, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	allowOrigins := params.AllowOrigins
+	if len(allowOrigins) == 0 {
+		allowOrigins = []string{"*"}
+	}
+
+	allowMethods := params.AllowMethods
+	if len(allowMethods) == 0 {
+		allowMethods = DefaultCORSAllowMethods
+	}
+
+	exposeHeaders := append([]string{HeaderRateLimitLimit, HeaderRateLimitRemaining, HeaderRateLimitReset, echo.HeaderRetryAfter, HeaderNextCursor}, params.ExposeHeaders...)
+
+	return middleware.CORSWithConfig(middleware.CORSConfig{
+		AllowOrigins:     allowOrigins,
+		AllowMethods:     allowMethods,
+		AllowHeaders:     params.AllowHeaders,
+		ExposeHeaders:    exposeHeaders,
+		AllowCredentials: params.AllowCredentials,
+		MaxAge:           int(params.MaxAge.Seconds()),
+	}), nil
+}
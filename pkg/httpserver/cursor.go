@@ -0,0 +1,126 @@
+package httpserver
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// QueryParameterCursor is the query parameter used by paginated endpoints to continue a previous request.
+	QueryParameterCursor = "cursor"
+	// QueryParameterPageSize is the query parameter used by paginated endpoints to specify the amount of items per page.
+	QueryParameterPageSize = "pageSize"
+	// HeaderNextCursor is the header that contains the cursor of the next page, it is not set on the last page.
+	HeaderNextCursor = "X-Next-Cursor"
+
+	cursorLength = 4 + 8 // milestone index + offset
+)
+
+// Cursor points to the position of the next page of a paginated endpoint.
+// It is anchored to the ledger index of the first page, so all pages are served from the same ledger state,
+// or clients are told to start over if that state is no longer available.
+// Clients must treat the encoded cursor as opaque.
+type Cursor struct {
+	// LedgerIndex is the ledger index the pagination is anchored to.
+	LedgerIndex iotago.MilestoneIndex
+	// Offset is the amount of items that were already returned.
+	Offset uint64
+}
+
+// NewCursor creates a new Cursor.
+func NewCursor(ledgerIndex iotago.MilestoneIndex, offset uint64) *Cursor {
+	return &Cursor{
+		LedgerIndex: ledgerIndex,
+		Offset:      offset,
+	}
+}
+
+// Next returns the cursor of the page following a page with the given amount of items.
+func (c *Cursor) Next(pageSize int) *Cursor {
+	return NewCursor(c.LedgerIndex, c.Offset+uint64(pageSize))
+}
+
+// String returns the opaque, URL safe encoding of the cursor.
+func (c *Cursor) String() string {
+	data := make([]byte, cursorLength)
+	binary.BigEndian.PutUint32(data[:4], c.LedgerIndex)
+	binary.BigEndian.PutUint64(data[4:], c.Offset)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor decodes a cursor that was encoded with Cursor.String.
+func DecodeCursor(encoded string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != cursorLength {
+		return nil, errors.Errorf("invalid length: %d", len(data))
+	}
+
+	return NewCursor(binary.BigEndian.Uint32(data[:4]), binary.BigEndian.Uint64(data[4:])), nil
+}
+
+// ParseCursorQueryParam parses the cursor from the given query parameter.
+// If the parameter is not specified, nil is returned, i.e. the first page is requested.
+func ParseCursorQueryParam(c echo.Context, paramName string) (*Cursor, error) {
+	cursorParam := c.QueryParam(paramName)
+	if cursorParam == "" {
+		//nolint:nilnil // nil cursor means the first page
+		return nil, nil
+	}
+
+	cursor, err := DecodeCursor(cursorParam)
+	if err != nil {
+		return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid cursor: %s, error: %s", cursorParam, err), paramName, ErrorReasonInvalid, cursorParam)
+	}
+
+	return cursor, nil
+}
+
+// WriteCursorHeader sets the cursor of the next page as HeaderNextCursor, a nil cursor marks the last page.
+// It must be called before the response body is written.
+func WriteCursorHeader(c echo.Context, cursor *Cursor) {
+	if cursor == nil {
+		c.Response().Header().Del(HeaderNextCursor)
+
+		return
+	}
+
+	c.Response().Header().Set(HeaderNextCursor, cursor.String())
+}
+
+// ParsePageSizeQueryParam parses the page size from the "pageSize" query parameter and returns the effective value.
+// If the parameter is not specified, the default size is returned, bigger values are clamped to the max size.
+func ParsePageSizeQueryParam(c echo.Context, defaultSize int, maxSize int) (int, error) {
+	pageSizeParam := c.QueryParam(QueryParameterPageSize)
+	if pageSizeParam == "" {
+		if defaultSize > maxSize {
+			return maxSize, nil
+		}
+
+		return defaultSize, nil
+	}
+
+	pageSize, err := strconv.Atoi(pageSizeParam)
+	if err != nil {
+		return 0, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid page size: %s, error: %s", pageSizeParam, err), QueryParameterPageSize, ErrorReasonInvalid, pageSizeParam)
+	}
+
+	if pageSize < 1 {
+		return 0, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid page size: %s, must be at least 1", pageSizeParam), QueryParameterPageSize, ErrorReasonOutOfRange, pageSizeParam)
+	}
+
+	if pageSize > maxSize {
+		return maxSize, nil
+	}
+
+	return pageSize, nil
+}
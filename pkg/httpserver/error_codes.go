@@ -0,0 +1,159 @@
+package httpserver
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// ErrorCode is a stable, machine-readable code of an API error, clients branch on it instead of parsing the message.
+type ErrorCode string
+
+const (
+	// ErrorCodeInvalidParameter is the code of requests with a missing or invalid parameter.
+	ErrorCodeInvalidParameter ErrorCode = "invalid_parameter"
+	// ErrorCodeBadRequest is the code of other malformed requests.
+	ErrorCodeBadRequest ErrorCode = "bad_request"
+	// ErrorCodeUnauthorized is the code of requests without valid credentials.
+	ErrorCodeUnauthorized ErrorCode = "unauthorized"
+	// ErrorCodeForbidden is the code of requests whose credentials lack the required permissions.
+	ErrorCodeForbidden ErrorCode = "forbidden"
+	// ErrorCodeNotFound is the code of requests for resources that don't exist.
+	ErrorCodeNotFound ErrorCode = "not_found"
+	// ErrorCodeMethodNotAllowed is the code of requests with a method the route doesn't support.
+	ErrorCodeMethodNotAllowed ErrorCode = "method_not_allowed"
+	// ErrorCodeNotAcceptable is the code of requests for a representation the route doesn't support.
+	ErrorCodeNotAcceptable ErrorCode = "not_acceptable"
+	// ErrorCodeConflict is the code of requests that conflict with the current state, e.g. an already existing resource.
+	ErrorCodeConflict ErrorCode = "conflict"
+	// ErrorCodePayloadTooLarge is the code of requests whose body exceeds the limit.
+	ErrorCodePayloadTooLarge ErrorCode = "payload_too_large"
+	// ErrorCodeUnsupportedMediaType is the code of requests with a content type the route doesn't support.
+	ErrorCodeUnsupportedMediaType ErrorCode = "unsupported_media_type"
+	// ErrorCodeRateLimited is the code of requests that exceeded the rate limit.
+	ErrorCodeRateLimited ErrorCode = "rate_limited"
+	// ErrorCodeInternal is the code of unexpected errors of the server.
+	ErrorCodeInternal ErrorCode = "internal_error"
+	// ErrorCodeNotImplemented is the code of requests for features the node doesn't support.
+	ErrorCodeNotImplemented ErrorCode = "not_implemented"
+	// ErrorCodeBadGateway is the code of requests the node failed to answer.
+	ErrorCodeBadGateway ErrorCode = "bad_gateway"
+	// ErrorCodeUnavailable is the code of requests that can't be served at the moment, clients may retry later.
+	ErrorCodeUnavailable ErrorCode = "unavailable"
+	// ErrorCodeNotSynced is the code of requests that need a synced node.
+	ErrorCodeNotSynced ErrorCode = "not_synced"
+	// ErrorCodeTimeout is the code of requests that took too long.
+	ErrorCodeTimeout ErrorCode = "timeout"
+)
+
+// statusErrorCodes are the codes of errors without a registered or attached code.
+var statusErrorCodes = map[int]ErrorCode{
+	http.StatusBadRequest:            ErrorCodeBadRequest,
+	http.StatusUnauthorized:          ErrorCodeUnauthorized,
+	http.StatusForbidden:             ErrorCodeForbidden,
+	http.StatusNotFound:              ErrorCodeNotFound,
+	http.StatusMethodNotAllowed:      ErrorCodeMethodNotAllowed,
+	http.StatusNotAcceptable:         ErrorCodeNotAcceptable,
+	http.StatusConflict:              ErrorCodeConflict,
+	http.StatusRequestEntityTooLarge: ErrorCodePayloadTooLarge,
+	http.StatusUnsupportedMediaType:  ErrorCodeUnsupportedMediaType,
+	http.StatusTooManyRequests:       ErrorCodeRateLimited,
+	http.StatusInternalServerError:   ErrorCodeInternal,
+	http.StatusNotImplemented:        ErrorCodeNotImplemented,
+	http.StatusBadGateway:            ErrorCodeBadGateway,
+	http.StatusServiceUnavailable:    ErrorCodeUnavailable,
+	http.StatusGatewayTimeout:        ErrorCodeTimeout,
+}
+
+type registeredErrorCode struct {
+	target error
+	code   ErrorCode
+}
+
+var (
+	errorCodesMutex sync.RWMutex
+	// registeredErrorCodes are checked in the order of their registration.
+	registeredErrorCodes = []registeredErrorCode{
+		{target: ErrInvalidParameter, code: ErrorCodeInvalidParameter},
+		{target: ErrNodeNotSynced, code: ErrorCodeNotSynced},
+	}
+)
+
+// RegisterErrorCode registers the code of all errors that wrap the target error, e.g. of an extension-specific sentinel error.
+// Codes attached with WithErrorCode take precedence.
+func RegisterErrorCode(target error, code ErrorCode) {
+	errorCodesMutex.Lock()
+	defer errorCodesMutex.Unlock()
+
+	registeredErrorCodes = append(registeredErrorCodes, registeredErrorCode{target: target, code: code})
+}
+
+// NewHTTPError creates a new sentinel HTTP error and registers its code.
+func NewHTTPError(statusCode int, code ErrorCode, message string) *echo.HTTPError {
+	err := echo.NewHTTPError(statusCode, message)
+	RegisterErrorCode(err, code)
+
+	return err
+}
+
+type codedError struct {
+	error
+	code ErrorCode
+}
+
+func (e *codedError) Unwrap() error {
+	return e.error
+}
+
+// WithErrorCode attaches the code to the error, it is added to the HTTPErrorResponse.
+func WithErrorCode(err error, code ErrorCode) error {
+	if err == nil {
+		return nil
+	}
+
+	return &codedError{error: err, code: code}
+}
+
+// ErrorCodeOf returns the code of the error. The outermost code attached with WithErrorCode is used,
+// then the code of the first registered error the error wraps, then the default code of the HTTP status.
+func ErrorCodeOf(err error) ErrorCode {
+	var e *codedError
+	if errors.As(err, &e) {
+		return e.code
+	}
+
+	if code, found := lookupErrorCode(err); found {
+		return code
+	}
+
+	statusCode := http.StatusInternalServerError
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		statusCode = httpErr.Code
+	}
+
+	if code, exists := statusErrorCodes[statusCode]; exists {
+		return code
+	}
+
+	if statusCode >= http.StatusInternalServerError {
+		return ErrorCodeInternal
+	}
+
+	return ErrorCodeBadRequest
+}
+
+func lookupErrorCode(err error) (ErrorCode, bool) {
+	errorCodesMutex.RLock()
+	defer errorCodesMutex.RUnlock()
+
+	for _, registered := range registeredErrorCodes {
+		if errors.Is(err, registered.target) {
+			return registered.code, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,65 @@
+package httpserver
+
+import (
+	"github.com/pkg/errors"
+)
+
+const (
+	// ErrorReasonMissing is the reason of a parameter that was not specified.
+	ErrorReasonMissing = "missing"
+	// ErrorReasonInvalid is the reason of a parameter that could not be parsed.
+	ErrorReasonInvalid = "invalid"
+	// ErrorReasonOutOfRange is the reason of a parameter that exceeds its bounds.
+	ErrorReasonOutOfRange = "out_of_range"
+	// ErrorReasonNotAllowed is the reason of a parameter that is not one of the allowed values.
+	ErrorReasonNotAllowed = "not_allowed"
+)
+
+// HTTPErrorDetail describes which parameter of a request failed and why.
+type HTTPErrorDetail struct {
+	// Field is the name of the parameter, including the index for array elements, e.g. "outputIds[3]".
+	Field string `json:"field"`
+	// Reason is the machine-readable reason, e.g. ErrorReasonInvalid.
+	Reason string `json:"reason"`
+	// Value is the offending value, if any.
+	Value string `json:"value,omitempty"`
+}
+
+type detailedError struct {
+	error
+	details []HTTPErrorDetail
+}
+
+func (e *detailedError) Unwrap() error {
+	return e.error
+}
+
+// WithErrorDetails attaches details to the error that are added to the HTTPErrorResponse.
+func WithErrorDetails(err error, details ...HTTPErrorDetail) error {
+	if err == nil {
+		return nil
+	}
+
+	return &detailedError{error: err, details: details}
+}
+
+// ErrorDetails returns all details attached to the error chain.
+func ErrorDetails(err error) []HTTPErrorDetail {
+	var details []HTTPErrorDetail
+
+	for err != nil {
+		var e *detailedError
+		if !errors.As(err, &e) {
+			break
+		}
+		details = append(details, e.details...)
+		err = e.error
+	}
+
+	return details
+}
+
+// parameterError attaches the detail of the failed parameter to the error.
+func parameterError(err error, field string, reason string, value string) error {
+	return WithErrorDetails(err, HTTPErrorDetail{Field: field, Reason: reason, Value: value})
+}
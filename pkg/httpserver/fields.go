@@ -0,0 +1,177 @@
+package httpserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// QueryParameterFields is the query parameter used by heavy endpoints to select the fields of the response,
+// e.g. "fields=metadata.blockId,output.amount".
+const QueryParameterFields = "fields"
+
+// fieldTree is a node of the selected fields, a node without children selects the whole value.
+type fieldTree map[string]fieldTree
+
+// FieldSelection are the fields of a response selected by a client, the zero value selects all fields.
+type FieldSelection struct {
+	tree fieldTree
+}
+
+// All reports whether all fields are selected.
+func (s FieldSelection) All() bool {
+	return len(s.tree) == 0
+}
+
+// Selected reports whether the top-level field is part of the selection.
+// It can be used to skip expensive lookups for fields the client did not ask for.
+func (s FieldSelection) Selected(field string) bool {
+	if s.All() {
+		return true
+	}
+
+	_, selected := s.tree[field]
+
+	return selected
+}
+
+// ParseFieldsQueryParam parses the field selection from the "fields" query parameter,
+// a comma-separated list of the JSON names of the fields, nested fields are separated by dots, e.g. "output.amount".
+// Fields of array elements are selected the same way, e.g. "items.outputId" selects the outputId of all items.
+// If allowed fields are given, every selected field must be one of them or be nested below one of them.
+// If the parameter is not specified, all fields are selected.
+func ParseFieldsQueryParam(c echo.Context, allowedFields ...string) (FieldSelection, error) {
+	fieldsParam := c.QueryParam(QueryParameterFields)
+	if fieldsParam == "" {
+		return FieldSelection{}, nil
+	}
+
+	tree := make(fieldTree)
+	for _, field := range strings.Split(fieldsParam, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+
+		segments := strings.Split(field, ".")
+		for _, segment := range segments {
+			if segment == "" {
+				return FieldSelection{}, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid field: %s", field), QueryParameterFields, ErrorReasonInvalid, fieldsParam)
+			}
+		}
+
+		if len(allowedFields) > 0 && !fieldAllowed(field, allowedFields) {
+			return FieldSelection{}, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid field: %s, allowed fields: %s", field, strings.Join(allowedFields, ", ")), QueryParameterFields, ErrorReasonNotAllowed, fieldsParam)
+		}
+
+		tree.add(segments)
+	}
+
+	return FieldSelection{tree: tree}, nil
+}
+
+// fieldAllowed reports whether the field is one of the allowed fields or nested below one of them.
+func fieldAllowed(field string, allowedFields []string) bool {
+	for _, allowedField := range allowedFields {
+		if field == allowedField || strings.HasPrefix(field, allowedField+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// add adds the path to the tree. Selecting a field as a whole supersedes the selection of its nested fields.
+func (t fieldTree) add(segments []string) {
+	node := t
+	for i, segment := range segments {
+		child, exists := node[segment]
+		if exists && child == nil {
+			// the whole value is already selected
+			return
+		}
+
+		if i == len(segments)-1 {
+			node[segment] = nil
+
+			return
+		}
+
+		if !exists {
+			child = make(fieldTree)
+			node[segment] = child
+		}
+		node = child
+	}
+}
+
+// filter removes all values that are not part of the tree, arrays are filtered element-wise.
+func (t fieldTree) filter(value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		filtered := make(map[string]interface{}, len(t))
+		for field, child := range t {
+			fieldValue, exists := typedValue[field]
+			if !exists {
+				continue
+			}
+
+			if child == nil {
+				filtered[field] = fieldValue
+
+				continue
+			}
+			filtered[field] = child.filter(fieldValue)
+		}
+
+		return filtered
+
+	case []interface{}:
+		filtered := make([]interface{}, len(typedValue))
+		for i, element := range typedValue {
+			filtered[i] = t.filter(element)
+		}
+
+		return filtered
+
+	default:
+		// scalars have no fields to select
+		return value
+	}
+}
+
+// Apply returns the JSON representation of the object reduced to the selected fields.
+// Selected fields that don't exist in the object are ignored.
+func (s FieldSelection) Apply(obj interface{}) (interface{}, error) {
+	if s.All() {
+		return obj, nil
+	}
+
+	objJSON, err := json.Marshal(obj)
+	if err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "failed to marshal response: %s", err)
+	}
+
+	// numbers are kept as they are, so large amounts don't lose precision
+	decoder := json.NewDecoder(bytes.NewReader(objJSON))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, errors.WithMessagef(echo.ErrInternalServerError, "failed to decode response: %s", err)
+	}
+
+	return s.tree.filter(value), nil
+}
+
+// JSONResponseWithFields sends the JSON response reduced to the selected fields.
+func JSONResponseWithFields(c echo.Context, statusCode int, result interface{}, fields FieldSelection) error {
+	filtered, err := fields.Apply(result)
+	if err != nil {
+		return err
+	}
+
+	return JSONResponse(c, statusCode, filtered)
+}
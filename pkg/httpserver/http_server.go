@@ -2,6 +2,7 @@ package httpserver
 
 import (
 	"fmt"
+	"math/big"
 	"net"
 	"net/http"
 	"strconv"
@@ -13,7 +14,9 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/pkg/errors"
 
+	"github.com/iotaledger/hive.go/core/generics/options"
 	"github.com/iotaledger/hive.go/core/logger"
+	"github.com/iotaledger/hive.go/serializer/v2"
 	iotago "github.com/iotaledger/iota.go/v3"
 )
 
@@ -38,10 +41,48 @@ func JSONResponse(c echo.Context, statusCode int, result interface{}) error {
 	return c.JSON(statusCode, result)
 }
 
+// SendResponseByHeader sends the object either as JSON or, if requested via the Accept header,
+// as the raw bytes of the iota serializer (MIMEApplicationVendorIOTASerializerV1).
+// JSON is sent if no or an unsupported content type is requested.
+// If the object does not implement serializer.Serializable, the binary content type is not acceptable.
+func SendResponseByHeader(c echo.Context, protoParams *iotago.ProtocolParameters, obj interface{}, httpStatusCode ...int) error {
+	statusCode := http.StatusOK
+	if len(httpStatusCode) > 0 {
+		statusCode = httpStatusCode[0]
+	}
+
+	mimeType, err := GetAcceptHeaderContentType(c, MIMEApplicationVendorIOTASerializerV1, echo.MIMEApplicationJSON)
+	if err != nil && !errors.Is(err, ErrNotAcceptable) {
+		return err
+	}
+
+	if mimeType != MIMEApplicationVendorIOTASerializerV1 {
+		return JSONResponse(c, statusCode, obj)
+	}
+
+	serializable, ok := obj.(serializer.Serializable)
+	if !ok {
+		return errors.WithMessagef(ErrNotAcceptable, "%s is not supported for this response", MIMEApplicationVendorIOTASerializerV1)
+	}
+
+	data, err := serializable.Serialize(serializer.DeSeriModePerformValidation, protoParams)
+	if err != nil {
+		return errors.WithMessagef(echo.ErrInternalServerError, "failed to serialize response: %s", err)
+	}
+
+	return c.Blob(statusCode, MIMEApplicationVendorIOTASerializerV1, data)
+}
+
 // HTTPErrorResponse defines the error struct for the HTTPErrorResponseEnvelope.
 type HTTPErrorResponse struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
+	// ErrorCode is the stable, machine-readable code of the error, e.g. ErrorCodeInvalidParameter.
+	ErrorCode ErrorCode `json:"errorCode"`
+	// Details describe which parameters of the request failed and why, if available.
+	Details []HTTPErrorDetail `json:"details,omitempty"`
+	// RequestID is the ID of the failed request, it is also part of the request logs.
+	RequestID string `json:"requestId,omitempty"`
 }
 
 // HTTPErrorResponseEnvelope defines the error response schema for node API responses.
@@ -64,13 +105,25 @@ func errorHandler() func(error, echo.Context) {
 			message = fmt.Sprintf("internal server error. error: %s", err)
 		}
 
-		_ = c.JSON(statusCode, HTTPErrorResponseEnvelope{Error: HTTPErrorResponse{Code: strconv.Itoa(statusCode), Message: message}})
+		_ = c.JSON(statusCode, HTTPErrorResponseEnvelope{Error: HTTPErrorResponse{Code: strconv.Itoa(statusCode), Message: message, ErrorCode: ErrorCodeOf(err), Details: ErrorDetails(err), RequestID: RequestID(c)}})
 	}
 }
 
 // NewEcho returns a new Echo instance.
-// It hides the banner, adds a default HTTPErrorHandler and the Recover middleware.
-func NewEcho(logger *logger.Logger, onHTTPError func(err error, c echo.Context), debugRequestLoggerEnabled bool) *echo.Echo {
+// It hides the banner, adds a default HTTPErrorHandler, the RequestIDMiddleware and the Recover middleware.
+// Sensitive query parameters and headers are redacted in the debug request logs.
+// The CORS middleware is added if it is enabled via WithCORS.
+// The compression middleware is added if it is enabled via WithCompression.
+// Request bodies are limited if it is enabled via WithBodyLimit.
+// Requests are traced if it is enabled via WithTracing.
+// Requests are written to the access log if one is given via WithAccessLog.
+func NewEcho(logger *logger.Logger, onHTTPError func(err error, c echo.Context), debugRequestLoggerEnabled bool, opts ...options.Option[EchoOptions]) *echo.Echo {
+	echoOpts := options.Apply(&EchoOptions{
+		redactedQueryParams: DefaultRedactedQueryParams,
+		redactedHeaders:     DefaultRedactedHeaders,
+	}, opts)
+	redactor := NewRedactor(echoOpts.redactedQueryParams, echoOpts.redactedHeaders)
+
 	e := echo.New()
 	e.HideBanner = true
 
@@ -82,8 +135,30 @@ func NewEcho(logger *logger.Logger, onHTTPError func(err error, c echo.Context),
 		apiErrorHandler(err, c)
 	}
 
+	// the ID is assigned before routing, so also unknown routes are answered with it
+	e.Pre(RequestIDMiddleware())
 	e.Use(middleware.Recover())
 
+	if echoOpts.tracingEnabled {
+		e.Use(TracingMiddleware(echoOpts.tracingServerName, echoOpts.tracerProvider, echoOpts.propagator))
+	}
+
+	if echoOpts.corsMiddleware != nil {
+		e.Use(echoOpts.corsMiddleware)
+	}
+
+	if echoOpts.compressionEnabled {
+		e.Use(CompressionMiddleware(echoOpts.compression...))
+	}
+
+	if echoOpts.bodyLimitEnabled {
+		e.Use(groupBodyLimitMiddleware(echoOpts.bodyLimit, echoOpts.bodyGroupLimits))
+	}
+
+	if echoOpts.accessLogger != nil {
+		e.Use(echoOpts.accessLogger.middleware(redactor))
+	}
+
 	if debugRequestLoggerEnabled {
 		e.Use(middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
 			LogLatency:      true,
@@ -91,16 +166,23 @@ func NewEcho(logger *logger.Logger, onHTTPError func(err error, c echo.Context),
 			LogMethod:       true,
 			LogURI:          true,
 			LogUserAgent:    true,
+			LogRequestID:    true,
 			LogStatus:       true,
 			LogError:        true,
 			LogResponseSize: true,
+			LogHeaders:      echoOpts.loggedHeaders,
 			LogValuesFunc: func(c echo.Context, v middleware.RequestLoggerValues) error {
 				errString := ""
 				if v.Error != nil {
 					errString = fmt.Sprintf("error: \"%s\", ", v.Error.Error())
 				}
 
-				logger.Debugf("%d %s \"%s\", %sagent: \"%s\", remoteIP: %s, responseSize: %s, took: %v", v.Status, v.Method, v.URI, errString, v.UserAgent, v.RemoteIP, humanize.Bytes(uint64(v.ResponseSize)), v.Latency.Truncate(time.Millisecond))
+				headersString := ""
+				if len(v.Headers) > 0 {
+					headersString = fmt.Sprintf("headers: \"%s\", ", redactor.RedactHeaders(v.Headers))
+				}
+
+				logger.Debugf("%d %s \"%s\", %s%sagent: \"%s\", remoteIP: %s, requestID: %s, responseSize: %s, took: %v", v.Status, v.Method, redactor.RedactURI(v.URI), errString, headersString, v.UserAgent, v.RemoteIP, v.RequestID, humanize.Bytes(uint64(v.ResponseSize)), v.Latency.Truncate(time.Millisecond))
 
 				return nil
 			},
@@ -133,43 +215,181 @@ func GetRequestContentType(c echo.Context, supportedContentTypes ...string) (str
 }
 
 func ParseBoolQueryParam(c echo.Context, paramName string) (bool, error) {
-	return strconv.ParseBool(c.QueryParam(paramName))
+	boolString := c.QueryParam(paramName)
+	value, err := strconv.ParseBool(boolString)
+	if err != nil {
+		return false, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid value: %s, error: %s", boolString, err), paramName, ErrorReasonInvalid, boolString)
+	}
+
+	return value, nil
 }
 
 func ParseUint32QueryParam(c echo.Context, paramName string, maxValue ...uint32) (uint32, error) {
 	intString := strings.ToLower(c.QueryParam(paramName))
 	if intString == "" {
-		return 0, errors.WithMessagef(ErrInvalidParameter, "parameter \"%s\" not specified", paramName)
+		return 0, parameterError(errors.WithMessagef(ErrInvalidParameter, "parameter \"%s\" not specified", paramName), paramName, ErrorReasonMissing, "")
 	}
 
 	value, err := strconv.ParseUint(intString, 10, 32)
 	if err != nil {
-		return 0, errors.WithMessagef(ErrInvalidParameter, "invalid value: %s, error: %s", intString, err)
+		return 0, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid value: %s, error: %s", intString, err), paramName, ErrorReasonInvalid, intString)
 	}
 
 	if len(maxValue) > 0 {
 		if uint32(value) > maxValue[0] {
-			return 0, errors.WithMessagef(ErrInvalidParameter, "invalid value: %s, higher than the max number %d", intString, maxValue)
+			return 0, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid value: %s, higher than the max number %d", intString, maxValue), paramName, ErrorReasonOutOfRange, intString)
 		}
 	}
 
 	return uint32(value), nil
 }
 
+// ParseUint64QueryParam parses the uint64 value of the given query parameter, e.g. for amounts or unix nanos.
+func ParseUint64QueryParam(c echo.Context, paramName string, maxValue ...uint64) (uint64, error) {
+	intString := strings.ToLower(c.QueryParam(paramName))
+	if intString == "" {
+		return 0, parameterError(errors.WithMessagef(ErrInvalidParameter, "parameter \"%s\" not specified", paramName), paramName, ErrorReasonMissing, "")
+	}
+
+	value, err := strconv.ParseUint(intString, 10, 64)
+	if err != nil {
+		return 0, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid value: %s, error: %s", intString, err), paramName, ErrorReasonInvalid, intString)
+	}
+
+	if len(maxValue) > 0 && value > maxValue[0] {
+		return 0, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid value: %s, higher than the max number %d", intString, maxValue[0]), paramName, ErrorReasonOutOfRange, intString)
+	}
+
+	return value, nil
+}
+
+// ParseInt64QueryParam parses the int64 value of the given query parameter.
+// If bounds are given, the first one is the min and the second one the max value.
+func ParseInt64QueryParam(c echo.Context, paramName string, bounds ...int64) (int64, error) {
+	intString := strings.ToLower(c.QueryParam(paramName))
+	if intString == "" {
+		return 0, parameterError(errors.WithMessagef(ErrInvalidParameter, "parameter \"%s\" not specified", paramName), paramName, ErrorReasonMissing, "")
+	}
+
+	value, err := strconv.ParseInt(intString, 10, 64)
+	if err != nil {
+		return 0, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid value: %s, error: %s", intString, err), paramName, ErrorReasonInvalid, intString)
+	}
+
+	if len(bounds) > 0 && value < bounds[0] {
+		return 0, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid value: %s, lower than the min number %d", intString, bounds[0]), paramName, ErrorReasonOutOfRange, intString)
+	}
+
+	if len(bounds) > 1 && value > bounds[1] {
+		return 0, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid value: %s, higher than the max number %d", intString, bounds[1]), paramName, ErrorReasonOutOfRange, intString)
+	}
+
+	return value, nil
+}
+
+// ParseBigIntQueryParam parses the arbitrary-precision integer of the given query parameter, e.g. native token amounts.
+// The value is either decimal or 0x prefixed hex encoded. Negative values are only allowed if allowNegative is set.
+// If a max value is given, the value must not be higher.
+func ParseBigIntQueryParam(c echo.Context, paramName string, allowNegative bool, maxValue ...*big.Int) (*big.Int, error) {
+	intString := strings.ToLower(c.QueryParam(paramName))
+	if intString == "" {
+		return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "parameter \"%s\" not specified", paramName), paramName, ErrorReasonMissing, "")
+	}
+
+	// base 0 would also accept octal and binary notations, which are not meant for amounts
+	base := 10
+	digits := intString
+	negative := strings.HasPrefix(digits, "-")
+	digits = strings.TrimPrefix(digits, "-")
+	if strings.HasPrefix(digits, "0x") {
+		base = 16
+		digits = strings.TrimPrefix(digits, "0x")
+	}
+
+	value, ok := new(big.Int).SetString(digits, base)
+	if !ok || strings.HasPrefix(digits, "+") || strings.HasPrefix(digits, "-") {
+		return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid integer: %s", intString), paramName, ErrorReasonInvalid, intString)
+	}
+	if negative {
+		value.Neg(value)
+	}
+
+	if !allowNegative && value.Sign() < 0 {
+		return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid integer: %s, must not be negative", intString), paramName, ErrorReasonOutOfRange, intString)
+	}
+
+	if len(maxValue) > 0 && maxValue[0] != nil && value.Cmp(maxValue[0]) > 0 {
+		return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid integer: %s, higher than the max number %s", intString, maxValue[0]), paramName, ErrorReasonOutOfRange, intString)
+	}
+
+	return value, nil
+}
+
+// ParseDurationQueryParam parses a human-readable duration like "30s" or "5m" from the given query parameter.
+// If bounds are given, the first one is the min and the second one the max duration.
+func ParseDurationQueryParam(c echo.Context, paramName string, bounds ...time.Duration) (time.Duration, error) {
+	durationString := strings.ToLower(c.QueryParam(paramName))
+	if durationString == "" {
+		return 0, parameterError(errors.WithMessagef(ErrInvalidParameter, "parameter \"%s\" not specified", paramName), paramName, ErrorReasonMissing, "")
+	}
+
+	duration, err := time.ParseDuration(durationString)
+	if err != nil {
+		return 0, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid duration: %s, error: %s", durationString, err), paramName, ErrorReasonInvalid, durationString)
+	}
+
+	if len(bounds) > 0 && duration < bounds[0] {
+		return 0, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid duration: %s, lower than the min duration %v", durationString, bounds[0]), paramName, ErrorReasonOutOfRange, durationString)
+	}
+
+	if len(bounds) > 1 && duration > bounds[1] {
+		return 0, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid duration: %s, higher than the max duration %v", durationString, bounds[1]), paramName, ErrorReasonOutOfRange, durationString)
+	}
+
+	return duration, nil
+}
+
 func ParseHexQueryParam(c echo.Context, paramName string, maxLen int) ([]byte, error) {
 	param := c.QueryParam(paramName)
 
 	paramBytes, err := iotago.DecodeHex(param)
 	if err != nil {
-		return nil, errors.WithMessagef(ErrInvalidParameter, "invalid param: %s, error: %s", paramName, err)
+		return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid param: %s, error: %s", paramName, err), paramName, ErrorReasonInvalid, param)
 	}
 	if len(paramBytes) > maxLen {
-		return nil, errors.WithMessage(ErrInvalidParameter, fmt.Sprintf("query parameter %s too long, max. %d bytes but is %d", paramName, maxLen, len(paramBytes)))
+		return nil, parameterError(errors.WithMessage(ErrInvalidParameter, fmt.Sprintf("query parameter %s too long, max. %d bytes but is %d", paramName, maxLen, len(paramBytes))), paramName, ErrorReasonOutOfRange, param)
 	}
 
 	return paramBytes, nil
 }
 
+// ParseTagParam parses the hex encoded tag of the given path parameter, it must not exceed the max tag length.
+func ParseTagParam(c echo.Context, paramName string) ([]byte, error) {
+	return parseTag(c.Param(paramName), paramName)
+}
+
+// ParseTagQueryParam parses the hex encoded tag of the given query parameter, it must not exceed the max tag length.
+func ParseTagQueryParam(c echo.Context, paramName string) ([]byte, error) {
+	return parseTag(c.QueryParam(paramName), paramName)
+}
+
+func parseTag(tagHex string, paramName string) ([]byte, error) {
+	if tagHex == "" {
+		return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "parameter \"%s\" not specified", paramName), paramName, ErrorReasonMissing, "")
+	}
+
+	tag, err := iotago.DecodeHex(strings.ToLower(tagHex))
+	if err != nil {
+		return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid tag: %s, error: %s", tagHex, err), paramName, ErrorReasonInvalid, tagHex)
+	}
+
+	if len(tag) > iotago.MaxTagLength {
+		return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid tag: %s, max. %d bytes but is %d", tagHex, iotago.MaxTagLength, len(tag)), paramName, ErrorReasonOutOfRange, tagHex)
+	}
+
+	return tag, nil
+}
+
 func ParseUnixTimestampQueryParam(c echo.Context, paramName string) (time.Time, error) {
 	timestamp, err := ParseUint32QueryParam(c, paramName)
 	if err != nil {
@@ -184,22 +404,149 @@ func ParseBech32AddressQueryParam(c echo.Context, prefix iotago.NetworkPrefix, p
 
 	hrp, bech32Address, err := iotago.ParseBech32(addressParam)
 	if err != nil {
-		return nil, errors.WithMessagef(ErrInvalidParameter, "invalid address: %s, error: %s", addressParam, err)
+		return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid address: %s, error: %s", addressParam, err), paramName, ErrorReasonInvalid, addressParam)
 	}
 
 	if hrp != prefix {
-		return nil, errors.WithMessagef(ErrInvalidParameter, "invalid bech32 address, expected prefix: %s", prefix)
+		return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid bech32 address, expected prefix: %s", prefix), paramName, ErrorReasonInvalid, addressParam)
 	}
 
 	return bech32Address, nil
 }
 
+func ParseBech32AddressParam(c echo.Context, prefix iotago.NetworkPrefix, paramName string) (iotago.Address, error) {
+	addressParam := strings.ToLower(c.Param(paramName))
+
+	hrp, bech32Address, err := iotago.ParseBech32(addressParam)
+	if err != nil {
+		return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid address: %s, error: %s", addressParam, err), paramName, ErrorReasonInvalid, addressParam)
+	}
+
+	if hrp != prefix {
+		return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid bech32 address, expected prefix: %s", prefix), paramName, ErrorReasonInvalid, addressParam)
+	}
+
+	return bech32Address, nil
+}
+
+// ParseAddressParam parses the address of the given path parameter.
+// The address is either bech32 encoded with the given prefix, or the 0x prefixed hex encoded serialized address,
+// i.e. the address type byte followed by the Ed25519 public key hash, the alias ID or the NFT ID.
+func ParseAddressParam(c echo.Context, prefix iotago.NetworkPrefix, paramName string) (iotago.Address, error) {
+	return parseAddress(c.Param(paramName), prefix, paramName)
+}
+
+// ParseAddressQueryParam parses the address of the given query parameter, see ParseAddressParam for the accepted formats.
+func ParseAddressQueryParam(c echo.Context, prefix iotago.NetworkPrefix, paramName string) (iotago.Address, error) {
+	return parseAddress(c.QueryParam(paramName), prefix, paramName)
+}
+
+func parseAddress(addressParam string, prefix iotago.NetworkPrefix, paramName string) (iotago.Address, error) {
+	addressParam = strings.ToLower(addressParam)
+	if addressParam == "" {
+		return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "parameter \"%s\" not specified", paramName), paramName, ErrorReasonMissing, "")
+	}
+
+	if !strings.HasPrefix(addressParam, "0x") {
+		hrp, bech32Address, err := iotago.ParseBech32(addressParam)
+		if err != nil {
+			return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid address: %s, error: %s", addressParam, err), paramName, ErrorReasonInvalid, addressParam)
+		}
+
+		if hrp != prefix {
+			return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid bech32 address, expected prefix: %s", prefix), paramName, ErrorReasonInvalid, addressParam)
+		}
+
+		return bech32Address, nil
+	}
+
+	addressBytes, err := iotago.DecodeHex(addressParam)
+	if err != nil {
+		return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid address: %s, error: %s", addressParam, err), paramName, ErrorReasonInvalid, addressParam)
+	}
+
+	if len(addressBytes) == 0 {
+		return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid address: %s, address type missing", addressParam), paramName, ErrorReasonInvalid, addressParam)
+	}
+
+	address, err := iotago.AddressSelector(uint32(addressBytes[0]))
+	if err != nil {
+		return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid address: %s, error: %s", addressParam, err), paramName, ErrorReasonInvalid, addressParam)
+	}
+
+	if len(addressBytes) != address.Size() {
+		return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid address: %s, invalid length: %d", addressParam, len(addressBytes)), paramName, ErrorReasonInvalid, addressParam)
+	}
+
+	if _, err := address.Deserialize(addressBytes, serializer.DeSeriModePerformValidation, nil); err != nil {
+		return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid address: %s, error: %s", addressParam, err), paramName, ErrorReasonInvalid, addressParam)
+	}
+
+	return address, nil
+}
+
+// QueryParameterSort is the query parameter used by list endpoints to specify the sort order.
+const QueryParameterSort = "sort"
+
+// SortDirection is the direction of a sort order.
+type SortDirection string
+
+const (
+	// SortAscending sorts in ascending order, which is the default.
+	SortAscending SortDirection = "asc"
+	// SortDescending sorts in descending order.
+	SortDescending SortDirection = "desc"
+)
+
+// SortOrder is the sort order of a list endpoint.
+type SortOrder struct {
+	// Field is the field to sort by, it is empty if no sort order was specified.
+	Field string
+	// Direction is the direction to sort in.
+	Direction SortDirection
+}
+
+// Ascending reports whether the sort order is ascending.
+func (s SortOrder) Ascending() bool {
+	return s.Direction != SortDescending
+}
+
+// ParseSortQueryParam parses the sort order from the "sort" query parameter in the form "field" or "field:asc|desc".
+// The field must be one of the allowed fields. If the parameter is not specified, an empty SortOrder is returned.
+func ParseSortQueryParam(c echo.Context, allowedFields ...string) (SortOrder, error) {
+	sortParam := c.QueryParam(QueryParameterSort)
+	if sortParam == "" {
+		return SortOrder{}, nil
+	}
+
+	field, direction, hasDirection := strings.Cut(sortParam, ":")
+
+	sortOrder := SortOrder{Field: field, Direction: SortAscending}
+	if hasDirection {
+		switch SortDirection(strings.ToLower(direction)) {
+		case SortAscending:
+		case SortDescending:
+			sortOrder.Direction = SortDescending
+		default:
+			return SortOrder{}, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid sort direction: %s, expected \"%s\" or \"%s\"", direction, SortAscending, SortDescending), QueryParameterSort, ErrorReasonNotAllowed, sortParam)
+		}
+	}
+
+	for _, allowedField := range allowedFields {
+		if field == allowedField {
+			return sortOrder, nil
+		}
+	}
+
+	return SortOrder{}, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid sort field: %s, allowed fields: %s", field, strings.Join(allowedFields, ", ")), QueryParameterSort, ErrorReasonNotAllowed, sortParam)
+}
+
 func ParseBlockIDParam(c echo.Context, paramName string) (iotago.BlockID, error) {
 	blockIDHex := strings.ToLower(c.Param(paramName))
 
 	blockID, err := iotago.BlockIDFromHexString(blockIDHex)
 	if err != nil {
-		return iotago.EmptyBlockID(), errors.WithMessagef(ErrInvalidParameter, "invalid block ID: %s, error: %s", blockIDHex, err)
+		return iotago.EmptyBlockID(), parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid block ID: %s, error: %s", blockIDHex, err), paramName, ErrorReasonInvalid, blockIDHex)
 	}
 
 	return blockID, nil
@@ -207,15 +554,10 @@ func ParseBlockIDParam(c echo.Context, paramName string) (iotago.BlockID, error)
 
 func ParseTransactionIDParam(c echo.Context, paramName string) (iotago.TransactionID, error) {
 	transactionID := iotago.TransactionID{}
-	transactionIDHex := strings.ToLower(c.Param(paramName))
 
-	transactionIDBytes, err := iotago.DecodeHex(transactionIDHex)
+	transactionIDBytes, err := parseFixedHex(c.Param(paramName), paramName, "transaction ID", iotago.TransactionIDLength)
 	if err != nil {
-		return transactionID, errors.WithMessagef(ErrInvalidParameter, "invalid transaction ID: %s, error: %s", transactionIDHex, err)
-	}
-
-	if len(transactionIDBytes) != iotago.TransactionIDLength {
-		return transactionID, errors.WithMessagef(ErrInvalidParameter, "invalid transaction ID: %s, invalid length: %d", transactionIDHex, len(transactionIDBytes))
+		return transactionID, err
 	}
 
 	copy(transactionID[:], transactionIDBytes)
@@ -228,7 +570,7 @@ func ParseOutputIDParam(c echo.Context, paramName string) (iotago.OutputID, erro
 
 	outputID, err := iotago.OutputIDFromHex(outputIDParam)
 	if err != nil {
-		return iotago.OutputID{}, errors.WithMessagef(ErrInvalidParameter, "invalid output ID: %s, error: %s", outputIDParam, err)
+		return iotago.OutputID{}, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid output ID: %s, error: %s", outputIDParam, err), paramName, ErrorReasonInvalid, outputIDParam)
 	}
 
 	return outputID, nil
@@ -237,27 +579,92 @@ func ParseOutputIDParam(c echo.Context, paramName string) (iotago.OutputID, erro
 func ParseMilestoneIndexParam(c echo.Context, paramName string) (iotago.MilestoneIndex, error) {
 	milestoneIndex := strings.ToLower(c.Param(paramName))
 	if milestoneIndex == "" {
-		return 0, errors.WithMessagef(ErrInvalidParameter, "parameter \"%s\" not specified", paramName)
+		return 0, parameterError(errors.WithMessagef(ErrInvalidParameter, "parameter \"%s\" not specified", paramName), paramName, ErrorReasonMissing, "")
 	}
 
 	msIndex, err := strconv.ParseUint(milestoneIndex, 10, 32)
 	if err != nil {
-		return 0, errors.WithMessagef(ErrInvalidParameter, "invalid milestone index: %s, error: %s", milestoneIndex, err)
+		return 0, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid milestone index: %s, error: %s", milestoneIndex, err), paramName, ErrorReasonInvalid, milestoneIndex)
 	}
 
 	return iotago.MilestoneIndex(msIndex), nil
 }
 
-func ParseMilestoneIDParam(c echo.Context, paramName string) (*iotago.MilestoneID, error) {
-	milestoneIDHex := strings.ToLower(c.Param(paramName))
+// ParseMilestoneIndexQueryParam parses the milestone index of the given query parameter.
+func ParseMilestoneIndexQueryParam(c echo.Context, paramName string) (iotago.MilestoneIndex, error) {
+	milestoneIndex := strings.ToLower(c.QueryParam(paramName))
+	if milestoneIndex == "" {
+		return 0, parameterError(errors.WithMessagef(ErrInvalidParameter, "parameter \"%s\" not specified", paramName), paramName, ErrorReasonMissing, "")
+	}
+
+	msIndex, err := strconv.ParseUint(milestoneIndex, 10, 32)
+	if err != nil {
+		return 0, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid milestone index: %s, error: %s", milestoneIndex, err), paramName, ErrorReasonInvalid, milestoneIndex)
+	}
+
+	return iotago.MilestoneIndex(msIndex), nil
+}
+
+// ParseMilestoneRangeQueryParams parses an inclusive milestone range from the given query parameters.
+// The start index must not be higher than the end index, and if a max span is given,
+// the range must not contain more than maxSpan milestones.
+func ParseMilestoneRangeQueryParams(c echo.Context, startParamName string, endParamName string, maxSpan ...uint32) (iotago.MilestoneIndex, iotago.MilestoneIndex, error) {
+	startIndex, err := ParseMilestoneIndexQueryParam(c, startParamName)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	endIndex, err := ParseMilestoneIndexQueryParam(c, endParamName)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if startIndex > endIndex {
+		endParam := c.QueryParam(endParamName)
+
+		return 0, 0, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid milestone range: %s (%d) must not be higher than %s (%d)", startParamName, startIndex, endParamName, endIndex), endParamName, ErrorReasonOutOfRange, endParam)
+	}
+
+	if len(maxSpan) > 0 && uint64(endIndex)-uint64(startIndex)+1 > uint64(maxSpan[0]) {
+		endParam := c.QueryParam(endParamName)
+
+		return 0, 0, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid milestone range: %d-%d, spans more than the max. %d milestones", startIndex, endIndex, maxSpan[0]), endParamName, ErrorReasonOutOfRange, endParam)
+	}
+
+	return startIndex, endIndex, nil
+}
+
+// ParseFixedHexParam parses the hex encoded value of the given path parameter, which must have exactly the given length in bytes.
+// It can be used to parse fixed-size identifiers, e.g. by copying the result into the identifier array.
+func ParseFixedHexParam(c echo.Context, paramName string, length int) ([]byte, error) {
+	return parseFixedHex(c.Param(paramName), paramName, "value", length)
+}
+
+// ParseFixedHexQueryParam parses the hex encoded value of the given query parameter, which must have exactly the given length in bytes.
+func ParseFixedHexQueryParam(c echo.Context, paramName string, length int) ([]byte, error) {
+	return parseFixedHex(c.QueryParam(paramName), paramName, "value", length)
+}
+
+// parseFixedHex parses a hex encoded value with the given length in bytes, the description names the value in errors.
+func parseFixedHex(valueHex string, paramName string, description string, length int) ([]byte, error) {
+	valueHex = strings.ToLower(valueHex)
 
-	milestoneIDBytes, err := iotago.DecodeHex(milestoneIDHex)
+	valueBytes, err := iotago.DecodeHex(valueHex)
 	if err != nil {
-		return nil, errors.WithMessagef(ErrInvalidParameter, "invalid milestone ID: %s, error: %s", milestoneIDHex, err)
+		return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid %s: %s, error: %s", description, valueHex, err), paramName, ErrorReasonInvalid, valueHex)
 	}
 
-	if len(milestoneIDBytes) != iotago.MilestoneIDLength {
-		return nil, errors.WithMessagef(ErrInvalidParameter, "invalid milestone ID: %s, invalid length: %d", milestoneIDHex, len(milestoneIDBytes))
+	if len(valueBytes) != length {
+		return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid %s: %s, invalid length: %d", description, valueHex, len(valueBytes)), paramName, ErrorReasonInvalid, valueHex)
+	}
+
+	return valueBytes, nil
+}
+
+func ParseMilestoneIDParam(c echo.Context, paramName string) (*iotago.MilestoneID, error) {
+	milestoneIDBytes, err := parseFixedHex(c.Param(paramName), paramName, "milestone ID", iotago.MilestoneIDLength)
+	if err != nil {
+		return nil, err
 	}
 
 	var milestoneID iotago.MilestoneID
@@ -267,15 +674,9 @@ func ParseMilestoneIDParam(c echo.Context, paramName string) (*iotago.MilestoneI
 }
 
 func ParseAliasIDParam(c echo.Context, paramName string) (*iotago.AliasID, error) {
-	aliasIDParam := strings.ToLower(c.Param(paramName))
-
-	aliasIDBytes, err := iotago.DecodeHex(aliasIDParam)
+	aliasIDBytes, err := parseFixedHex(c.Param(paramName), paramName, "alias ID", iotago.AliasIDLength)
 	if err != nil {
-		return nil, errors.WithMessagef(ErrInvalidParameter, "invalid alias ID: %s, error: %s", aliasIDParam, err)
-	}
-
-	if len(aliasIDBytes) != iotago.AliasIDLength {
-		return nil, errors.WithMessagef(ErrInvalidParameter, "invalid alias ID: %s, error: %s", aliasIDParam, err)
+		return nil, err
 	}
 
 	var aliasID iotago.AliasID
@@ -285,15 +686,9 @@ func ParseAliasIDParam(c echo.Context, paramName string) (*iotago.AliasID, error
 }
 
 func ParseNFTIDParam(c echo.Context, paramName string) (*iotago.NFTID, error) {
-	nftIDParam := strings.ToLower(c.Param(paramName))
-
-	nftIDBytes, err := iotago.DecodeHex(nftIDParam)
+	nftIDBytes, err := parseFixedHex(c.Param(paramName), paramName, "NFT ID", iotago.NFTIDLength)
 	if err != nil {
-		return nil, errors.WithMessagef(ErrInvalidParameter, "invalid NFT ID: %s, error: %s", nftIDParam, err)
-	}
-
-	if len(nftIDBytes) != iotago.NFTIDLength {
-		return nil, errors.WithMessagef(ErrInvalidParameter, "invalid NFT ID: %s, error: %s", nftIDParam, err)
+		return nil, err
 	}
 
 	var nftID iotago.NFTID
@@ -303,15 +698,9 @@ func ParseNFTIDParam(c echo.Context, paramName string) (*iotago.NFTID, error) {
 }
 
 func ParseFoundryIDParam(c echo.Context, paramName string) (*iotago.FoundryID, error) {
-	foundryIDParam := strings.ToLower(c.Param(paramName))
-
-	foundryIDBytes, err := iotago.DecodeHex(foundryIDParam)
+	foundryIDBytes, err := parseFixedHex(c.Param(paramName), paramName, "foundry ID", iotago.FoundryIDLength)
 	if err != nil {
-		return nil, errors.WithMessagef(ErrInvalidParameter, "invalid foundry ID: %s, error: %s", foundryIDParam, err)
-	}
-
-	if len(foundryIDBytes) != iotago.FoundryIDLength {
-		return nil, errors.WithMessagef(ErrInvalidParameter, "invalid foundry ID: %s, error: %s", foundryIDParam, err)
+		return nil, err
 	}
 
 	var foundryID iotago.FoundryID
@@ -320,6 +709,24 @@ func ParseFoundryIDParam(c echo.Context, paramName string) (*iotago.FoundryID, e
 	return &foundryID, nil
 }
 
+func ParseNativeTokenIDParam(c echo.Context, paramName string) (*iotago.NativeTokenID, error) {
+	nativeTokenIDBytes, err := parseFixedHex(c.Param(paramName), paramName, "native token ID", iotago.NativeTokenIDLength)
+	if err != nil {
+		return nil, err
+	}
+
+	var nativeTokenID iotago.NativeTokenID
+	copy(nativeTokenID[:], nativeTokenIDBytes)
+
+	return &nativeTokenID, nil
+}
+
+// GetURL returns the URL of the path served on the host and port.
+// For the "unix" protocol the host is the path of the socket file and the port is ignored.
 func GetURL(protocol string, host string, port uint16, path ...string) string {
+	if protocol == ProtocolUnix {
+		return GetUnixSocketURL(host, path...)
+	}
+
 	return fmt.Sprintf("%s://%s%s", protocol, net.JoinHostPort(host, strconv.Itoa(int(port))), strings.Join(path, "/"))
 }
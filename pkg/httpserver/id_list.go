@@ -0,0 +1,96 @@
+package httpserver
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// splitListQueryParam splits the comma-separated values of the given query parameter.
+// Empty values are ignored, e.g. those of a trailing comma.
+func splitListQueryParam(c echo.Context, paramName string, maxCount int) ([]string, error) {
+	listParam := strings.ToLower(c.QueryParam(paramName))
+
+	values := make([]string, 0)
+	for _, value := range strings.Split(listParam, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	if len(values) == 0 {
+		return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "parameter \"%s\" not specified", paramName), paramName, ErrorReasonMissing, "")
+	}
+
+	if len(values) > maxCount {
+		return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "too many values in parameter \"%s\", max. %d but is %d", paramName, maxCount, len(values)), paramName, ErrorReasonOutOfRange, listParam)
+	}
+
+	return values, nil
+}
+
+// ParseBlockIDsQueryParam parses the comma-separated list of block IDs of the given query parameter,
+// it must contain at least one and at most maxCount IDs.
+func ParseBlockIDsQueryParam(c echo.Context, paramName string, maxCount int) (iotago.BlockIDs, error) {
+	values, err := splitListQueryParam(c, paramName, maxCount)
+	if err != nil {
+		return nil, err
+	}
+
+	blockIDs := make(iotago.BlockIDs, 0, len(values))
+	for _, blockIDHex := range values {
+		blockID, err := iotago.BlockIDFromHexString(blockIDHex)
+		if err != nil {
+			return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid block ID: %s, error: %s", blockIDHex, err), paramName, ErrorReasonInvalid, blockIDHex)
+		}
+		blockIDs = append(blockIDs, blockID)
+	}
+
+	return blockIDs, nil
+}
+
+// ParseOutputIDsQueryParam parses the comma-separated list of output IDs of the given query parameter,
+// it must contain at least one and at most maxCount IDs.
+func ParseOutputIDsQueryParam(c echo.Context, paramName string, maxCount int) (iotago.OutputIDs, error) {
+	values, err := splitListQueryParam(c, paramName, maxCount)
+	if err != nil {
+		return nil, err
+	}
+
+	outputIDs := make(iotago.OutputIDs, 0, len(values))
+	for _, outputIDHex := range values {
+		outputID, err := iotago.OutputIDFromHex(outputIDHex)
+		if err != nil {
+			return nil, parameterError(errors.WithMessagef(ErrInvalidParameter, "invalid output ID: %s, error: %s", outputIDHex, err), paramName, ErrorReasonInvalid, outputIDHex)
+		}
+		outputIDs = append(outputIDs, outputID)
+	}
+
+	return outputIDs, nil
+}
+
+// ParseTransactionIDsQueryParam parses the comma-separated list of transaction IDs of the given query parameter,
+// it must contain at least one and at most maxCount IDs.
+func ParseTransactionIDsQueryParam(c echo.Context, paramName string, maxCount int) ([]iotago.TransactionID, error) {
+	values, err := splitListQueryParam(c, paramName, maxCount)
+	if err != nil {
+		return nil, err
+	}
+
+	transactionIDs := make([]iotago.TransactionID, 0, len(values))
+	for _, transactionIDHex := range values {
+		transactionIDBytes, err := parseFixedHex(transactionIDHex, paramName, "transaction ID", iotago.TransactionIDLength)
+		if err != nil {
+			return nil, err
+		}
+
+		var transactionID iotago.TransactionID
+		copy(transactionID[:], transactionIDBytes)
+		transactionIDs = append(transactionIDs, transactionID)
+	}
+
+	return transactionIDs, nil
+}
@@ -0,0 +1,165 @@
+package httpserver
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+)
+
+var (
+	// ErrJWTMissing is returned if the request does not contain a JWT.
+	ErrJWTMissing = echo.NewHTTPError(http.StatusUnauthorized, "missing or malformed jwt")
+	// ErrJWTInvalid is returned if the JWT is not signed by the node or expired.
+	ErrJWTInvalid = echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired jwt")
+	// ErrJWTInvalidClaims is returned if the JWT was not issued for the node or does not grant access.
+	ErrJWTInvalidClaims = echo.NewHTTPError(http.StatusUnauthorized, "invalid jwt claims")
+)
+
+const (
+	// jwtAuthScheme is the scheme of the Authorization header.
+	jwtAuthScheme = "Bearer"
+	// jwtContextKey is the key the claims of a verified JWT are stored under in the echo context.
+	jwtContextKey = "jwt"
+)
+
+// JWTAuthClaims are the claims of the JWTs issued by the node.
+type JWTAuthClaims struct {
+	jwt.StandardClaims
+	// Dashboard is whether the token grants access to the dashboard.
+	Dashboard bool `json:"dashboard"`
+	// API is whether the token grants access to the API.
+	API bool `json:"api"`
+}
+
+// JWTAuth verifies the JWTs issued by the node, so apps behind the node accept the same tokens.
+// The node signs the tokens with HS256 using the blake2b-256 hash of its marshaled identity private key,
+// the subject is the configured salt and the audience is the node's peer ID.
+type JWTAuth struct {
+	subject      string
+	nodeID       string
+	secret       []byte
+	exemptRoutes []string
+	allow        func(c echo.Context, claims *JWTAuthClaims) bool
+}
+
+// WithJWTExemptRoutes exempts the given echo route paths from authentication, e.g. "/api/myapp/v1/health".
+// A trailing "*" exempts all routes with the given prefix.
+func WithJWTExemptRoutes(routes ...string) options.Option[JWTAuth] {
+	return func(j *JWTAuth) {
+		j.exemptRoutes = append(j.exemptRoutes, routes...)
+	}
+}
+
+// WithJWTAllow sets the function that decides whether the verified claims grant access to the request.
+// By default, the API claim is required.
+func WithJWTAllow(allow func(c echo.Context, claims *JWTAuthClaims) bool) options.Option[JWTAuth] {
+	return func(j *JWTAuth) {
+		j.allow = allow
+	}
+}
+
+// NewJWTAuth creates a new JWTAuth.
+// The salt and the nodeID must match the node's JWT salt and peer ID,
+// identityPrivateKey is the node's marshaled identity private key the tokens are signed with.
+func NewJWTAuth(salt string, nodeID string, identityPrivateKey []byte, opts ...options.Option[JWTAuth]) *JWTAuth {
+	// the secret is hashed to make it a uniform length
+	hashedSecret := blake2b.Sum256(identityPrivateKey)
+
+	return options.Apply(&JWTAuth{
+		subject: salt,
+		nodeID:  nodeID,
+		secret:  hashedSecret[:],
+		allow: func(_ echo.Context, claims *JWTAuthClaims) bool {
+			return claims.API
+		},
+	}, opts)
+}
+
+// exempt reports whether the given echo route path is exempted from authentication.
+func (j *JWTAuth) exempt(route string) bool {
+	for _, exemptRoute := range j.exemptRoutes {
+		if prefix := strings.TrimSuffix(exemptRoute, "*"); prefix != exemptRoute {
+			if strings.HasPrefix(route, prefix) {
+				return true
+			}
+
+			continue
+		}
+
+		if route == exemptRoute {
+			return true
+		}
+	}
+
+	return false
+}
+
+// VerifyToken verifies the given token and returns its claims.
+func (j *JWTAuth) VerifyToken(token string) (*JWTAuthClaims, error) {
+	claims := &JWTAuthClaims{}
+
+	parsedToken, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		if token.Method != jwt.SigningMethodHS256 {
+			return nil, fmt.Errorf("unexpected signing method: %s", token.Header["alg"])
+		}
+
+		return j.secret, nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(ErrJWTInvalid, err.Error())
+	}
+	if !parsedToken.Valid {
+		return nil, ErrJWTInvalid
+	}
+
+	if !claims.VerifyAudience(j.nodeID, true) || claims.Subject != j.subject {
+		return nil, ErrJWTInvalidClaims
+	}
+
+	return claims, nil
+}
+
+// Middleware returns the middleware that enforces a valid JWT in the Authorization header on all non-exempt routes.
+// The claims of the verified token can be retrieved with JWTClaims.
+// It can be used as the preset of a protected auth policy, see WithAuthPreset.
+func (j *JWTAuth) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if j.exempt(c.Path()) {
+				return next(c)
+			}
+
+			scheme, token, found := strings.Cut(c.Request().Header.Get(echo.HeaderAuthorization), " ")
+			if !found || !strings.EqualFold(scheme, jwtAuthScheme) || token == "" {
+				return ErrJWTMissing
+			}
+
+			claims, err := j.VerifyToken(token)
+			if err != nil {
+				return err
+			}
+
+			if !j.allow(c, claims) {
+				return ErrJWTInvalidClaims
+			}
+
+			c.Set(jwtContextKey, claims)
+
+			return next(c)
+		}
+	}
+}
+
+// JWTClaims returns the claims of the JWT verified by the JWTAuth middleware, or nil if the route is exempt.
+func JWTClaims(c echo.Context) *JWTAuthClaims {
+	claims, _ := c.Get(jwtContextKey).(*JWTAuthClaims)
+
+	return claims
+}
@@ -0,0 +1,62 @@
+package httpserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+const (
+	// QueryParameterWait is used to block the request until the requested state is reached, e.g. "wait=true".
+	QueryParameterWait = "wait"
+	// QueryParameterTimeout is used to limit the duration a waiting request blocks, e.g. "timeout=30s".
+	QueryParameterTimeout = "timeout"
+)
+
+// LongPoll are the long-polling settings of a request.
+type LongPoll struct {
+	// Wait tells whether the client wants to wait until the requested state is reached.
+	Wait bool
+	// Timeout is the maximum duration the request blocks.
+	Timeout time.Duration
+}
+
+// ParseLongPollQueryParams parses the "wait" and "timeout" query parameters.
+// The timeout defaults to defaultTimeout and must not exceed maxTimeout.
+func ParseLongPollQueryParams(c echo.Context, defaultTimeout time.Duration, maxTimeout time.Duration) (LongPoll, error) {
+	longPoll := LongPoll{Timeout: defaultTimeout}
+
+	if c.QueryParam(QueryParameterWait) == "" {
+		return longPoll, nil
+	}
+
+	wait, err := ParseBoolQueryParam(c, QueryParameterWait)
+	if err != nil {
+		return LongPoll{}, err
+	}
+	longPoll.Wait = wait
+
+	if c.QueryParam(QueryParameterTimeout) != "" {
+		timeout, err := ParseDurationQueryParam(c, QueryParameterTimeout, 0, maxTimeout)
+		if err != nil {
+			return LongPoll{}, err
+		}
+		longPoll.Timeout = timeout
+	}
+
+	return longPoll, nil
+}
+
+// WaitContext returns the context a waiting request blocks with, it is canceled if the client disconnects
+// or the timeout is reached.
+func (p LongPoll) WaitContext(c echo.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(c.Request().Context(), p.Timeout)
+}
+
+// TimedOut tells whether the wait ended because the timeout was reached, and not because the client disconnected.
+// Handlers respond with the current state in that case, so clients can poll again.
+func TimedOut(c echo.Context, waitCtx context.Context) bool {
+	return errors.Is(waitCtx.Err(), context.DeadlineExceeded) && c.Request().Context().Err() == nil
+}
@@ -0,0 +1,101 @@
+package httpserver
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+)
+
+// routeUnmatched is the route label of requests that did not match any registered route,
+// so scanners can't blow up the cardinality of the metrics with arbitrary paths.
+const routeUnmatched = "unmatched"
+
+// Metrics records the amount, the status codes and the latencies of the handled requests,
+// labeled by the HTTP method and the route template, e.g. "/api/core/v2/blocks/:blockID".
+type Metrics struct {
+	buckets []float64
+
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// WithMetricsBuckets sets the buckets of the request latency histograms in seconds.
+func WithMetricsBuckets(buckets []float64) options.Option[Metrics] {
+	return func(m *Metrics) {
+		m.buckets = buckets
+	}
+}
+
+// NewMetrics creates new Metrics and registers them with the given registerer.
+// The namespace is used as prefix of the prometheus metric names.
+func NewMetrics(registerer prometheus.Registerer, namespace string, opts ...options.Option[Metrics]) (*Metrics, error) {
+	m := options.Apply(&Metrics{
+		buckets: prometheus.DefBuckets,
+	}, opts)
+
+	m.requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "The total amount of handled requests.",
+	}, []string{"method", "route", "status"})
+
+	m.duration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "The time it took to handle the requests.",
+		Buckets:   m.buckets,
+	}, []string{"method", "route"})
+
+	for _, collector := range []prometheus.Collector{m.requests, m.duration} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, errors.Wrap(err, "registering HTTP metrics failed")
+		}
+	}
+
+	return m, nil
+}
+
+// Middleware returns the middleware that records the metrics of all requests passing it.
+// Errors returned by the handlers are recorded with the status code the error handler will respond with.
+func (m *Metrics) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+
+			err := next(c)
+
+			route := c.Path()
+			if route == "" {
+				route = routeUnmatched
+			}
+
+			method := c.Request().Method
+			m.requests.WithLabelValues(method, route, strconv.Itoa(responseStatus(c, err))).Inc()
+			m.duration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+
+			return err
+		}
+	}
+}
+
+// responseStatus returns the status code of the response, or the one of the error if the response was not written yet.
+func responseStatus(c echo.Context, err error) int {
+	if err == nil || c.Response().Committed {
+		return c.Response().Status
+	}
+
+	var e *echo.HTTPError
+	if errors.As(err, &e) {
+		return e.Code
+	}
+
+	return http.StatusInternalServerError
+}
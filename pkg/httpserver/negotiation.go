@@ -0,0 +1,210 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+)
+
+// NegotiatedHandlerFunc handles a request and returns the response object, which is sent as JSON,
+// and its raw serialized bytes, which are sent if MIMEApplicationVendorIOTASerializerV1 is requested.
+// Either of them may be nil if the representation is not supported, if both are nil, no content is sent.
+type NegotiatedHandlerFunc func(c echo.Context) (obj interface{}, rawBytes []byte, err error)
+
+// ErrorMapper maps errors returned by handlers to HTTP errors, it returns nil if it does not know the error.
+type ErrorMapper func(err error) error
+
+// NegotiationOptions are the options of NegotiatedHandler.
+type NegotiationOptions struct {
+	statusCode          int
+	requestContentTypes []string
+	errorMappers        []ErrorMapper
+}
+
+// WithStatusCode sets the status code of successful responses, e.g. http.StatusCreated.
+func WithStatusCode(statusCode int) options.Option[NegotiationOptions] {
+	return func(o *NegotiationOptions) {
+		o.statusCode = statusCode
+	}
+}
+
+// WithRequestContentTypes sets the supported content types of request bodies.
+// Requests with a body of another content type are rejected with 415 Unsupported Media Type.
+func WithRequestContentTypes(contentTypes ...string) options.Option[NegotiationOptions] {
+	return func(o *NegotiationOptions) {
+		o.requestContentTypes = contentTypes
+	}
+}
+
+// WithErrorMapper adds an error mapper that is consulted before the default mapping of gRPC and context errors.
+func WithErrorMapper(errorMapper ErrorMapper) options.Option[NegotiationOptions] {
+	return func(o *NegotiationOptions) {
+		o.errorMappers = append(o.errorMappers, errorMapper)
+	}
+}
+
+// NegotiateAcceptContentType returns the supported content type that is preferred by the given Accept header.
+// The quality values and wildcards like "*/*" or "application/*" are honored, on equal quality the order of the
+// supported content types decides. An empty header accepts the first supported content type.
+func NegotiateAcceptContentType(accept string, supportedContentTypes ...string) (string, bool) {
+	if len(supportedContentTypes) == 0 {
+		return "", false
+	}
+	if strings.TrimSpace(accept) == "" {
+		return supportedContentTypes[0], true
+	}
+
+	bestContentType := ""
+	bestQuality := 0.0
+	bestIndex := len(supportedContentTypes)
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaRange, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		mediaRange = strings.ToLower(strings.TrimSpace(mediaRange))
+
+		quality := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if param = strings.TrimSpace(param); strings.HasPrefix(param, "q=") {
+				parsedQuality, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+				if err != nil {
+					quality = 0
+				} else {
+					quality = parsedQuality
+				}
+			}
+		}
+		if quality <= 0 {
+			continue
+		}
+
+		for index, contentType := range supportedContentTypes {
+			mainType, _, _ := strings.Cut(contentType, "/")
+
+			matches := mediaRange == "*/*" || mediaRange == mainType+"/*" || mediaRange == contentType
+			if !matches {
+				continue
+			}
+
+			if quality > bestQuality || (quality == bestQuality && index < bestIndex) {
+				bestContentType = contentType
+				bestQuality = quality
+				bestIndex = index
+			}
+		}
+	}
+
+	return bestContentType, bestContentType != ""
+}
+
+// mapGRPCError maps the errors of the node's gRPC API and of canceled contexts to HTTP errors.
+func mapGRPCError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return errors.WithMessage(echo.NewHTTPError(http.StatusGatewayTimeout), err.Error())
+	}
+
+	s, ok := status.FromError(errors.Cause(err))
+	if !ok {
+		return nil
+	}
+
+	//nolint:exhaustive // all other codes are internal server errors
+	switch s.Code() {
+	case codes.NotFound:
+		return errors.WithMessage(echo.ErrNotFound, s.Message())
+	case codes.InvalidArgument, codes.OutOfRange:
+		return errors.WithMessage(ErrInvalidParameter, s.Message())
+	case codes.AlreadyExists:
+		return errors.WithMessage(echo.NewHTTPError(http.StatusConflict), s.Message())
+	case codes.Unavailable, codes.ResourceExhausted:
+		return errors.WithMessage(echo.ErrServiceUnavailable, s.Message())
+	case codes.DeadlineExceeded:
+		return errors.WithMessage(echo.NewHTTPError(http.StatusGatewayTimeout), s.Message())
+	case codes.Unimplemented:
+		return errors.WithMessage(echo.NewHTTPError(http.StatusNotImplemented), s.Message())
+	default:
+		return nil
+	}
+}
+
+// MapGRPCError maps the errors of the node's gRPC API and of canceled contexts to the matching HTTP errors,
+// other errors are returned unchanged.
+func MapGRPCError(err error) error {
+	if mappedErr := mapGRPCError(err); mappedErr != nil {
+		return mappedErr
+	}
+
+	return err
+}
+
+// NegotiatedHandler wraps the given handler and negotiates the content types of the request and the response.
+// The response is sent as JSON or as MIMEApplicationVendorIOTASerializerV1, as preferred by the Accept header,
+// and 406 Not Acceptable is returned if the handler does not support the requested representation.
+// Errors of the handler are mapped by the given error mappers, then gRPC and context errors are mapped
+// to the matching HTTP errors, e.g. a NotFound status of the node becomes 404 Not Found.
+func NegotiatedHandler(handler NegotiatedHandlerFunc, opts ...options.Option[NegotiationOptions]) echo.HandlerFunc {
+	negotiationOpts := options.Apply(&NegotiationOptions{
+		statusCode:          http.StatusOK,
+		requestContentTypes: []string{echo.MIMEApplicationJSON, MIMEApplicationVendorIOTASerializerV1},
+	}, opts)
+
+	mapError := func(err error) error {
+		for _, errorMapper := range negotiationOpts.errorMappers {
+			if mappedErr := errorMapper(err); mappedErr != nil {
+				return mappedErr
+			}
+		}
+		if mappedErr := mapGRPCError(err); mappedErr != nil {
+			return mappedErr
+		}
+
+		return err
+	}
+
+	return func(c echo.Context) error {
+		request := c.Request()
+
+		if request.ContentLength != 0 && request.Body != nil && request.Body != http.NoBody {
+			if _, err := GetRequestContentType(c, negotiationOpts.requestContentTypes...); err != nil {
+				return errors.WithMessagef(err, "supported content types: %s", strings.Join(negotiationOpts.requestContentTypes, ", "))
+			}
+		}
+
+		mimeType, ok := NegotiateAcceptContentType(request.Header.Get(echo.HeaderAccept), echo.MIMEApplicationJSON, MIMEApplicationVendorIOTASerializerV1)
+		if !ok {
+			return errors.WithMessagef(ErrNotAcceptable, "supported content types: %s, %s", echo.MIMEApplicationJSON, MIMEApplicationVendorIOTASerializerV1)
+		}
+
+		obj, rawBytes, err := handler(c)
+		if err != nil {
+			return mapError(err)
+		}
+
+		if obj == nil && rawBytes == nil {
+			return c.NoContent(http.StatusNoContent)
+		}
+
+		switch mimeType {
+		case MIMEApplicationVendorIOTASerializerV1:
+			if rawBytes == nil {
+				return errors.WithMessagef(ErrNotAcceptable, "%s is not supported for this response", MIMEApplicationVendorIOTASerializerV1)
+			}
+
+			return c.Blob(negotiationOpts.statusCode, MIMEApplicationVendorIOTASerializerV1, rawBytes)
+
+		default:
+			if obj == nil {
+				return errors.WithMessagef(ErrNotAcceptable, "%s is not supported for this response", echo.MIMEApplicationJSON)
+			}
+
+			return JSONResponse(c, negotiationOpts.statusCode, obj)
+		}
+	}
+}
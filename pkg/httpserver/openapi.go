@@ -0,0 +1,215 @@
+package httpserver
+
+import (
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// OpenAPIVersion is the version of the OpenAPI specification the documents are generated for.
+const OpenAPIVersion = "3.0.3"
+
+// OpenAPIDocument is an OpenAPI 3 document.
+type OpenAPIDocument struct {
+	OpenAPI string                     `json:"openapi"`
+	Info    OpenAPIInfo                `json:"info"`
+	Paths   map[string]OpenAPIPathItem `json:"paths"`
+}
+
+// OpenAPIInfo is the metadata of an OpenAPI document.
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OpenAPIPathItem are the operations of a single path, keyed by the lowercase HTTP method.
+type OpenAPIPathItem map[string]*OpenAPIOperation
+
+// OpenAPIOperation is a single operation of an OpenAPI path.
+type OpenAPIOperation struct {
+	OperationID string                      `json:"operationId"`
+	Summary     string                      `json:"summary,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Parameters  []*OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody         `json:"requestBody,omitempty"`
+	Security    []map[string][]string       `json:"security,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+}
+
+// OpenAPIParameter is a parameter of an OpenAPI operation.
+// In is "path", "query" or "header".
+type OpenAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required"`
+	Schema      *OpenAPISchema `json:"schema,omitempty"`
+}
+
+// OpenAPISchema is the schema of a parameter or a response body.
+// An empty type allows any value.
+type OpenAPISchema struct {
+	Type       string                    `json:"type,omitempty"`
+	Format     string                    `json:"format,omitempty"`
+	Nullable   bool                      `json:"nullable,omitempty"`
+	Properties map[string]*OpenAPISchema `json:"properties,omitempty"`
+	Required   []string                  `json:"required,omitempty"`
+	Items      *OpenAPISchema            `json:"items,omitempty"`
+	// AdditionalProperties is the schema of the values of objects with arbitrary keys, e.g. maps.
+	AdditionalProperties *OpenAPISchema `json:"additionalProperties,omitempty"`
+}
+
+// OpenAPIMediaType is the content of a response for a single media type.
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchema `json:"schema,omitempty"`
+}
+
+// OpenAPIRequestBody is the request body of an OpenAPI operation.
+type OpenAPIRequestBody struct {
+	Required bool                         `json:"required"`
+	Content  map[string]*OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIResponse is a response of an OpenAPI operation.
+type OpenAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// openAPIPath converts an echo path to an OpenAPI path and returns the names of its path parameters.
+func openAPIPath(echoPath string) (string, []string) {
+	segments := strings.Split(echoPath, "/")
+	params := make([]string, 0)
+
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			name := strings.TrimPrefix(segment, ":")
+			segments[i] = "{" + name + "}"
+			params = append(params, name)
+		}
+	}
+
+	return strings.Join(segments, "/"), params
+}
+
+// defaultOperationID derives an operation ID from the method and path, e.g. "getBlocksBlockIDProof".
+func defaultOperationID(method string, echoPath string) string {
+	var builder strings.Builder
+	builder.WriteString(strings.ToLower(method))
+
+	for _, segment := range strings.FieldsFunc(echoPath, func(r rune) bool { return r == '/' || r == ':' || r == '-' || r == '_' }) {
+		builder.WriteString(strings.ToUpper(segment[:1]))
+		builder.WriteString(segment[1:])
+	}
+
+	return builder.String()
+}
+
+// openAPIResponses converts the declared response schemas of a route, keyed by status code, to OpenAPI responses.
+func openAPIResponses(schemas map[int]*OpenAPISchema) map[string]*OpenAPIResponse {
+	if len(schemas) == 0 {
+		return map[string]*OpenAPIResponse{
+			"default": {Description: http.StatusText(http.StatusOK)},
+		}
+	}
+
+	responses := make(map[string]*OpenAPIResponse, len(schemas))
+	for statusCode, schema := range schemas {
+		key := "default"
+		description := http.StatusText(http.StatusOK)
+		if statusCode != 0 {
+			key = strconv.Itoa(statusCode)
+			description = http.StatusText(statusCode)
+		}
+
+		responses[key] = &OpenAPIResponse{
+			Description: description,
+			Content: map[string]*OpenAPIMediaType{
+				echo.MIMEApplicationJSON: {Schema: schema},
+			},
+		}
+	}
+
+	return responses
+}
+
+// NewOpenAPIDocument creates an empty OpenAPI document.
+func NewOpenAPIDocument(title string, version string) *OpenAPIDocument {
+	return &OpenAPIDocument{
+		OpenAPI: OpenAPIVersion,
+		Info: OpenAPIInfo{
+			Title:   title,
+			Version: version,
+		},
+		Paths: make(map[string]OpenAPIPathItem),
+	}
+}
+
+// OpenAPI generates the OpenAPI document of all routes in the table.
+func (t *RouteTable) OpenAPI(title string, version string) *OpenAPIDocument {
+	doc := NewOpenAPIDocument(title, version)
+	doc.AddRoutes(t)
+
+	return doc
+}
+
+// AddRoutes adds the operations of all routes in the table to the document,
+// so apps with several route tables serve a single document.
+func (doc *OpenAPIDocument) AddRoutes(t *RouteTable) {
+	for _, route := range t.routes {
+		routePath, pathParams := openAPIPath(path.Join(t.Prefix(), route.Path))
+
+		operationID := route.OperationID
+		if operationID == "" {
+			operationID = defaultOperationID(route.Method, route.Path)
+		}
+
+		operation := &OpenAPIOperation{
+			OperationID: operationID,
+			Summary:     route.Summary,
+			Description: route.Description,
+			Tags:        route.Tags,
+			Responses:   openAPIResponses(route.Responses),
+		}
+		declaredPathParams := make(map[string]struct{})
+		for _, param := range route.Parameters {
+			if param.In == "path" {
+				declaredPathParams[param.Name] = struct{}{}
+			}
+		}
+		for _, param := range pathParams {
+			if _, declared := declaredPathParams[param]; declared {
+				continue
+			}
+			operation.Parameters = append(operation.Parameters, &OpenAPIParameter{
+				Name:     param,
+				In:       "path",
+				Required: true,
+				Schema:   &OpenAPISchema{Type: "string"},
+			})
+		}
+		operation.Parameters = append(operation.Parameters, route.Parameters...)
+		if route.RequestBody != nil {
+			operation.RequestBody = &OpenAPIRequestBody{
+				Required: true,
+				Content: map[string]*OpenAPIMediaType{
+					echo.MIMEApplicationJSON: {Schema: route.RequestBody},
+				},
+			}
+		}
+		if route.Auth != AuthPolicyPublic {
+			operation.Security = []map[string][]string{{string(route.Auth): {}}}
+		}
+
+		pathItem, exists := doc.Paths[routePath]
+		if !exists {
+			pathItem = make(OpenAPIPathItem)
+			doc.Paths[routePath] = pathItem
+		}
+		pathItem[strings.ToLower(route.Method)] = operation
+	}
+}
@@ -0,0 +1,95 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+)
+
+const (
+	// RouteOpenAPIDocument is the route the generated OpenAPI document is served at.
+	RouteOpenAPIDocument = "/api/docs/openapi.json"
+	// RouteSwaggerUI is the route the Swagger UI is served at, if it is enabled.
+	RouteSwaggerUI = "/api/docs"
+
+	// DefaultSwaggerUIDistURL is the URL the assets of the Swagger UI are loaded from by default.
+	DefaultSwaggerUIDistURL = "https://unpkg.com/swagger-ui-dist@4.15.5"
+)
+
+// OpenAPIDocsOptions are the options of the served OpenAPI document.
+type OpenAPIDocsOptions struct {
+	swaggerUIEnabled bool
+	swaggerUIDistURL string
+}
+
+// WithSwaggerUI serves the Swagger UI for the document at RouteSwaggerUI.
+// The assets are loaded by the browser from distURL, DefaultSwaggerUIDistURL is used if it is empty.
+func WithSwaggerUI(distURL string) options.Option[OpenAPIDocsOptions] {
+	return func(o *OpenAPIDocsOptions) {
+		o.swaggerUIEnabled = true
+		if distURL != "" {
+			o.swaggerUIDistURL = distURL
+		}
+	}
+}
+
+// OpenAPIHandler returns the handler that serves the document as JSON.
+// The document is encoded once, it must not be changed afterwards.
+func OpenAPIHandler(doc *OpenAPIDocument) (echo.HandlerFunc, error) {
+	docJSON, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal OpenAPI document: %w", err)
+	}
+
+	return func(c echo.Context) error {
+		return c.JSONBlob(http.StatusOK, docJSON)
+	}, nil
+}
+
+// SwaggerUIHandler returns the handler that serves a Swagger UI page for the document at specURL.
+func SwaggerUIHandler(specURL string, distURL string) echo.HandlerFunc {
+	page := fmt.Sprintf(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>API docs</title>
+  <link rel="stylesheet" href="%[1]s/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="%[1]s/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "%[2]s", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`, html.EscapeString(distURL), html.EscapeString(specURL))
+
+	return func(c echo.Context) error {
+		return c.HTML(http.StatusOK, page)
+	}
+}
+
+// RegisterOpenAPIDocs serves the document at RouteOpenAPIDocument and, if enabled via WithSwaggerUI, the Swagger UI.
+func RegisterOpenAPIDocs(e *echo.Echo, doc *OpenAPIDocument, opts ...options.Option[OpenAPIDocsOptions]) error {
+	docsOpts := options.Apply(&OpenAPIDocsOptions{
+		swaggerUIDistURL: DefaultSwaggerUIDistURL,
+	}, opts)
+
+	handler, err := OpenAPIHandler(doc)
+	if err != nil {
+		return err
+	}
+	e.GET(RouteOpenAPIDocument, handler)
+
+	if docsOpts.swaggerUIEnabled {
+		e.GET(RouteSwaggerUI, SwaggerUIHandler(RouteOpenAPIDocument, docsOpts.swaggerUIDistURL))
+	}
+
+	return nil
+}
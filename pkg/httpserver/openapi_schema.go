@@ -0,0 +1,156 @@
+package httpserver
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+)
+
+// SchemaOf derives the schema of the JSON representation of the value from its type, e.g. of a response struct.
+// Properties are named by their json tags, properties without "omitempty" are required.
+// Types with a custom JSON encoding can't be inspected and allow any value, unless they encode to text.
+func SchemaOf(v interface{}) *OpenAPISchema {
+	if v == nil {
+		return &OpenAPISchema{}
+	}
+
+	t := reflect.TypeOf(v)
+	// pointers are accepted to describe the value they point to, e.g. SchemaOf(&Response{})
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return schemaOfType(t, make(map[reflect.Type]struct{}))
+}
+
+func schemaOfType(t reflect.Type, visiting map[reflect.Type]struct{}) *OpenAPISchema {
+	if t.Kind() == reflect.Ptr {
+		schema := schemaOfType(t.Elem(), visiting)
+		schema.Nullable = true
+
+		return schema
+	}
+
+	switch {
+	case t == timeType:
+		return &OpenAPISchema{Type: "string", Format: "date-time"}
+	case t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType):
+		return &OpenAPISchema{}
+	case t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType):
+		return &OpenAPISchema{Type: "string"}
+	}
+
+	//nolint:exhaustive // all other kinds allow any value
+	switch t.Kind() {
+	case reflect.Bool:
+		return &OpenAPISchema{Type: "boolean"}
+
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &OpenAPISchema{Type: "integer", Format: "int32"}
+
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &OpenAPISchema{Type: "integer", Format: "int64"}
+
+	case reflect.Float32:
+		return &OpenAPISchema{Type: "number", Format: "float"}
+
+	case reflect.Float64:
+		return &OpenAPISchema{Type: "number", Format: "double"}
+
+	case reflect.String:
+		return &OpenAPISchema{Type: "string"}
+
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			// byte slices are encoded as base64 strings
+			return &OpenAPISchema{Type: "string", Format: "byte"}
+		}
+
+		schema := &OpenAPISchema{Type: "array", Items: schemaOfType(t.Elem(), visiting)}
+		if t.Kind() == reflect.Slice {
+			// nil slices are encoded as null
+			schema.Nullable = true
+		}
+
+		return schema
+
+	case reflect.Map:
+		return &OpenAPISchema{Type: "object", Nullable: true, AdditionalProperties: schemaOfType(t.Elem(), visiting)}
+
+	case reflect.Struct:
+		if _, isVisiting := visiting[t]; isVisiting {
+			// recursive types are not expanded again
+			return &OpenAPISchema{}
+		}
+		visiting[t] = struct{}{}
+		defer delete(visiting, t)
+
+		schema := &OpenAPISchema{Type: "object", Properties: make(map[string]*OpenAPISchema)}
+		addStructProperties(schema, t, visiting)
+
+		return schema
+
+	default:
+		return &OpenAPISchema{}
+	}
+}
+
+// addStructProperties adds the exported fields of the struct to the schema, embedded structs are flattened.
+func addStructProperties(schema *OpenAPISchema, t reflect.Type, visiting map[reflect.Type]struct{}) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, tagOptions, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				addStructProperties(schema, fieldType, visiting)
+
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		propertySchema := schemaOfType(field.Type, visiting)
+		if hasTagOption(tagOptions, "string") {
+			propertySchema = &OpenAPISchema{Type: "string", Nullable: propertySchema.Nullable}
+		}
+		schema.Properties[name] = propertySchema
+
+		if !hasTagOption(tagOptions, "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+func hasTagOption(tagOptions string, option string) bool {
+	for _, tagOption := range strings.Split(tagOptions, ",") {
+		if tagOption == option {
+			return true
+		}
+	}
+
+	return false
+}
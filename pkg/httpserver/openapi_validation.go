@@ -0,0 +1,172 @@
+package httpserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/core/logger"
+)
+
+// WithResponseValidation validates the JSON responses of all routes with declared response schemas
+// and logs mismatches to the given logger. It is meant for debug mode, because every response is buffered and decoded.
+func WithResponseValidation(log *logger.Logger) options.Option[RouteTable] {
+	return func(t *RouteTable) {
+		t.responseValidationLogger = log
+	}
+}
+
+// Validate validates the decoded JSON value against the schema and returns the mismatches.
+// Properties that are not declared in the schema are allowed.
+func (s *OpenAPISchema) Validate(value interface{}) []string {
+	mismatches := make([]string, 0)
+	s.validate("$", value, &mismatches)
+
+	return mismatches
+}
+
+func (s *OpenAPISchema) validate(valuePath string, value interface{}, mismatches *[]string) {
+	if s == nil || s.Type == "" {
+		return
+	}
+
+	mismatch := func(format string, args ...interface{}) {
+		*mismatches = append(*mismatches, fmt.Sprintf("%s: %s", valuePath, fmt.Sprintf(format, args...)))
+	}
+
+	if value == nil {
+		if !s.Nullable {
+			mismatch("expected %s, got null", s.Type)
+		}
+
+		return
+	}
+
+	switch s.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			mismatch("expected object, got %T", value)
+
+			return
+		}
+
+		for _, required := range s.Required {
+			if _, exists := object[required]; !exists {
+				mismatch("missing required property \"%s\"", required)
+			}
+		}
+
+		names := make([]string, 0, len(s.Properties))
+		for name := range s.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if propertyValue, exists := object[name]; exists {
+				s.Properties[name].validate(valuePath+"."+name, propertyValue, mismatches)
+			}
+		}
+
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			mismatch("expected array, got %T", value)
+
+			return
+		}
+
+		for i, item := range array {
+			s.Items.validate(fmt.Sprintf("%s[%d]", valuePath, i), item, mismatches)
+		}
+
+	case "string":
+		if _, ok := value.(string); !ok {
+			mismatch("expected string, got %T", value)
+		}
+
+	case "integer":
+		number, ok := value.(float64)
+		if !ok || number != math.Trunc(number) {
+			mismatch("expected integer, got %v", value)
+		}
+
+	case "number":
+		if _, ok := value.(float64); !ok {
+			mismatch("expected number, got %T", value)
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			mismatch("expected boolean, got %T", value)
+		}
+
+	default:
+		mismatch("unknown schema type \"%s\"", s.Type)
+	}
+}
+
+// recordingResponseWriter records the written body while passing it on.
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingResponseWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// responseValidationMiddleware validates the JSON responses of the route against its declared schemas.
+func responseValidationMiddleware(log *logger.Logger, route *Route) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			response := c.Response()
+
+			writer := &recordingResponseWriter{ResponseWriter: response.Writer}
+			originalWriter := response.Writer
+			response.Writer = writer
+			defer func() {
+				response.Writer = originalWriter
+			}()
+
+			err := next(c)
+
+			schema, exists := route.Responses[response.Status]
+			if !exists {
+				schema, exists = route.Responses[0]
+			}
+			if !exists || writer.body.Len() == 0 || !strings.HasPrefix(response.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+				return err
+			}
+
+			var value interface{}
+			if decodeErr := json.Unmarshal(writer.body.Bytes(), &value); decodeErr != nil {
+				log.Warnf("response of %s %s with status %d is no valid JSON: %s", route.Method, route.Path, response.Status, decodeErr)
+
+				return err
+			}
+
+			if mismatches := schema.Validate(value); len(mismatches) > 0 {
+				log.Warnf("response of %s %s with status %d does not match the schema: %s", route.Method, route.Path, response.Status, strings.Join(mismatches, "; "))
+			}
+
+			return err
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package httpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+func testContext(query string) echo.Context {
+	request := httptest.NewRequest(http.MethodGet, "/?"+query, nil)
+
+	return echo.New().NewContext(request, httptest.NewRecorder())
+}
+
+// checkParameterError checks that the error is answered with 400 invalid_parameter.
+func checkParameterError(t *testing.T, err error) {
+	t.Helper()
+
+	if !errors.Is(err, ErrInvalidParameter) {
+		t.Fatalf("expected ErrInvalidParameter, got %v", err)
+	}
+
+	var httpErr *echo.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %v", http.StatusBadRequest, err)
+	}
+
+	if code := ErrorCodeOf(err); code != ErrorCodeInvalidParameter {
+		t.Fatalf("expected error code %s, got %s", ErrorCodeInvalidParameter, code)
+	}
+}
+
+func TestParseBoolQueryParam(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    bool
+		wantErr bool
+	}{
+		{query: "wait=true", want: true},
+		{query: "wait=1", want: true},
+		{query: "wait=false", want: false},
+		{query: "wait=0", want: false},
+		{query: "wait=foo", wantErr: true},
+		{query: "", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			value, err := ParseBoolQueryParam(testContext(test.query), "wait")
+			if test.wantErr {
+				checkParameterError(t, err)
+
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if value != test.want {
+				t.Fatalf("expected %t, got %t", test.want, value)
+			}
+		})
+	}
+}
+
+func TestParsePageSizeQueryParam(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		defaultSize int
+		maxSize     int
+		want        int
+		wantErr     bool
+	}{
+		{name: "default", query: "", defaultSize: 100, maxSize: 1000, want: 100},
+		{name: "default clamped", query: "", defaultSize: 100, maxSize: 50, want: 50},
+		{name: "given", query: "pageSize=10", defaultSize: 100, maxSize: 1000, want: 10},
+		{name: "minimum", query: "pageSize=1", defaultSize: 100, maxSize: 1000, want: 1},
+		{name: "clamped", query: "pageSize=5000", defaultSize: 100, maxSize: 1000, want: 1000},
+		{name: "zero", query: "pageSize=0", defaultSize: 100, maxSize: 1000, wantErr: true},
+		{name: "negative", query: "pageSize=-1", defaultSize: 100, maxSize: 1000, wantErr: true},
+		{name: "not a number", query: "pageSize=ten", defaultSize: 100, maxSize: 1000, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pageSize, err := ParsePageSizeQueryParam(testContext(test.query), test.defaultSize, test.maxSize)
+			if test.wantErr {
+				checkParameterError(t, err)
+
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if pageSize != test.want {
+				t.Fatalf("expected %d, got %d", test.want, pageSize)
+			}
+		})
+	}
+}
+
+func TestParseCursorQueryParam(t *testing.T) {
+	cursor := NewCursor(123, 456)
+
+	tests := []struct {
+		name    string
+		query   string
+		want    *Cursor
+		wantErr bool
+	}{
+		{name: "first page", query: ""},
+		{name: "next page", query: "cursor=" + cursor.Next(10).String(), want: NewCursor(123, 466)},
+		{name: "invalid encoding", query: "cursor=!!!", wantErr: true},
+		{name: "invalid length", query: "cursor=" + cursor.String()[:8], wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parsed, err := ParseCursorQueryParam(testContext(test.query), QueryParameterCursor)
+			if test.wantErr {
+				checkParameterError(t, err)
+
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			switch {
+			case test.want == nil && parsed != nil:
+				t.Fatalf("expected no cursor, got %+v", parsed)
+			case test.want != nil && (parsed == nil || *parsed != *test.want):
+				t.Fatalf("expected cursor %+v, got %+v", test.want, parsed)
+			}
+		})
+	}
+}
+
+func TestParseUint32QueryParam(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    uint32
+		wantErr bool
+	}{
+		{query: "index=0", want: 0},
+		{query: "index=4294967295", want: 4294967295},
+		{query: "index=4294967296", wantErr: true},
+		{query: "index=-1", wantErr: true},
+		{query: "", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			value, err := ParseUint32QueryParam(testContext(test.query), "index")
+			if test.wantErr {
+				checkParameterError(t, err)
+
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if value != test.want {
+				t.Fatalf("expected %d, got %d", test.want, value)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package httpserver
+
+import (
+	"errors"
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+)
+
+// ErrInvalidRateLimit is returned if a rate limiter is created without positive requests per second and burst.
+var ErrInvalidRateLimit = errors.New("rate limit needs positive requests per second and burst")
+
+// rateLimitClient is the rate limit state of a single client IP.
+type rateLimitClient struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// RateLimiter limits the requests per second of every client IP.
+// Clients exceeding the limit get ErrTooManyRequests, all responses carry the X-RateLimit-* headers.
+type RateLimiter struct {
+	requestsPerSecond float64
+	burst             int
+	ipExtractor       echo.IPExtractor
+	idleTimeout       time.Duration
+
+	clientsMutex sync.Mutex
+	clients      map[string]*rateLimitClient
+	lastCleanup  time.Time
+}
+
+// WithRateLimitTrustedProxies sets the IP ranges of the reverse proxies in front of the app.
+// The client IP is then taken from the X-Forwarded-For header, skipping the trusted proxies.
+// Without trusted proxies, the IP of the direct peer is used and forwarding headers are ignored,
+// so clients can't evade the limit by spoofing them.
+func WithRateLimitTrustedProxies(ipRanges ...*net.IPNet) options.Option[RateLimiter] {
+	return func(r *RateLimiter) {
+		trustOptions := []echo.TrustOption{
+			echo.TrustLoopback(false),
+			echo.TrustLinkLocal(false),
+			echo.TrustPrivateNet(false),
+		}
+		for _, ipRange := range ipRanges {
+			trustOptions = append(trustOptions, echo.TrustIPRange(ipRange))
+		}
+
+		r.ipExtractor = echo.ExtractIPFromXFFHeader(trustOptions...)
+	}
+}
+
+// WithRateLimitIPExtractor sets the function that extracts the client IP from a request.
+func WithRateLimitIPExtractor(ipExtractor echo.IPExtractor) options.Option[RateLimiter] {
+	return func(r *RateLimiter) {
+		r.ipExtractor = ipExtractor
+	}
+}
+
+// WithRateLimitIdleTimeout sets the duration after which the state of an inactive client is dropped.
+func WithRateLimitIdleTimeout(idleTimeout time.Duration) options.Option[RateLimiter] {
+	return func(r *RateLimiter) {
+		r.idleTimeout = idleTimeout
+	}
+}
+
+// NewRateLimiter creates a new RateLimiter that allows requestsPerSecond on average and bursts of up to burst requests.
+// Both have to be positive, the reset time of the rate limit state is derived from them.
+func NewRateLimiter(requestsPerSecond float64, burst int, opts ...options.Option[RateLimiter]) (*RateLimiter, error) {
+	if requestsPerSecond <= 0 || burst <= 0 {
+		return nil, ErrInvalidRateLimit
+	}
+
+	return options.Apply(&RateLimiter{
+		requestsPerSecond: requestsPerSecond,
+		burst:             burst,
+		ipExtractor:       echo.ExtractIPDirect(),
+		idleTimeout:       10 * time.Minute,
+		clients:           make(map[string]*rateLimitClient),
+		lastCleanup:       time.Now(),
+	}, opts), nil
+}
+
+// client returns the state of the given client IP and drops the state of inactive clients.
+func (r *RateLimiter) client(ip string, now time.Time) *rateLimitClient {
+	r.clientsMutex.Lock()
+	defer r.clientsMutex.Unlock()
+
+	if now.Sub(r.lastCleanup) >= r.idleTimeout {
+		for clientIP, client := range r.clients {
+			if now.Sub(client.lastSeen) >= r.idleTimeout {
+				delete(r.clients, clientIP)
+			}
+		}
+		r.lastCleanup = now
+	}
+
+	client, exists := r.clients[ip]
+	if !exists {
+		client = &rateLimitClient{
+			limiter: rate.NewLimiter(rate.Limit(r.requestsPerSecond), r.burst),
+		}
+		r.clients[ip] = client
+	}
+	client.lastSeen = now
+
+	return client
+}
+
+// state returns the rate limit state of the given limiter.
+func (r *RateLimiter) state(limiter *rate.Limiter, now time.Time, retryAfter time.Duration) RateLimitState {
+	tokens := limiter.TokensAt(now)
+
+	return RateLimitState{
+		Limit:      r.burst,
+		Remaining:  int(math.Floor(tokens)),
+		Reset:      now.Add(time.Duration((float64(r.burst) - tokens) / r.requestsPerSecond * float64(time.Second))),
+		RetryAfter: retryAfter,
+	}
+}
+
+// Middleware returns the middleware that enforces the rate limit.
+func (r *RateLimiter) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			now := time.Now()
+			limiter := r.client(r.ipExtractor(c.Request()), now).limiter
+
+			reservation := limiter.ReserveN(now, 1)
+			if !reservation.OK() {
+				// the burst is 0, no request is ever allowed
+				return RateLimitExceeded(c, RateLimitState{Limit: r.burst, Reset: now})
+			}
+
+			if delay := reservation.DelayFrom(now); delay > 0 {
+				// the request is rejected, so it must not consume the token
+				reservation.CancelAt(now)
+
+				return RateLimitExceeded(c, r.state(limiter, now, delay))
+			}
+
+			SetRateLimitHeaders(c, r.state(limiter, now, 0))
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package httpserver
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// HeaderRateLimitLimit is the maximum amount of requests a client can issue in the current window.
+	HeaderRateLimitLimit = "X-RateLimit-Limit"
+	// HeaderRateLimitRemaining is the amount of requests a client can still issue in the current window.
+	HeaderRateLimitRemaining = "X-RateLimit-Remaining"
+	// HeaderRateLimitReset is the unix timestamp at which the quota of the client is fully restored.
+	HeaderRateLimitReset = "X-RateLimit-Reset"
+)
+
+// ErrTooManyRequests is returned if a client exceeded its rate limit.
+var ErrTooManyRequests = echo.NewHTTPError(http.StatusTooManyRequests, "rate limit exceeded")
+
+// RateLimitState is the rate limit state of a client, as reported to the client in the response headers.
+type RateLimitState struct {
+	// Limit is the maximum amount of requests in a window.
+	Limit int
+	// Remaining is the amount of requests that are still allowed in the current window.
+	Remaining int
+	// Reset is the time at which the quota is fully restored.
+	Reset time.Time
+	// RetryAfter is the duration after which the next request is allowed, 0 if it is allowed now.
+	RetryAfter time.Duration
+}
+
+// SetRateLimitHeaders sets the X-RateLimit-Limit/Remaining/Reset headers of the response,
+// and Retry-After if the client has to wait, so client SDKs can implement correct backoff.
+func SetRateLimitHeaders(c echo.Context, state RateLimitState) {
+	header := c.Response().Header()
+
+	remaining := state.Remaining
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	header.Set(HeaderRateLimitLimit, strconv.Itoa(state.Limit))
+	header.Set(HeaderRateLimitRemaining, strconv.Itoa(remaining))
+	header.Set(HeaderRateLimitReset, strconv.FormatInt(state.Reset.Unix(), 10))
+
+	if state.RetryAfter > 0 {
+		// Retry-After is specified in whole seconds, round up so clients don't retry too early
+		header.Set(echo.HeaderRetryAfter, strconv.FormatInt(int64(math.Ceil(state.RetryAfter.Seconds())), 10))
+	}
+}
+
+// RateLimitExceeded sets the rate limit headers and returns ErrTooManyRequests.
+func RateLimitExceeded(c echo.Context, state RateLimitState) error {
+	SetRateLimitHeaders(c, state)
+
+	return ErrTooManyRequests
+}
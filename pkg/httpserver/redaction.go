@@ -0,0 +1,146 @@
+package httpserver
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+)
+
+// RedactedValue replaces the values of redacted query parameters and headers.
+const RedactedValue = "[REDACTED]"
+
+var (
+	// DefaultRedactedQueryParams are the query parameters that are redacted by default.
+	DefaultRedactedQueryParams = []string{"token", "jwt", "apiKey"}
+	// DefaultRedactedHeaders are the headers that are redacted by default.
+	DefaultRedactedHeaders = []string{echo.HeaderAuthorization, echo.HeaderCookie, HeaderAPIKey}
+)
+
+// EchoOptions are the optional settings of NewEcho.
+type EchoOptions struct {
+	redactedQueryParams []string
+	redactedHeaders     []string
+	loggedHeaders       []string
+	corsMiddleware      echo.MiddlewareFunc
+	compressionEnabled  bool
+	compression         []options.Option[CompressionOptions]
+	bodyLimitEnabled    bool
+	bodyLimit           int64
+	bodyGroupLimits     map[string]int64
+	tracingEnabled      bool
+	tracingServerName   string
+	tracerProvider      trace.TracerProvider
+	propagator          propagation.TextMapPropagator
+	accessLogger        *AccessLogger
+}
+
+// WithRedactedQueryParams sets the query parameters whose values are redacted in the request logs,
+// e.g. to also redact addresses. It replaces DefaultRedactedQueryParams.
+func WithRedactedQueryParams(params ...string) options.Option[EchoOptions] {
+	return func(o *EchoOptions) {
+		o.redactedQueryParams = params
+	}
+}
+
+// WithRedactedHeaders sets the headers whose values are redacted in the request logs.
+// It replaces DefaultRedactedHeaders.
+func WithRedactedHeaders(headers ...string) options.Option[EchoOptions] {
+	return func(o *EchoOptions) {
+		o.redactedHeaders = headers
+	}
+}
+
+// WithLoggedHeaders sets the request headers that are added to the request logs.
+func WithLoggedHeaders(headers ...string) options.Option[EchoOptions] {
+	return func(o *EchoOptions) {
+		o.loggedHeaders = headers
+	}
+}
+
+// Redactor removes sensitive query parameters and headers from request information before it is logged,
+// to keep secrets and PII out of log aggregation.
+type Redactor struct {
+	queryParams map[string]struct{}
+	headers     map[string]struct{}
+}
+
+// NewRedactor creates a new Redactor. Query parameters and headers are matched case-insensitively.
+func NewRedactor(queryParams []string, headers []string) *Redactor {
+	r := &Redactor{
+		queryParams: make(map[string]struct{}, len(queryParams)),
+		headers:     make(map[string]struct{}, len(headers)),
+	}
+	for _, param := range queryParams {
+		r.queryParams[strings.ToLower(param)] = struct{}{}
+	}
+	for _, header := range headers {
+		r.headers[http.CanonicalHeaderKey(header)] = struct{}{}
+	}
+
+	return r
+}
+
+// RedactURI replaces the values of the redacted query parameters in the given request URI.
+func (r *Redactor) RedactURI(uri string) string {
+	path, rawQuery, hasQuery := strings.Cut(uri, "?")
+	if !hasQuery || len(r.queryParams) == 0 {
+		return uri
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		// don't risk leaking anything that could not be parsed
+		return path + "?" + RedactedValue
+	}
+
+	redacted := false
+	for param, values := range query {
+		if _, exists := r.queryParams[strings.ToLower(param)]; !exists {
+			continue
+		}
+		for i := range values {
+			values[i] = RedactedValue
+		}
+		redacted = true
+	}
+	if !redacted {
+		return uri
+	}
+
+	// keep the redaction marker readable instead of percent-encoding it
+	return path + "?" + strings.ReplaceAll(query.Encode(), url.QueryEscape(RedactedValue), RedactedValue)
+}
+
+// RedactHeader returns the value of the header, or RedactedValue if the header is redacted.
+func (r *Redactor) RedactHeader(header string, value string) string {
+	if _, exists := r.headers[http.CanonicalHeaderKey(header)]; exists {
+		return RedactedValue
+	}
+
+	return value
+}
+
+// RedactHeaders formats the given headers as "name: value" pairs in a stable order, with the redacted values replaced.
+func (r *Redactor) RedactHeaders(headers map[string][]string) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		for _, value := range headers[name] {
+			pairs = append(pairs, name+": "+r.RedactHeader(name, value))
+		}
+	}
+
+	return strings.Join(pairs, ", ")
+}
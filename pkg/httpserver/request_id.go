@@ -0,0 +1,87 @@
+package httpserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// maxRequestIDLength is the maximum length of request IDs sent by clients, longer IDs are replaced.
+	maxRequestIDLength = 128
+
+	contextKeyRequestID = "requestID"
+)
+
+type requestIDContextKey struct{}
+
+// RequestIDMiddleware returns the middleware that assigns an ID to every request.
+// The ID of the X-Request-ID header of the request is kept, e.g. if it was assigned by a reverse proxy,
+// otherwise a random ID is generated. The ID is returned in the X-Request-ID header of the response,
+// it is part of the error responses and the request logs, so failing calls can be correlated with the logs.
+func RequestIDMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			requestID := c.Request().Header.Get(echo.HeaderXRequestID)
+			if !validRequestID(requestID) {
+				requestID = generateRequestID()
+				c.Request().Header.Set(echo.HeaderXRequestID, requestID)
+			}
+
+			c.Set(contextKeyRequestID, requestID)
+			c.SetRequest(c.Request().WithContext(context.WithValue(c.Request().Context(), requestIDContextKey{}, requestID)))
+			c.Response().Header().Set(echo.HeaderXRequestID, requestID)
+
+			return next(c)
+		}
+	}
+}
+
+// RequestID returns the ID of the request assigned by the RequestIDMiddleware, empty if there is none.
+func RequestID(c echo.Context) string {
+	requestID, ok := c.Get(contextKeyRequestID).(string)
+	if !ok {
+		return ""
+	}
+
+	return requestID
+}
+
+// RequestIDFromContext returns the ID of the request the context belongs to, empty if there is none.
+// It allows code without access to the echo.Context, e.g. calls to the node, to log the ID.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, ok := ctx.Value(requestIDContextKey{}).(string)
+	if !ok {
+		return ""
+	}
+
+	return requestID
+}
+
+// validRequestID reports whether the ID sent by a client can be used.
+// Only printable ASCII characters without spaces and quotes are accepted, so the ID can't break the log lines.
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(requestID); i++ {
+		if char := requestID[i]; char <= ' ' || char > '~' || char == '"' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func generateRequestID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		// the random source of the OS does not fail in practice
+		panic(err)
+	}
+
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,152 @@
+package httpserver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+)
+
+// ErrInvalidRouteGroup is returned if the parameters of a route group are invalid.
+var ErrInvalidRouteGroup = errors.New("invalid route group")
+
+// ParametersRateLimit are the rate limit settings of a route group.
+type ParametersRateLimit struct {
+	// Enabled is whether the requests of every client IP are rate limited.
+	Enabled bool `default:"false" usage:"whether the requests of every client IP are rate limited"`
+	// RequestsPerSecond is the average amount of requests per second allowed per client IP.
+	RequestsPerSecond float64 `default:"10" usage:"the average amount of requests per second allowed per client IP"`
+	// Burst is the maximum amount of requests a client IP may send at once.
+	Burst int `default:"20" usage:"the maximum amount of requests a client IP may send at once"`
+}
+
+// ParametersRouteGroup are the policies of a route group, they can be registered as config parameters of a component,
+// so operators tune them without code changes.
+type ParametersRouteGroup struct {
+	// Prefix is the path prefix of the routes of the group, e.g. "/api/indexer/v1/outputs".
+	Prefix string `usage:"the path prefix of the routes of the group"`
+	// Auth is the auth policy of the routes of the group, empty makes them public.
+	Auth AuthPolicy `usage:"the auth policy of the routes of the group, empty makes them public"`
+	// RateLimit are the rate limit settings of the group.
+	RateLimit ParametersRateLimit
+	// CORS are the CORS settings of the group, they replace the CORS settings of the API for the routes of the group.
+	CORS ParametersCORS
+	// BodyLimit is the maximum size in bytes of request bodies, 0 keeps the limit of the API.
+	BodyLimit int64 `default:"0" usage:"the maximum size in bytes of request bodies, 0 keeps the limit of the API"`
+}
+
+// RouteGroupFactory builds echo route groups from their ParametersRouteGroup.
+type RouteGroupFactory struct {
+	authPresets        map[AuthPolicy]echo.MiddlewareFunc
+	rateLimiterOptions []options.Option[RateLimiter]
+}
+
+// WithRouteGroupAuthPreset registers the middleware that enforces the given auth policy.
+func WithRouteGroupAuthPreset(policy AuthPolicy, middleware echo.MiddlewareFunc) options.Option[RouteGroupFactory] {
+	return func(f *RouteGroupFactory) {
+		f.authPresets[policy] = middleware
+	}
+}
+
+// WithRouteGroupRateLimiterOptions sets the options of the rate limiters of the groups, e.g. the trusted proxies.
+func WithRouteGroupRateLimiterOptions(rateLimiterOptions ...options.Option[RateLimiter]) options.Option[RouteGroupFactory] {
+	return func(f *RouteGroupFactory) {
+		f.rateLimiterOptions = append(f.rateLimiterOptions, rateLimiterOptions...)
+	}
+}
+
+// NewRouteGroupFactory creates a new RouteGroupFactory.
+func NewRouteGroupFactory(opts ...options.Option[RouteGroupFactory]) *RouteGroupFactory {
+	return options.Apply(&RouteGroupFactory{
+		authPresets: make(map[AuthPolicy]echo.MiddlewareFunc),
+	}, opts)
+}
+
+// Middlewares returns the middlewares that enforce the policies of the group in the order they are applied:
+// CORS, so preflight requests are answered without credentials, then the body limit, the rate limit and the auth.
+func (f *RouteGroupFactory) Middlewares(params *ParametersRouteGroup) ([]echo.MiddlewareFunc, error) {
+	middlewares := make([]echo.MiddlewareFunc, 0, 4)
+
+	if params.CORS.Enabled {
+		corsMiddleware, err := CORSMiddleware(&params.CORS)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "group %s", params.Prefix)
+		}
+		middlewares = append(middlewares, corsMiddleware)
+	}
+
+	if params.BodyLimit < 0 {
+		return nil, errors.Wrapf(ErrInvalidRouteGroup, "body limit of group %s must not be negative", params.Prefix)
+	}
+	if params.BodyLimit > 0 {
+		middlewares = append(middlewares, BodyLimitMiddleware(params.BodyLimit))
+	}
+
+	if params.RateLimit.Enabled {
+		rateLimiter, err := NewRateLimiter(params.RateLimit.RequestsPerSecond, params.RateLimit.Burst, f.rateLimiterOptions...)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidRouteGroup, "group %s: %s", params.Prefix, err)
+		}
+		middlewares = append(middlewares, rateLimiter.Middleware())
+	}
+
+	if params.Auth != AuthPolicyPublic {
+		authMiddleware, exists := f.authPresets[params.Auth]
+		if !exists {
+			// fail closed, a typo in the config must not expose a protected group
+			return nil, fmt.Errorf("%w: %s for group %s", ErrUnknownAuthPolicy, params.Auth, params.Prefix)
+		}
+		middlewares = append(middlewares, authMiddleware)
+	}
+
+	return middlewares, nil
+}
+
+// Group creates the route group of the Echo instance that enforces the policies of the parameters.
+func (f *RouteGroupFactory) Group(e *echo.Echo, params *ParametersRouteGroup) (*echo.Group, error) {
+	if !strings.HasPrefix(params.Prefix, "/") {
+		return nil, errors.Wrapf(ErrInvalidRouteGroup, "prefix %q must start with \"/\"", params.Prefix)
+	}
+
+	middlewares, err := f.Middlewares(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.Group(params.Prefix, middlewares...), nil
+}
+
+// Groups creates the route groups of all parameters, keyed by the names of the groups in the config.
+// It fails without creating any group if the parameters of a group are invalid.
+func (f *RouteGroupFactory) Groups(e *echo.Echo, params map[string]*ParametersRouteGroup) (map[string]*echo.Group, error) {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	middlewares := make(map[string][]echo.MiddlewareFunc, len(params))
+	for _, name := range names {
+		groupParams := params[name]
+		if !strings.HasPrefix(groupParams.Prefix, "/") {
+			return nil, errors.Wrapf(ErrInvalidRouteGroup, "prefix %q of group %s must start with \"/\"", groupParams.Prefix, name)
+		}
+
+		groupMiddlewares, err := f.Middlewares(groupParams)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "group %s", name)
+		}
+		middlewares[name] = groupMiddlewares
+	}
+
+	groups := make(map[string]*echo.Group, len(params))
+	for _, name := range names {
+		groups[name] = e.Group(params[name].Prefix, middlewares[name]...)
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,214 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/core/logger"
+)
+
+// ErrUnknownAuthPolicy is returned if a route uses an auth policy without a registered middleware preset.
+var ErrUnknownAuthPolicy = errors.New("unknown auth policy")
+
+// AuthPolicy names the authentication requirements of a route.
+// Every policy except AuthPolicyPublic needs a middleware preset registered with WithAuthPreset.
+type AuthPolicy string
+
+const (
+	// AuthPolicyPublic routes are accessible without authentication.
+	AuthPolicyPublic AuthPolicy = ""
+	// AuthPolicyProtected routes require the preset registered for AuthPolicyProtected.
+	AuthPolicyProtected AuthPolicy = "protected"
+)
+
+// CachePolicy describes the Cache-Control header set on the responses of a route.
+// The zero value does not set the header.
+type CachePolicy struct {
+	// NoStore forbids caching the response.
+	NoStore bool
+	// MaxAge is the duration a response may be cached by clients and proxies.
+	MaxAge time.Duration
+}
+
+var (
+	// CachePolicyNone does not set the Cache-Control header.
+	CachePolicyNone = CachePolicy{}
+	// CachePolicyNoStore forbids caching, e.g. for responses that depend on the latest ledger state.
+	CachePolicyNoStore = CachePolicy{NoStore: true}
+)
+
+// CacheFor allows responses to be cached for the given duration, e.g. for confirmed and immutable data.
+func CacheFor(maxAge time.Duration) CachePolicy {
+	return CachePolicy{MaxAge: maxAge}
+}
+
+func (p CachePolicy) header() string {
+	switch {
+	case p.NoStore:
+		return "no-store"
+	case p.MaxAge > 0:
+		return "public, max-age=" + strconv.Itoa(int(p.MaxAge.Seconds()))
+	default:
+		return ""
+	}
+}
+
+func (p CachePolicy) middleware() echo.MiddlewareFunc {
+	header := p.header()
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if header != "" {
+				c.Response().Header().Set(echo.HeaderCacheControl, header)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// Route is the declarative description of a route.
+type Route struct {
+	// Method is the HTTP method, e.g. http.MethodGet.
+	Method string
+	// Path is the path relative to the prefix of the RouteTable, e.g. "/blocks/:blockID".
+	Path string
+	// Handler handles the requests.
+	Handler echo.HandlerFunc
+	// OperationID uniquely identifies the route in the OpenAPI document and names the method of generated clients.
+	// It is derived from the method and path if empty.
+	OperationID string
+	// Summary is a short description of the route used in the OpenAPI document.
+	Summary string
+	// Description is a detailed description of the route used in the OpenAPI document.
+	Description string
+	// Tags group the route in the OpenAPI document.
+	Tags []string
+	// Parameters are the query and header parameters of the route used in the OpenAPI document.
+	// The path parameters are derived from the path, declared path parameters replace the derived ones,
+	// e.g. to add a description.
+	Parameters []*OpenAPIParameter
+	// RequestBody is the schema of the JSON request body, e.g. SchemaOf(&SubmitRequest{}).
+	RequestBody *OpenAPISchema
+	// Responses are the schemas of the JSON responses by status code, 0 declares the default response.
+	Responses map[int]*OpenAPISchema
+	// Auth is the auth policy of the route.
+	Auth AuthPolicy
+	// Cache is the cache policy of the route.
+	Cache CachePolicy
+	// AllowUnsynced serves the route even if the SyncPolicy of the RouteTable reports that the node is not synced.
+	AllowUnsynced bool
+	// Middlewares are additional middlewares applied after the auth, sync and cache policies.
+	Middlewares []echo.MiddlewareFunc
+}
+
+// APIRouteRegistrar registers the route prefix of an app at the node, e.g. a NodeBridge or NodeBridges.
+type APIRouteRegistrar interface {
+	RegisterAPIRoute(ctx context.Context, route string, bindAddress string) error
+	UnregisterAPIRoute(ctx context.Context, route string) error
+}
+
+// RouteTable is the single source of truth for the routes of an app.
+// It assembles the echo routes, the OpenAPI document and the route registration at the node.
+type RouteTable struct {
+	apiRoute                 string
+	routes                   []*Route
+	authPresets              map[AuthPolicy]echo.MiddlewareFunc
+	syncPolicy               *SyncPolicy
+	responseValidationLogger *logger.Logger
+}
+
+// WithAuthPreset registers the middleware that enforces the given auth policy.
+func WithAuthPreset(policy AuthPolicy, middleware echo.MiddlewareFunc) options.Option[RouteTable] {
+	return func(t *RouteTable) {
+		t.authPresets[policy] = middleware
+	}
+}
+
+// NewRouteTable creates a new RouteTable for the given API route, e.g. "proof/v1".
+// The routes are served below "/api/<apiRoute>", which is the path the node forwards to the app.
+func NewRouteTable(apiRoute string, routes []*Route, opts ...options.Option[RouteTable]) *RouteTable {
+	return options.Apply(&RouteTable{
+		apiRoute:    apiRoute,
+		routes:      routes,
+		authPresets: make(map[AuthPolicy]echo.MiddlewareFunc),
+	}, opts)
+}
+
+// APIRoute returns the API route that is registered at the node.
+func (t *RouteTable) APIRoute() string {
+	return t.apiRoute
+}
+
+// Prefix returns the path prefix of all routes in the table.
+func (t *RouteTable) Prefix() string {
+	return path.Join("/api", t.apiRoute)
+}
+
+// Routes returns all routes in the table.
+func (t *RouteTable) Routes() []*Route {
+	return t.routes
+}
+
+// middlewares returns the middlewares of a route in the order they are applied.
+func (t *RouteTable) middlewares(route *Route) ([]echo.MiddlewareFunc, error) {
+	middlewares := make([]echo.MiddlewareFunc, 0, len(route.Middlewares)+3)
+
+	if route.Auth != AuthPolicyPublic {
+		authMiddleware, exists := t.authPresets[route.Auth]
+		if !exists {
+			// fail closed, a typo must not expose a protected route
+			return nil, fmt.Errorf("%w: %s for route %s %s", ErrUnknownAuthPolicy, route.Auth, route.Method, route.Path)
+		}
+		middlewares = append(middlewares, authMiddleware)
+	}
+
+	if t.syncPolicy != nil && !route.AllowUnsynced {
+		middlewares = append(middlewares, t.syncPolicy.Middleware())
+	}
+
+	middlewares = append(middlewares, route.Cache.middleware())
+	if t.responseValidationLogger != nil && len(route.Responses) > 0 {
+		middlewares = append(middlewares, responseValidationMiddleware(t.responseValidationLogger, route))
+	}
+	middlewares = append(middlewares, route.Middlewares...)
+
+	return middlewares, nil
+}
+
+// Register adds all routes of the table to the Echo instance.
+// It fails without adding any routes if a route uses an unknown auth policy.
+func (t *RouteTable) Register(e *echo.Echo) error {
+	middlewares := make([][]echo.MiddlewareFunc, len(t.routes))
+	for i, route := range t.routes {
+		routeMiddlewares, err := t.middlewares(route)
+		if err != nil {
+			return err
+		}
+		middlewares[i] = routeMiddlewares
+	}
+
+	group := e.Group(t.Prefix())
+	for i, route := range t.routes {
+		group.Add(route.Method, route.Path, route.Handler, middlewares[i]...)
+	}
+
+	return nil
+}
+
+// RegisterAtNode registers the API route of the table at the node, so the node forwards the requests to bindAddress.
+func (t *RouteTable) RegisterAtNode(ctx context.Context, registrar APIRouteRegistrar, bindAddress string) error {
+	return registrar.RegisterAPIRoute(ctx, t.apiRoute, bindAddress)
+}
+
+// UnregisterAtNode removes the API route of the table from the node.
+func (t *RouteTable) UnregisterAtNode(ctx context.Context, registrar APIRouteRegistrar) error {
+	return registrar.UnregisterAPIRoute(ctx, t.apiRoute)
+}
@@ -0,0 +1,129 @@
+package httpserver
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/core/logger"
+)
+
+// inFlightCounters are the counters of the in-flight requests of the Echo instances passed to Serve, keyed by the instance.
+var inFlightCounters sync.Map
+
+// inFlightCounter returns the counter of the in-flight requests of the Echo instance.
+// The counting middleware is only added the first time, so serving an instance again does not stack it.
+func inFlightCounter(e *echo.Echo) *int64 {
+	counter, loaded := inFlightCounters.LoadOrStore(e, new(int64))
+	inFlight, _ := counter.(*int64)
+	if !loaded {
+		e.Pre(func(next echo.HandlerFunc) echo.HandlerFunc {
+			return func(c echo.Context) error {
+				atomic.AddInt64(inFlight, 1)
+				defer atomic.AddInt64(inFlight, -1)
+
+				return next(c)
+			}
+		})
+	}
+
+	return inFlight
+}
+
+// ServeOptions are the optional settings of Serve.
+type ServeOptions struct {
+	logger       *logger.Logger
+	tlsConfig    *tls.Config
+	drainTimeout time.Duration
+	startOptions []options.Option[StartOptions]
+}
+
+// WithServeLogger logs the start and the shutdown of the server, including the amount of in-flight requests.
+func WithServeLogger(logger *logger.Logger) options.Option[ServeOptions] {
+	return func(o *ServeOptions) {
+		o.logger = logger
+	}
+}
+
+// WithServeTLSConfig serves HTTPS with the given TLS config, e.g. the TLSConfig of a CertificateReloader.
+func WithServeTLSConfig(tlsConfig *tls.Config) options.Option[ServeOptions] {
+	return func(o *ServeOptions) {
+		o.tlsConfig = tlsConfig
+	}
+}
+
+// WithServeDrainTimeout sets the maximum duration in-flight requests are awaited on shutdown.
+func WithServeDrainTimeout(drainTimeout time.Duration) options.Option[ServeOptions] {
+	return func(o *ServeOptions) {
+		o.drainTimeout = drainTimeout
+	}
+}
+
+// WithServeStartOptions sets the options the server is started with, e.g. WithHTTP2 or WithH2C.
+func WithServeStartOptions(startOptions ...options.Option[StartOptions]) options.Option[ServeOptions] {
+	return func(o *ServeOptions) {
+		o.startOptions = append(o.startOptions, startOptions...)
+	}
+}
+
+// Serve starts the HTTP server of the Echo instance on the given bind address and blocks until the context is canceled
+// or the server fails. On cancellation no new connections are accepted, and the in-flight requests are drained
+// for up to the drain timeout before the remaining connections are closed.
+// It returns nil after a graceful shutdown, and an error if the server failed or the drain timeout was reached.
+func Serve(ctx context.Context, e *echo.Echo, bindAddress string, opts ...options.Option[ServeOptions]) error {
+	serveOpts := options.Apply(&ServeOptions{
+		drainTimeout: 5 * time.Second,
+	}, opts)
+
+	inFlight := inFlightCounter(e)
+
+	logInfof := func(template string, args ...interface{}) {
+		if serveOpts.logger != nil {
+			serveOpts.logger.Infof(template, args...)
+		}
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		logInfof("starting HTTP server on %s ...", bindAddress)
+		serverErr <- Start(e, bindAddress, serveOpts.tlsConfig, serveOpts.startOptions...)
+	}()
+
+	select {
+	case err := <-serverErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+
+		return errors.Wrap(err, "HTTP server failed")
+
+	case <-ctx.Done():
+	}
+
+	logInfof("stopping HTTP server, draining %d in-flight requests ...", atomic.LoadInt64(inFlight))
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), serveOpts.drainTimeout)
+	defer shutdownCancel()
+
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		remaining := atomic.LoadInt64(inFlight)
+		_ = e.Close()
+
+		return errors.Wrapf(err, "draining HTTP server failed, %d requests aborted", remaining)
+	}
+
+	if err := <-serverErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return errors.Wrap(err, "HTTP server failed")
+	}
+
+	logInfof("stopping HTTP server ... done")
+
+	return nil
+}
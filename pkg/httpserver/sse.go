@@ -0,0 +1,171 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+const (
+	// MIMETextEventStream is the content type of Server-Sent Events.
+	MIMETextEventStream = "text/event-stream"
+
+	// HeaderLastEventID is sent by clients that reconnect to a stream, it contains the ID of the last received event.
+	HeaderLastEventID = "Last-Event-ID"
+)
+
+// ErrStreamingNotSupported is returned if the response writer can't be flushed, e.g. because of a middleware.
+var ErrStreamingNotSupported = echo.NewHTTPError(http.StatusInternalServerError, "streaming not supported")
+
+// SSEEvent is a single Server-Sent Event.
+type SSEEvent struct {
+	// ID is the ID of the event, clients send the last received ID when they reconnect, e.g. a milestone index.
+	ID string
+	// Event is the type of the event, clients can listen to specific types, e.g. "milestone".
+	Event string
+	// Data is the data of the event, it is JSON encoded unless it is a string or a byte slice.
+	Data interface{}
+}
+
+// SSEStream streams Server-Sent Events to a client.
+type SSEStream struct {
+	c       echo.Context
+	flusher http.Flusher
+}
+
+// NewSSEStream starts a Server-Sent Events stream on the response of the request.
+// It must be called before anything else is written to the response.
+// The compression middleware skips the stream, so every event reaches the client immediately.
+func NewSSEStream(c echo.Context) (*SSEStream, error) {
+	flusher, ok := c.Response().Writer.(http.Flusher)
+	if !ok {
+		return nil, ErrStreamingNotSupported
+	}
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, MIMETextEventStream)
+	header.Set(echo.HeaderCacheControl, "no-cache")
+	header.Set(echo.HeaderConnection, "keep-alive")
+	// disable the response buffering of reverse proxies like nginx
+	header.Set("X-Accel-Buffering", "no")
+
+	c.Response().WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	return &SSEStream{c: c, flusher: flusher}, nil
+}
+
+// LastEventID returns the ID of the last event the client received before it reconnected, empty for new clients.
+// Handlers use it to resume the stream after that event.
+func (s *SSEStream) LastEventID() string {
+	if lastEventID := s.c.Request().Header.Get(HeaderLastEventID); lastEventID != "" {
+		return lastEventID
+	}
+
+	// browsers can't set headers on the initial EventSource request, so it is also accepted as query parameter
+	return s.c.QueryParam("lastEventId")
+}
+
+// Done returns a channel that is closed when the client disconnected or the server shuts down.
+func (s *SSEStream) Done() <-chan struct{} {
+	return s.c.Request().Context().Done()
+}
+
+// Send writes the event and flushes it to the client.
+func (s *SSEStream) Send(event *SSEEvent) error {
+	var data []byte
+	switch typedData := event.Data.(type) {
+	case string:
+		data = []byte(typedData)
+	case []byte:
+		data = typedData
+	default:
+		var err error
+		if data, err = json.Marshal(typedData); err != nil {
+			return errors.Wrap(err, "marshaling event data failed")
+		}
+	}
+
+	var sb strings.Builder
+	if event.ID != "" {
+		sb.WriteString("id: " + sanitizeSSEField(event.ID) + "\n")
+	}
+	if event.Event != "" {
+		sb.WriteString("event: " + sanitizeSSEField(event.Event) + "\n")
+	}
+	// every line of the data needs its own field, the client joins them with newlines
+	for _, line := range strings.Split(string(data), "\n") {
+		sb.WriteString("data: " + line + "\n")
+	}
+	sb.WriteString("\n")
+
+	return s.write(sb.String())
+}
+
+// SendRetry tells the client how long to wait before reconnecting after the stream was interrupted.
+func (s *SSEStream) SendRetry(retry time.Duration) error {
+	return s.write(fmt.Sprintf("retry: %d\n\n", retry.Milliseconds()))
+}
+
+// heartbeat writes a comment, which is ignored by clients but keeps proxies from closing the idle connection
+// and detects disconnected clients.
+func (s *SSEStream) heartbeat() error {
+	return s.write(": heartbeat\n\n")
+}
+
+func (s *SSEStream) write(message string) error {
+	if _, err := s.c.Response().Write([]byte(message)); err != nil {
+		return errors.Wrap(err, "writing event failed")
+	}
+	s.flusher.Flush()
+
+	return nil
+}
+
+// sanitizeSSEField removes line breaks, they would end the field.
+func sanitizeSSEField(value string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
+
+// StreamSSE streams the events of the channel to the client until the channel is closed, the client disconnects,
+// or a write fails. A heartbeat is sent if no event was sent within the heartbeat interval, 0 disables the heartbeats.
+// It returns nil if the stream ended because the channel was closed or the client disconnected.
+func StreamSSE(c echo.Context, events <-chan *SSEEvent, heartbeatInterval time.Duration) error {
+	stream, err := NewSSEStream(c)
+	if err != nil {
+		return err
+	}
+
+	var heartbeat <-chan time.Time
+	if heartbeatInterval > 0 {
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	for {
+		select {
+		case <-stream.Done():
+			return nil
+
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+
+		case <-heartbeat:
+			if err := stream.heartbeat(); err != nil {
+				return err
+			}
+		}
+	}
+}
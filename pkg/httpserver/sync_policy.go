@@ -0,0 +1,85 @@
+package httpserver
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+)
+
+const (
+	// ErrorReasonNotSynced is the reason of a request that was rejected because the node is not synced.
+	ErrorReasonNotSynced = "not_synced"
+
+	// errorFieldNode is the field of the error detail of requests rejected by the SyncPolicy.
+	errorFieldNode = "node"
+)
+
+// ErrNodeNotSynced is returned by routes guarded by the SyncPolicy while the node is not synced.
+var ErrNodeNotSynced = echo.NewHTTPError(http.StatusServiceUnavailable, "node not synced")
+
+// SyncPolicy rejects the requests of all routes of a RouteTable with ErrNodeNotSynced while the node is not synced,
+// so the handlers don't have to check the sync status themselves.
+// Routes with AllowUnsynced set, e.g. status routes, are still served.
+type SyncPolicy struct {
+	synced func() bool
+}
+
+// NewSyncPolicy creates a new SyncPolicy. The synced function reports whether the node is synced,
+// e.g. NodeBridge.IsNodeSynced, or whether the app is synced, e.g. using the phase of a syncstate.Machine.
+func NewSyncPolicy(synced func() bool) *SyncPolicy {
+	return &SyncPolicy{
+		synced: synced,
+	}
+}
+
+// Synced returns whether the guarded routes are served.
+func (p *SyncPolicy) Synced() bool {
+	return p.synced()
+}
+
+// Middleware returns the middleware that rejects requests with ErrNodeNotSynced while the node is not synced.
+// The error response contains a detail with ErrorReasonNotSynced, so clients can tell it apart from other 503 responses.
+func (p *SyncPolicy) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !p.synced() {
+				return WithErrorDetails(ErrNodeNotSynced, HTTPErrorDetail{Field: errorFieldNode, Reason: ErrorReasonNotSynced})
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// ReadinessResponse defines the response of the readiness handler of the SyncPolicy.
+type ReadinessResponse struct {
+	// Ready tells whether the guarded routes are served.
+	Ready bool `json:"ready"`
+}
+
+// ReadinessHandler returns a handler for readiness probes of load balancers and orchestrators.
+// It responds with 200 while the routes are served and with 503 while the node is not synced.
+func (p *SyncPolicy) ReadinessHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if !p.synced() {
+			return JSONResponse(c, http.StatusServiceUnavailable, &ReadinessResponse{Ready: false})
+		}
+
+		return JSONResponse(c, http.StatusOK, &ReadinessResponse{Ready: true})
+	}
+}
+
+// WithSyncPolicy guards all routes of the RouteTable that don't set AllowUnsynced with the given SyncPolicy.
+func WithSyncPolicy(policy *SyncPolicy) options.Option[RouteTable] {
+	return func(t *RouteTable) {
+		t.syncPolicy = policy
+	}
+}
+
+// IsNodeNotSynced returns whether the error was returned because the node is not synced.
+func IsNodeNotSynced(err error) bool {
+	return errors.Is(err, ErrNodeNotSynced)
+}
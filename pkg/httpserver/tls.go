@@ -0,0 +1,185 @@
+package httpserver
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/http2"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+)
+
+// DefaultCertificateCheckInterval is the default interval in which the certificate files are checked for changes.
+const DefaultCertificateCheckInterval = 10 * time.Second
+
+// CertificateReloader serves a TLS certificate from files and reloads it when the files change on disk,
+// e.g. after a renewal, without restarting the server.
+// The files are checked at most once per check interval during TLS handshakes.
+type CertificateReloader struct {
+	certFile      string
+	keyFile       string
+	checkInterval time.Duration
+
+	certificateMutex sync.Mutex
+	certificate      *tls.Certificate
+	certModTime      time.Time
+	keyModTime       time.Time
+	lastCheck        time.Time
+}
+
+// NewCertificateReloader creates a new CertificateReloader and loads the certificate.
+func NewCertificateReloader(certFile string, keyFile string, checkInterval time.Duration) (*CertificateReloader, error) {
+	r := &CertificateReloader{
+		certFile:      certFile,
+		keyFile:       keyFile,
+		checkInterval: checkInterval,
+	}
+
+	certModTime, keyModTime, err := r.modTimes()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(certModTime, keyModTime); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *CertificateReloader) modTimes() (time.Time, time.Time, error) {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("reading TLS certificate file failed: %w", err)
+	}
+
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("reading TLS key file failed: %w", err)
+	}
+
+	return certInfo.ModTime(), keyInfo.ModTime(), nil
+}
+
+func (r *CertificateReloader) load(certModTime time.Time, keyModTime time.Time) error {
+	certificate, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("loading TLS certificate failed: %w", err)
+	}
+
+	r.certificate = &certificate
+	r.certModTime = certModTime
+	r.keyModTime = keyModTime
+	r.lastCheck = time.Now()
+
+	return nil
+}
+
+// GetCertificate returns the current certificate, it can be used as tls.Config.GetCertificate.
+// If the files changed but can't be loaded, e.g. because only one of them was replaced yet,
+// the previous certificate is served and the files are checked again after the check interval.
+func (r *CertificateReloader) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.certificateMutex.Lock()
+	defer r.certificateMutex.Unlock()
+
+	if time.Since(r.lastCheck) < r.checkInterval {
+		return r.certificate, nil
+	}
+	r.lastCheck = time.Now()
+
+	certModTime, keyModTime, err := r.modTimes()
+	if err != nil || (certModTime.Equal(r.certModTime) && keyModTime.Equal(r.keyModTime)) {
+		return r.certificate, nil
+	}
+
+	//nolint:errcheck // keep serving the previous certificate
+	_ = r.load(certModTime, keyModTime)
+
+	return r.certificate, nil
+}
+
+// TLSConfig returns a TLS config that serves the certificate of the reloader.
+func (r *CertificateReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: r.GetCertificate,
+	}
+}
+
+// StartOptions are the optional settings of Start.
+type StartOptions struct {
+	http2Enabled bool
+	h2cEnabled   bool
+	http2Server  *http2.Server
+}
+
+// WithHTTP2 serves HTTP/2 to clients that negotiate it during the TLS handshake, HTTP/1.1 is still served to all others.
+// It only applies to servers with a TLS config. The settings of the HTTP/2 server are the defaults if it is nil.
+func WithHTTP2(http2Server *http2.Server) options.Option[StartOptions] {
+	return func(o *StartOptions) {
+		o.http2Enabled = true
+		o.http2Server = http2Server
+	}
+}
+
+// WithH2C serves HTTP/2 without TLS (h2c) to clients that connect with prior knowledge or upgrade the connection,
+// e.g. behind a reverse proxy that terminates TLS. HTTP/1.1 is still served to all others.
+// It only applies to servers without a TLS config. The settings of the HTTP/2 server are the defaults if it is nil.
+func WithH2C(http2Server *http2.Server) options.Option[StartOptions] {
+	return func(o *StartOptions) {
+		o.h2cEnabled = true
+		o.http2Server = http2Server
+	}
+}
+
+// Start starts the HTTP server of the Echo instance on the given bind address.
+// If tlsConfig is not nil, the server speaks HTTPS, e.g. with the TLSConfig of a CertificateReloader.
+// Bind addresses prefixed with "unix://" bind a unix domain socket instead of TCP.
+// HTTP/2 is enabled via WithHTTP2 for HTTPS and via WithH2C for cleartext HTTP.
+func Start(e *echo.Echo, bindAddress string, tlsConfig *tls.Config, opts ...options.Option[StartOptions]) error {
+	startOpts := options.Apply(&StartOptions{}, opts)
+
+	http2Server := startOpts.http2Server
+	if http2Server == nil {
+		http2Server = &http2.Server{}
+	}
+
+	if tlsConfig != nil {
+		e.TLSServer.Addr = bindAddress
+		e.TLSServer.TLSConfig = tlsConfig
+
+		if startOpts.http2Enabled {
+			// the config of the caller is not modified, it may be shared with other servers
+			e.TLSServer.TLSConfig = tlsConfig.Clone()
+			if err := http2.ConfigureServer(e.TLSServer, http2Server); err != nil {
+				return fmt.Errorf("failed to configure HTTP/2: %w", err)
+			}
+		}
+	}
+
+	if IsUnixSocketAddress(bindAddress) {
+		listener, err := Listen(bindAddress)
+		if err != nil {
+			return err
+		}
+
+		if tlsConfig == nil {
+			e.Listener = listener
+		} else {
+			e.TLSListener = tls.NewListener(listener, e.TLSServer.TLSConfig)
+		}
+	}
+
+	if tlsConfig == nil {
+		if startOpts.h2cEnabled {
+			return e.StartH2CServer(bindAddress, http2Server)
+		}
+
+		return e.Start(bindAddress)
+	}
+
+	return e.StartServer(e.TLSServer)
+}
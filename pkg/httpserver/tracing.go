@@ -0,0 +1,75 @@
+package httpserver
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+)
+
+// tracerName is the name of the tracer that creates the spans of the requests.
+const tracerName = "github.com/iotaledger/inx-app/pkg/httpserver"
+
+// WithTracing starts an OpenTelemetry span for every request, see TracingMiddleware.
+// The serverName is the name of the app, e.g. "inx-indexer". If the tracer provider or the propagator are nil,
+// the global ones registered via otel.SetTracerProvider and otel.SetTextMapPropagator are used.
+func WithTracing(serverName string, tracerProvider trace.TracerProvider, propagator propagation.TextMapPropagator) options.Option[EchoOptions] {
+	return func(o *EchoOptions) {
+		o.tracingEnabled = true
+		o.tracingServerName = serverName
+		o.tracerProvider = tracerProvider
+		o.propagator = propagator
+	}
+}
+
+// TracingMiddleware starts a server span for every request, as child of the trace given in the traceparent header.
+// The span is named after the route template and annotated with the route and the status code,
+// and the trace context is written to the response headers, so clients can correlate their requests with the traces.
+// The span is part of the request context, so it is the parent of the calls the handlers make with that context.
+func TracingMiddleware(serverName string, tracerProvider trace.TracerProvider, propagator propagation.TextMapPropagator) echo.MiddlewareFunc {
+	if tracerProvider == nil {
+		tracerProvider = otel.GetTracerProvider()
+	}
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+
+	tracer := tracerProvider.Tracer(tracerName)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			route := c.Path()
+			spanName := route
+			if route == "" {
+				route = routeUnmatched
+				spanName = "HTTP " + req.Method
+			}
+
+			ctx := propagator.Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+			ctx, span := tracer.Start(ctx, spanName,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest(serverName, route, req)...),
+			)
+			defer span.End()
+
+			c.SetRequest(req.WithContext(ctx))
+			propagator.Inject(ctx, propagation.HeaderCarrier(c.Response().Header()))
+
+			err := next(c)
+			if err != nil {
+				span.RecordError(err)
+			}
+
+			status := responseStatus(c, err)
+			span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(status)...)
+			span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(status, trace.SpanKindServer))
+
+			return err
+		}
+	}
+}
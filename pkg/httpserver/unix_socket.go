@@ -0,0 +1,100 @@
+package httpserver
+
+import (
+	"net"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ProtocolUnix is the protocol of unix domain socket URLs.
+	ProtocolUnix = "unix"
+	// UnixSocketPrefix is the prefix of bind addresses that bind a unix domain socket instead of TCP,
+	// e.g. "unix:///var/run/inx-app.sock".
+	UnixSocketPrefix = ProtocolUnix + "://"
+)
+
+// IsUnixSocketAddress reports whether the bind address is a unix domain socket.
+func IsUnixSocketAddress(bindAddress string) bool {
+	return strings.HasPrefix(bindAddress, UnixSocketPrefix)
+}
+
+// UnixSocketPath returns the path of the socket file of a unix domain socket bind address.
+func UnixSocketPath(bindAddress string) (string, error) {
+	if !IsUnixSocketAddress(bindAddress) {
+		return "", errors.Errorf("not a unix socket address: %s", bindAddress)
+	}
+
+	socketPath := bindAddress[len(UnixSocketPrefix):]
+	if socketPath == "" {
+		return "", errors.Errorf("missing socket path in address: %s", bindAddress)
+	}
+
+	return socketPath, nil
+}
+
+// Listen creates the listener for the bind address, a unix domain socket if it is prefixed with "unix://",
+// otherwise a TCP listener.
+// A stale socket file of a previous run is removed before the socket is bound.
+// The socket file is removed again when the listener is closed.
+func Listen(bindAddress string) (net.Listener, error) {
+	if !IsUnixSocketAddress(bindAddress) {
+		return net.Listen("tcp", bindAddress)
+	}
+
+	socketPath, err := UnixSocketPath(bindAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := removeStaleUnixSocket(socketPath); err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "binding unix socket %s failed", socketPath)
+	}
+
+	return listener, nil
+}
+
+// removeStaleUnixSocket removes the socket file if it is not in use anymore.
+// Other files are never removed, so a misconfigured path doesn't delete data.
+func removeStaleUnixSocket(socketPath string) error {
+	fileInfo, err := os.Lstat(socketPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return errors.Wrapf(err, "checking unix socket %s failed", socketPath)
+	}
+
+	if fileInfo.Mode()&os.ModeSocket == 0 {
+		return errors.Errorf("unix socket path %s exists and is not a socket", socketPath)
+	}
+
+	// the socket is still in use if something accepts connections on it
+	if conn, err := net.Dial("unix", socketPath); err == nil {
+		_ = conn.Close()
+
+		return errors.Errorf("unix socket %s is already in use", socketPath)
+	}
+
+	if err := os.Remove(socketPath); err != nil {
+		return errors.Wrapf(err, "removing stale unix socket %s failed", socketPath)
+	}
+
+	return nil
+}
+
+// GetUnixSocketURL returns the URL of the path served on the unix domain socket.
+// The socket path is escaped into the host, so it can't be confused with the path of the request,
+// e.g. "unix://%2Fvar%2Frun%2Finx-app.sock/api/routes".
+func GetUnixSocketURL(socketPath string, path ...string) string {
+	return UnixSocketPrefix + url.PathEscape(socketPath) + strings.Join(path, "/")
+}
@@ -0,0 +1,403 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+)
+
+const (
+	// WebSocketMessageTypeSubscribe is sent by clients to subscribe to a topic.
+	WebSocketMessageTypeSubscribe = "subscribe"
+	// WebSocketMessageTypeUnsubscribe is sent by clients to unsubscribe from a topic.
+	WebSocketMessageTypeUnsubscribe = "unsubscribe"
+	// WebSocketMessageTypeSubscribed is sent to clients to confirm a subscription.
+	WebSocketMessageTypeSubscribed = "subscribed"
+	// WebSocketMessageTypeUnsubscribed is sent to clients to confirm that a subscription was removed.
+	WebSocketMessageTypeUnsubscribed = "unsubscribed"
+	// WebSocketMessageTypePublish is sent to clients with the data published to a topic they subscribed to.
+	WebSocketMessageTypePublish = "publish"
+	// WebSocketMessageTypeError is sent to clients if their message could not be handled.
+	WebSocketMessageTypeError = "error"
+)
+
+// ErrWebSocketTopicNotAllowed is sent to clients that subscribe to a topic rejected by the topic filter.
+var ErrWebSocketTopicNotAllowed = errors.New("topic not allowed")
+
+// WebSocketMessage is the message exchanged with the clients of a WebSocketHub.
+type WebSocketMessage struct {
+	// Type is the type of the message, e.g. WebSocketMessageTypeSubscribe.
+	Type string `json:"type"`
+	// Topic is the topic the message refers to.
+	Topic string `json:"topic,omitempty"`
+	// Data is the data published to the topic.
+	Data json.RawMessage `json:"data,omitempty"`
+	// Error describes why a message of the client could not be handled.
+	Error string `json:"error,omitempty"`
+}
+
+// WebSocketHub pushes live updates to WebSocket clients, e.g. new blocks or ledger changes to browsers.
+// Clients subscribe to topics by sending WebSocketMessages, and receive the data published to these topics.
+// Every client has its own send queue, clients that don't keep up are disconnected instead of blocking the publisher.
+type WebSocketHub struct {
+	upgrader       websocket.Upgrader
+	sendQueueSize  int
+	maxMessageSize int64
+	writeTimeout   time.Duration
+	pingInterval   time.Duration
+	topicFilter    func(topic string) bool
+
+	mutex   sync.RWMutex
+	clients map[*webSocketClient]struct{}
+	topics  map[string]map[*webSocketClient]struct{}
+}
+
+// WithWebSocketSendQueueSize sets the amount of messages that are queued per client before it is disconnected.
+func WithWebSocketSendQueueSize(sendQueueSize int) options.Option[WebSocketHub] {
+	return func(h *WebSocketHub) {
+		h.sendQueueSize = sendQueueSize
+	}
+}
+
+// WithWebSocketMaxMessageSize sets the maximum size in bytes of the messages sent by clients.
+func WithWebSocketMaxMessageSize(maxMessageSize int64) options.Option[WebSocketHub] {
+	return func(h *WebSocketHub) {
+		h.maxMessageSize = maxMessageSize
+	}
+}
+
+// WithWebSocketWriteTimeout sets the duration after which clients that don't accept a message are disconnected.
+func WithWebSocketWriteTimeout(writeTimeout time.Duration) options.Option[WebSocketHub] {
+	return func(h *WebSocketHub) {
+		h.writeTimeout = writeTimeout
+	}
+}
+
+// WithWebSocketPingInterval sets the interval in which clients are pinged,
+// clients that don't answer until the next ping are disconnected.
+func WithWebSocketPingInterval(pingInterval time.Duration) options.Option[WebSocketHub] {
+	return func(h *WebSocketHub) {
+		h.pingInterval = pingInterval
+	}
+}
+
+// WithWebSocketCheckOrigin sets the function that decides whether a browser of the given request may connect.
+// By default only requests without an Origin header or from the same host are allowed.
+func WithWebSocketCheckOrigin(checkOrigin func(r *http.Request) bool) options.Option[WebSocketHub] {
+	return func(h *WebSocketHub) {
+		h.upgrader.CheckOrigin = checkOrigin
+	}
+}
+
+// WithWebSocketTopicFilter sets the function that decides whether clients may subscribe to a topic.
+// By default all topics are allowed.
+func WithWebSocketTopicFilter(topicFilter func(topic string) bool) options.Option[WebSocketHub] {
+	return func(h *WebSocketHub) {
+		h.topicFilter = topicFilter
+	}
+}
+
+// NewWebSocketHub creates a new WebSocketHub.
+func NewWebSocketHub(opts ...options.Option[WebSocketHub]) *WebSocketHub {
+	return options.Apply(&WebSocketHub{
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+		},
+		sendQueueSize:  256,
+		maxMessageSize: 4096,
+		writeTimeout:   10 * time.Second,
+		pingInterval:   30 * time.Second,
+		topicFilter:    func(string) bool { return true },
+		clients:        make(map[*webSocketClient]struct{}),
+		topics:         make(map[string]map[*webSocketClient]struct{}),
+	}, opts)
+}
+
+// webSocketClient is a connected client of the WebSocketHub.
+type webSocketClient struct {
+	conn      *websocket.Conn
+	send      chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+	// the topics are guarded by the mutex of the hub.
+	topics map[string]struct{}
+}
+
+// close stops the write loop of the client, which closes the connection.
+func (c *webSocketClient) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+}
+
+// enqueue queues the message for the client without blocking, it returns false if the queue of the client is full.
+func (c *webSocketClient) enqueue(message []byte) bool {
+	select {
+	case <-c.done:
+		return false
+	case c.send <- message:
+		return true
+	default:
+		return false
+	}
+}
+
+// Handler returns the handler that upgrades the request to a WebSocket connection and serves the client until it disconnects.
+func (h *WebSocketHub) Handler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		conn, err := h.upgrader.Upgrade(c.Response(), c.Request(), nil)
+		if err != nil {
+			// the upgrader already responded with an error
+			return nil
+		}
+
+		client := &webSocketClient{
+			conn:   conn,
+			send:   make(chan []byte, h.sendQueueSize),
+			done:   make(chan struct{}),
+			topics: make(map[string]struct{}),
+		}
+
+		h.mutex.Lock()
+		h.clients[client] = struct{}{}
+		h.mutex.Unlock()
+
+		writeLoopDone := make(chan struct{})
+		go func() {
+			defer close(writeLoopDone)
+			h.writeLoop(client)
+		}()
+
+		h.readLoop(client)
+
+		h.removeClient(client)
+		client.close()
+		<-writeLoopDone
+
+		return nil
+	}
+}
+
+// readLoop handles the messages of the client until the connection fails or the client is closed.
+func (h *WebSocketHub) readLoop(client *webSocketClient) {
+	client.conn.SetReadLimit(h.maxMessageSize)
+
+	// the pong of a ping must arrive before the next ping is sent
+	_ = client.conn.SetReadDeadline(time.Now().Add(2 * h.pingInterval))
+	client.conn.SetPongHandler(func(string) error {
+		return client.conn.SetReadDeadline(time.Now().Add(2 * h.pingInterval))
+	})
+
+	for {
+		message := &WebSocketMessage{}
+		if err := client.conn.ReadJSON(message); err != nil {
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+				h.reply(client, &WebSocketMessage{Type: WebSocketMessageTypeError, Error: "invalid message: " + err.Error()})
+
+				continue
+			}
+
+			return
+		}
+
+		h.reply(client, h.handleMessage(client, message))
+	}
+}
+
+// handleMessage handles a message of the client and returns the reply.
+func (h *WebSocketHub) handleMessage(client *webSocketClient, message *WebSocketMessage) *WebSocketMessage {
+	switch message.Type {
+	case WebSocketMessageTypeSubscribe:
+		if message.Topic == "" || !h.topicFilter(message.Topic) {
+			return &WebSocketMessage{Type: WebSocketMessageTypeError, Topic: message.Topic, Error: ErrWebSocketTopicNotAllowed.Error()}
+		}
+		h.subscribe(client, message.Topic)
+
+		return &WebSocketMessage{Type: WebSocketMessageTypeSubscribed, Topic: message.Topic}
+
+	case WebSocketMessageTypeUnsubscribe:
+		h.unsubscribe(client, message.Topic)
+
+		return &WebSocketMessage{Type: WebSocketMessageTypeUnsubscribed, Topic: message.Topic}
+
+	default:
+		return &WebSocketMessage{Type: WebSocketMessageTypeError, Error: "unknown message type: " + message.Type}
+	}
+}
+
+// reply queues the reply for the client, the client is disconnected if its queue is full.
+func (h *WebSocketHub) reply(client *webSocketClient, reply *WebSocketMessage) {
+	data, err := json.Marshal(reply)
+	if err != nil {
+		return
+	}
+
+	if !client.enqueue(data) {
+		client.close()
+	}
+}
+
+// writeLoop writes the queued messages and the pings to the client until it is closed or a write fails.
+func (h *WebSocketHub) writeLoop(client *webSocketClient) {
+	ticker := time.NewTicker(h.pingInterval)
+	defer ticker.Stop()
+	defer func() { _ = client.conn.Close() }()
+
+	for {
+		select {
+		case <-client.done:
+			_ = client.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(h.writeTimeout))
+
+			return
+
+		case message := <-client.send:
+			_ = client.conn.SetWriteDeadline(time.Now().Add(h.writeTimeout))
+			if err := client.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				client.close()
+
+				return
+			}
+
+		case <-ticker.C:
+			if err := client.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(h.writeTimeout)); err != nil {
+				client.close()
+
+				return
+			}
+		}
+	}
+}
+
+func (h *WebSocketHub) subscribe(client *webSocketClient, topic string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	subscribers, exists := h.topics[topic]
+	if !exists {
+		subscribers = make(map[*webSocketClient]struct{})
+		h.topics[topic] = subscribers
+	}
+	subscribers[client] = struct{}{}
+	client.topics[topic] = struct{}{}
+}
+
+func (h *WebSocketHub) unsubscribe(client *webSocketClient, topic string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.unsubscribeWithoutLocking(client, topic)
+}
+
+func (h *WebSocketHub) unsubscribeWithoutLocking(client *webSocketClient, topic string) {
+	delete(client.topics, topic)
+
+	subscribers, exists := h.topics[topic]
+	if !exists {
+		return
+	}
+
+	delete(subscribers, client)
+	if len(subscribers) == 0 {
+		delete(h.topics, topic)
+	}
+}
+
+func (h *WebSocketHub) removeClient(client *webSocketClient) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for topic := range client.topics {
+		h.unsubscribeWithoutLocking(client, topic)
+	}
+	delete(h.clients, client)
+}
+
+// Publish sends the JSON encoded data to all clients subscribed to the topic and returns the amount of clients it was queued for.
+// Clients whose send queue is full are disconnected.
+func (h *WebSocketHub) Publish(topic string, data interface{}) (int, error) {
+	message, err := publishMessage(topic, data)
+	if err != nil {
+		return 0, err
+	}
+
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	return h.sendWithoutLocking(h.topics[topic], message), nil
+}
+
+// Broadcast sends the JSON encoded data to all connected clients, regardless of their subscriptions,
+// and returns the amount of clients it was queued for. Clients whose send queue is full are disconnected.
+func (h *WebSocketHub) Broadcast(topic string, data interface{}) (int, error) {
+	message, err := publishMessage(topic, data)
+	if err != nil {
+		return 0, err
+	}
+
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	return h.sendWithoutLocking(h.clients, message), nil
+}
+
+func publishMessage(topic string, data interface{}) ([]byte, error) {
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling data failed")
+	}
+
+	// the message is marshaled once for all clients
+	return json.Marshal(&WebSocketMessage{Type: WebSocketMessageTypePublish, Topic: topic, Data: dataJSON})
+}
+
+func (h *WebSocketHub) sendWithoutLocking(clients map[*webSocketClient]struct{}, message []byte) int {
+	sent := 0
+	for client := range clients {
+		if !client.enqueue(message) {
+			// a slow client must not block the publisher or delay the other clients
+			client.close()
+
+			continue
+		}
+		sent++
+	}
+
+	return sent
+}
+
+// HasSubscribers tells whether any client is subscribed to the topic,
+// so publishers can skip building expensive updates nobody receives.
+func (h *WebSocketHub) HasSubscribers(topic string) bool {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	return len(h.topics[topic]) > 0
+}
+
+// ClientsCount returns the amount of connected clients.
+func (h *WebSocketHub) ClientsCount() int {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	return len(h.clients)
+}
+
+// Close disconnects all clients.
+func (h *WebSocketHub) Close() {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	for client := range h.clients {
+		client.close()
+	}
+}
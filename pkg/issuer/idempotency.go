@@ -0,0 +1,124 @@
+package issuer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/iotaledger/hive.go/serializer/v2"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// payloadHash is the blake2b-256 hash of a serialized payload.
+type payloadHash [blake2b.Size256]byte
+
+// submission is an ongoing or finished issuance of a payload.
+type submission struct {
+	done     chan struct{}
+	blockID  iotago.BlockID
+	err      error
+	issuedAt time.Time
+}
+
+// recentSubmissions remembers the blocks issued for payloads within a time window,
+// so duplicate requests return the original block ID instead of issuing another block.
+type recentSubmissions struct {
+	window time.Duration
+
+	mutex       sync.Mutex
+	submissions map[payloadHash]*submission
+	lastPrune   time.Time
+}
+
+func newRecentSubmissions(window time.Duration) *recentSubmissions {
+	return &recentSubmissions{
+		window:      window,
+		submissions: make(map[payloadHash]*submission),
+	}
+}
+
+// acquire returns the submission of the payload, and whether the caller owns it and has to issue the block.
+// If the payload is already being issued or was issued within the window, the existing submission is returned.
+func (r *recentSubmissions) acquire(hash payloadHash) (*submission, bool) {
+	now := time.Now()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.pruneWithoutLocking(now)
+
+	if s, exists := r.submissions[hash]; exists && (s.issuedAt.IsZero() || now.Sub(s.issuedAt) < r.window) {
+		return s, false
+	}
+
+	s := &submission{done: make(chan struct{})}
+	r.submissions[hash] = s
+
+	return s, true
+}
+
+// complete finishes the submission and releases the waiting duplicates.
+// Failed submissions are forgotten, so a retry of the client issues the payload again.
+func (r *recentSubmissions) complete(hash payloadHash, s *submission, blockID iotago.BlockID, err error) {
+	r.mutex.Lock()
+	s.blockID = blockID
+	s.err = err
+	s.issuedAt = time.Now()
+	if err != nil && r.submissions[hash] == s {
+		delete(r.submissions, hash)
+	}
+	r.mutex.Unlock()
+
+	close(s.done)
+}
+
+// pruneWithoutLocking removes the submissions that were issued before the window, at most once per window.
+func (r *recentSubmissions) pruneWithoutLocking(now time.Time) {
+	if now.Sub(r.lastPrune) < r.window {
+		return
+	}
+	r.lastPrune = now
+
+	for hash, s := range r.submissions {
+		if !s.issuedAt.IsZero() && now.Sub(s.issuedAt) >= r.window {
+			delete(r.submissions, hash)
+		}
+	}
+}
+
+// hashPayload returns the hash of the serialized payload.
+func hashPayload(payload iotago.Payload, protoParams *iotago.ProtocolParameters) (payloadHash, error) {
+	payloadBytes, err := payload.Serialize(serializer.DeSeriModeNoValidation, protoParams)
+	if err != nil {
+		return payloadHash{}, errors.Wrap(err, "serializing payload failed")
+	}
+
+	return blake2b.Sum256(payloadBytes), nil
+}
+
+// issueBlockOnce issues the block, unless a block with the same payload is already being issued or was issued recently,
+// in which case the block ID of that block is returned.
+func (i *Issuer) issueBlockOnce(ctx context.Context, block *iotago.Block) (iotago.BlockID, error) {
+	hash, err := hashPayload(block.Payload, i.nodeBridge.ProtocolParameters())
+	if err != nil {
+		return iotago.EmptyBlockID(), err
+	}
+
+	s, owner := i.recentSubmissions.acquire(hash)
+	if !owner {
+		select {
+		case <-ctx.Done():
+			return iotago.EmptyBlockID(), ctx.Err()
+		case <-s.done:
+			return s.blockID, s.err
+		}
+	}
+
+	blockID, err := i.issueBlock(ctx, block)
+	i.recentSubmissions.complete(hash, s, blockID, err)
+
+	return blockID, err
+}
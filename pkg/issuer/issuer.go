@@ -0,0 +1,196 @@
+// Package issuer issues blocks with a given payload via the node.
+// It selects the tips, does the proof of work and handles rejected submissions
+// as advised by the node, so apps don't need to implement this themselves.
+package issuer
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+	"github.com/iotaledger/inx-app/pkg/pow"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// ErrMaxAttemptsReached is returned if a block was rejected by the node in all attempts.
+var ErrMaxAttemptsReached = errors.New("max attempts to issue the block reached")
+
+// Issuer issues blocks via the node.
+type Issuer struct {
+	nodeBridge *nodebridge.NodeBridge
+
+	tipsCount           uint32
+	powParallelism      int
+	refreshTipsInterval time.Duration
+	maxAttempts         int
+	retryDelay          time.Duration
+	tipScorer           *TipScorer
+	idempotencyWindow   time.Duration
+	recentSubmissions   *recentSubmissions
+}
+
+// WithTipsCount sets the amount of tips the blocks reference.
+func WithTipsCount(tipsCount uint32) options.Option[Issuer] {
+	return func(i *Issuer) {
+		i.tipsCount = tipsCount
+	}
+}
+
+// WithPoWParallelism sets the amount of workers used for the proof of work.
+func WithPoWParallelism(parallelism int) options.Option[Issuer] {
+	return func(i *Issuer) {
+		i.powParallelism = parallelism
+	}
+}
+
+// WithRefreshTipsInterval sets the duration after which the tips are refreshed if the proof of work did not finish yet.
+func WithRefreshTipsInterval(interval time.Duration) options.Option[Issuer] {
+	return func(i *Issuer) {
+		i.refreshTipsInterval = interval
+	}
+}
+
+// WithMaxAttempts sets the maximum amount of submissions of a block that the node rejected for a recoverable reason.
+func WithMaxAttempts(maxAttempts int) options.Option[Issuer] {
+	return func(i *Issuer) {
+		i.maxAttempts = maxAttempts
+	}
+}
+
+// WithRetryDelay sets the duration to wait before resubmitting a block if the node was unavailable.
+func WithRetryDelay(retryDelay time.Duration) options.Option[Issuer] {
+	return func(i *Issuer) {
+		i.retryDelay = retryDelay
+	}
+}
+
+// WithTipScorer selects the parents with the given TipScorer instead of using the tips of the node as they are.
+// The issued blocks are recorded at the scorer, so following blocks avoid them as parents.
+func WithTipScorer(tipScorer *TipScorer) options.Option[Issuer] {
+	return func(i *Issuer) {
+		i.tipScorer = tipScorer
+	}
+}
+
+// WithIdempotencyWindow remembers the blocks issued for payloads for the given duration.
+// Issuing the same payload again within the window, e.g. because a client retried its request against the HTTP API,
+// returns the block ID of the original block instead of issuing a duplicate block and doing its proof of work.
+// Concurrent duplicates wait for the original issuance and get the same result. A duration of 0 disables it.
+func WithIdempotencyWindow(window time.Duration) options.Option[Issuer] {
+	return func(i *Issuer) {
+		i.idempotencyWindow = window
+	}
+}
+
+// NewIssuer creates a new Issuer.
+func NewIssuer(nodeBridge *nodebridge.NodeBridge, opts ...options.Option[Issuer]) *Issuer {
+	i := options.Apply(&Issuer{
+		nodeBridge:          nodeBridge,
+		tipsCount:           iotago.BlockMaxParents / 2,
+		powParallelism:      runtime.NumCPU(),
+		refreshTipsInterval: 5 * time.Second,
+		maxAttempts:         3,
+		retryDelay:          time.Second,
+	}, opts)
+
+	if i.idempotencyWindow > 0 {
+		i.recentSubmissions = newRecentSubmissions(i.idempotencyWindow)
+	}
+
+	return i
+}
+
+func (i *Issuer) refreshTips(ctx context.Context) pow.RefreshTipsFunc {
+	return func() (iotago.BlockIDs, error) {
+		if i.tipScorer != nil {
+			return i.tipScorer.SelectParents(ctx, i.tipsCount)
+		}
+
+		return i.nodeBridge.RequestTips(ctx, i.tipsCount, false)
+	}
+}
+
+// doPoW does the proof of work of the block, it selects new tips if the block has no parents.
+func (i *Issuer) doPoW(ctx context.Context, block *iotago.Block) error {
+	targetScore := float64(i.nodeBridge.ProtocolParameters().MinPoWScore)
+	if _, err := pow.DoPoW(ctx, block, targetScore, i.powParallelism, i.refreshTipsInterval, i.refreshTips(ctx)); err != nil {
+		return errors.Wrap(err, "proof of work failed")
+	}
+
+	return nil
+}
+
+// IssuePayload issues a block with the given payload, which can be nil.
+// Rejected blocks are rebuilt, their proof of work is redone, or they are resubmitted, depending on the reason.
+func (i *Issuer) IssuePayload(ctx context.Context, payload iotago.Payload) (iotago.BlockID, error) {
+	block := &iotago.Block{
+		ProtocolVersion: i.nodeBridge.ProtocolParameters().Version,
+		Payload:         payload,
+	}
+
+	return i.IssueBlock(ctx, block)
+}
+
+// IssueBlock issues the given block. If the block has no parents, tips are selected.
+// Rejected blocks are rebuilt, their proof of work is redone, or they are resubmitted, depending on the reason.
+// If WithIdempotencyWindow is set, blocks with a payload that was issued recently are not issued again.
+func (i *Issuer) IssueBlock(ctx context.Context, block *iotago.Block) (iotago.BlockID, error) {
+	if i.recentSubmissions != nil && block.Payload != nil {
+		return i.issueBlockOnce(ctx, block)
+	}
+
+	return i.issueBlock(ctx, block)
+}
+
+func (i *Issuer) issueBlock(ctx context.Context, block *iotago.Block) (iotago.BlockID, error) {
+	if err := i.doPoW(ctx, block); err != nil {
+		return iotago.EmptyBlockID(), err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < i.maxAttempts; attempt++ {
+		blockID, err := i.nodeBridge.SubmitBlock(ctx, block)
+		if err == nil {
+			if i.tipScorer != nil {
+				i.tipScorer.RecordOwnBlock(blockID)
+			}
+
+			return blockID, nil
+		}
+		lastErr = err
+
+		var submissionErr *nodebridge.SubmissionError
+		if !errors.As(err, &submissionErr) {
+			return iotago.EmptyBlockID(), err
+		}
+
+		switch submissionErr.Action() {
+		case nodebridge.SubmissionActionRebuild:
+			block.Parents = nil
+			if err := i.doPoW(ctx, block); err != nil {
+				return iotago.EmptyBlockID(), err
+			}
+
+		case nodebridge.SubmissionActionRedoPoW:
+			if err := i.doPoW(ctx, block); err != nil {
+				return iotago.EmptyBlockID(), err
+			}
+
+		case nodebridge.SubmissionActionRetry:
+			select {
+			case <-ctx.Done():
+				return iotago.EmptyBlockID(), ctx.Err()
+			case <-time.After(i.retryDelay):
+			}
+
+		default:
+			return iotago.EmptyBlockID(), err
+		}
+	}
+
+	return iotago.EmptyBlockID(), errors.WithMessagef(ErrMaxAttemptsReached, "%d attempts, last error: %s", i.maxAttempts, lastErr)
+}
@@ -0,0 +1,229 @@
+package issuer
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+	"github.com/iotaledger/inx-app/pkg/workerpool"
+	inx "github.com/iotaledger/inx/go"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// ErrNoTipsAvailable is returned if the node did not return any usable tips.
+var ErrNoTipsAvailable = errors.New("no tips available")
+
+const (
+	// the weights of the individual criteria of the tip score.
+	tipScoreWeightAge     = 1.0
+	tipScoreWeightParents = 0.5
+	tipScoreWeightPromote = 1.0
+	tipScoreWeightOwn     = 2.0
+
+	// the amount of metadata requests done in parallel.
+	tipMetadataParallelism = 8
+)
+
+// ScoredTip is a candidate tip with its score, higher scores are better.
+type ScoredTip struct {
+	BlockID iotago.BlockID
+	Score   float64
+}
+
+// TipScorer selects the parents of blocks by scoring candidate tips of the node.
+// Tips that were seen for a long time, that the node advises to promote, and blocks issued by the app itself
+// are avoided, tips that reference many parents are preferred. This spreads the blocks of apps that issue
+// many blocks in bursts over the tangle instead of chaining them, which improves their confirmation rate.
+type TipScorer struct {
+	nodeBridge *nodebridge.NodeBridge
+
+	candidatesFactor  int
+	maxTipAge         time.Duration
+	ownBlockRetention time.Duration
+
+	mutex     sync.Mutex
+	firstSeen map[iotago.BlockID]time.Time
+	ownBlocks map[iotago.BlockID]time.Time
+}
+
+// WithCandidatesFactor sets how many more tips than needed are requested from the node as candidates, it defaults to 2.
+func WithCandidatesFactor(candidatesFactor int) options.Option[TipScorer] {
+	return func(s *TipScorer) {
+		s.candidatesFactor = candidatesFactor
+	}
+}
+
+// WithMaxTipAge sets the age of a tip, since it was first seen as a candidate, at which it gets the lowest age score.
+// It defaults to 10 seconds.
+func WithMaxTipAge(maxTipAge time.Duration) options.Option[TipScorer] {
+	return func(s *TipScorer) {
+		s.maxTipAge = maxTipAge
+	}
+}
+
+// WithOwnBlockRetention sets how long issued blocks are remembered to be avoided as parents, it defaults to 1 minute.
+func WithOwnBlockRetention(retention time.Duration) options.Option[TipScorer] {
+	return func(s *TipScorer) {
+		s.ownBlockRetention = retention
+	}
+}
+
+// NewTipScorer creates a new TipScorer.
+func NewTipScorer(nodeBridge *nodebridge.NodeBridge, opts ...options.Option[TipScorer]) *TipScorer {
+	s := options.Apply(&TipScorer{
+		nodeBridge:        nodeBridge,
+		candidatesFactor:  2,
+		maxTipAge:         10 * time.Second,
+		ownBlockRetention: time.Minute,
+		firstSeen:         make(map[iotago.BlockID]time.Time),
+		ownBlocks:         make(map[iotago.BlockID]time.Time),
+	}, opts)
+
+	if s.candidatesFactor < 1 {
+		s.candidatesFactor = 1
+	}
+
+	return s
+}
+
+// RecordOwnBlock remembers a block issued by the app, so it is avoided as parent of the following blocks.
+func (s *TipScorer) RecordOwnBlock(blockID iotago.BlockID) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.ownBlocks[blockID] = time.Now()
+}
+
+// prune removes the remembered tips and blocks that are no longer relevant.
+func (s *TipScorer) prune(now time.Time) {
+	for blockID, firstSeen := range s.firstSeen {
+		if now.Sub(firstSeen) > 2*s.maxTipAge {
+			delete(s.firstSeen, blockID)
+		}
+	}
+
+	for blockID, issued := range s.ownBlocks {
+		if now.Sub(issued) > s.ownBlockRetention {
+			delete(s.ownBlocks, blockID)
+		}
+	}
+}
+
+// score scores the tip, it returns false if the tip must not be used as parent.
+func (s *TipScorer) score(now time.Time, metadata *inx.BlockMetadata) (float64, bool) {
+	if !metadata.GetSolid() || metadata.GetShouldReattach() || metadata.GetReferencedByMilestoneIndex() != 0 {
+		return 0, false
+	}
+
+	blockID := metadata.UnwrapBlockID()
+
+	s.mutex.Lock()
+	firstSeen, seen := s.firstSeen[blockID]
+	if !seen {
+		firstSeen = now
+		s.firstSeen[blockID] = now
+	}
+	_, own := s.ownBlocks[blockID]
+	s.mutex.Unlock()
+
+	var score float64
+
+	ageRatio := 1.0
+	if s.maxTipAge > 0 {
+		ageRatio = float64(now.Sub(firstSeen)) / float64(s.maxTipAge)
+	}
+	if ageRatio > 1 {
+		ageRatio = 1
+	}
+	score += tipScoreWeightAge * (1 - ageRatio)
+
+	// tips that reference more parents indicate a wider cone
+	score += tipScoreWeightParents * float64(len(metadata.GetParents())) / float64(iotago.BlockMaxParents)
+
+	if metadata.GetShouldPromote() {
+		score -= tipScoreWeightPromote
+	}
+
+	if own {
+		score -= tipScoreWeightOwn
+	}
+
+	return score, true
+}
+
+// ScoreTips requests candidate tips from the node and returns the usable ones ordered by their score, best first.
+func (s *TipScorer) ScoreTips(ctx context.Context, count uint32) ([]*ScoredTip, error) {
+	candidates, err := s.nodeBridge.RequestTips(ctx, count*uint32(s.candidatesFactor), false)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	s.mutex.Lock()
+	s.prune(now)
+	s.mutex.Unlock()
+
+	var scoredTipsMutex sync.Mutex
+	scoredTips := make([]*ScoredTip, 0, len(candidates))
+
+	group := workerpool.NewGroup(tipMetadataParallelism)
+	for _, candidate := range candidates {
+		blockID := candidate
+		if err := group.Go(ctx, func() {
+			metadata, err := s.nodeBridge.BlockMetadata(ctx, blockID)
+			if err != nil {
+				// the tip might have been evicted in the meantime
+				return
+			}
+
+			score, usable := s.score(now, metadata)
+			if !usable {
+				return
+			}
+
+			scoredTipsMutex.Lock()
+			scoredTips = append(scoredTips, &ScoredTip{BlockID: blockID, Score: score})
+			scoredTipsMutex.Unlock()
+		}); err != nil {
+			group.Wait()
+
+			return nil, err
+		}
+	}
+	group.Wait()
+
+	sort.SliceStable(scoredTips, func(i int, j int) bool {
+		return scoredTips[i].Score > scoredTips[j].Score
+	})
+
+	return scoredTips, nil
+}
+
+// SelectParents selects the best scored tips as parents of a block.
+// Fewer parents are returned if the node has fewer usable tips.
+func (s *TipScorer) SelectParents(ctx context.Context, count uint32) (iotago.BlockIDs, error) {
+	scoredTips, err := s.ScoreTips(ctx, count)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(scoredTips) == 0 {
+		return nil, ErrNoTipsAvailable
+	}
+
+	if uint32(len(scoredTips)) > count {
+		scoredTips = scoredTips[:count]
+	}
+
+	parents := make(iotago.BlockIDs, 0, len(scoredTips))
+	for _, scoredTip := range scoredTips {
+		parents = append(parents, scoredTip.BlockID)
+	}
+
+	return parents.RemoveDupsAndSort(), nil
+}
@@ -0,0 +1,555 @@
+package jobqueue
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/core/kvstore"
+	"github.com/iotaledger/hive.go/core/logger"
+)
+
+const (
+	storePrefixSequence   byte = 0
+	storePrefixPending    byte = 1
+	storePrefixDeadLetter byte = 2
+
+	sequenceInterval = 100
+)
+
+var (
+	// ErrNoHandler is returned when a job is enqueued for a job type without a registered handler.
+	ErrNoHandler = errors.New("no handler registered for job type")
+	// ErrJobNotFound is returned when a job does not exist.
+	ErrJobNotFound = errors.New("job not found")
+)
+
+// Job is a unit of deferred work.
+type Job struct {
+	// ID is the unique, monotonically increasing identifier of the job.
+	ID uint64 `json:"id"`
+	// Type is used to select the handler for the job.
+	Type string `json:"type"`
+	// Payload is the handler specific data of the job.
+	Payload []byte `json:"payload"`
+	// Attempts is the amount of times the job was processed unsuccessfully.
+	Attempts uint32 `json:"attempts"`
+	// LastError is the error of the last unsuccessful attempt.
+	LastError string `json:"lastError,omitempty"`
+	// NotBefore is the earliest time the job is processed again.
+	NotBefore time.Time `json:"notBefore"`
+	// CreatedAt is the time the job was enqueued.
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Handler processes a job.
+// If the handler returns an error, the job is retried until the maximum amount of attempts is reached.
+type Handler func(ctx context.Context, job *Job) error
+
+// Queue is a durable job queue backed by a kvstore.
+// Jobs are only removed from the store after they were processed successfully,
+// so they survive restarts and are processed at least once.
+// Jobs that fail too often are moved to the dead letters.
+type Queue struct {
+	// the logger used to log events.
+	*logger.WrappedLogger
+
+	store    kvstore.KVStore
+	sequence *kvstore.Sequence
+
+	maxAttempts     uint32
+	retryBackoff    time.Duration
+	maxRetryBackoff time.Duration
+	workerCount     int
+	pollInterval    time.Duration
+	drainTimeout    time.Duration
+
+	handlersMutex sync.RWMutex
+	handlers      map[string]Handler
+
+	inFlightMutex sync.Mutex
+	inFlight      map[uint64]struct{}
+
+	wakeup chan struct{}
+}
+
+// WithMaxAttempts sets the amount of attempts before a job is moved to the dead letters.
+func WithMaxAttempts(maxAttempts uint32) options.Option[Queue] {
+	return func(q *Queue) {
+		q.maxAttempts = maxAttempts
+	}
+}
+
+// WithRetryBackoff sets the delay before the first retry of a failed job.
+// The delay is doubled with every further attempt, up to the maximum retry backoff.
+func WithRetryBackoff(retryBackoff time.Duration) options.Option[Queue] {
+	return func(q *Queue) {
+		q.retryBackoff = retryBackoff
+	}
+}
+
+// WithMaxRetryBackoff sets the maximum delay before a retry of a failed job.
+func WithMaxRetryBackoff(maxRetryBackoff time.Duration) options.Option[Queue] {
+	return func(q *Queue) {
+		q.maxRetryBackoff = maxRetryBackoff
+	}
+}
+
+// WithWorkerCount sets the amount of jobs that are processed in parallel.
+func WithWorkerCount(workerCount int) options.Option[Queue] {
+	return func(q *Queue) {
+		q.workerCount = workerCount
+	}
+}
+
+// WithPollInterval sets the interval in which the store is checked for jobs that are due for a retry.
+func WithPollInterval(pollInterval time.Duration) options.Option[Queue] {
+	return func(q *Queue) {
+		q.pollInterval = pollInterval
+	}
+}
+
+// WithDrainTimeout sets how long the jobs that are due are still processed after the shutdown was initiated.
+// Jobs that were not processed until then stay persisted and are processed after the restart.
+// By default processing stops immediately and in-flight jobs are interrupted.
+func WithDrainTimeout(drainTimeout time.Duration) options.Option[Queue] {
+	return func(q *Queue) {
+		q.drainTimeout = drainTimeout
+	}
+}
+
+// NewQueue creates a new Queue that persists its jobs in the given store.
+func NewQueue(store kvstore.KVStore, log *logger.Logger, opts ...options.Option[Queue]) (*Queue, error) {
+	sequence, err := kvstore.NewSequence(store, []byte{storePrefixSequence}, sequenceInterval)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create job sequence: %w", err)
+	}
+
+	return options.Apply(&Queue{
+		WrappedLogger:   logger.NewWrappedLogger(log),
+		store:           store,
+		sequence:        sequence,
+		maxAttempts:     5,
+		retryBackoff:    time.Second,
+		maxRetryBackoff: time.Hour,
+		workerCount:     1,
+		pollInterval:    time.Second,
+		handlers:        make(map[string]Handler),
+		inFlight:        make(map[uint64]struct{}),
+		wakeup:          make(chan struct{}, 1),
+	}, opts), nil
+}
+
+// RegisterHandler registers the handler for the given job type.
+func (q *Queue) RegisterHandler(jobType string, handler Handler) {
+	q.handlersMutex.Lock()
+	defer q.handlersMutex.Unlock()
+
+	q.handlers[jobType] = handler
+}
+
+func (q *Queue) handler(jobType string) (Handler, bool) {
+	q.handlersMutex.RLock()
+	defer q.handlersMutex.RUnlock()
+
+	handler, exists := q.handlers[jobType]
+
+	return handler, exists
+}
+
+// Enqueue persists a new job and returns its ID.
+func (q *Queue) Enqueue(jobType string, payload []byte) (uint64, error) {
+	if _, exists := q.handler(jobType); !exists {
+		return 0, fmt.Errorf("%w: %s", ErrNoHandler, jobType)
+	}
+
+	id, err := q.sequence.Next()
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	job := &Job{
+		ID:        id,
+		Type:      jobType,
+		Payload:   payload,
+		NotBefore: now,
+		CreatedAt: now,
+	}
+
+	if err := q.storeJob(storePrefixPending, job); err != nil {
+		return 0, err
+	}
+
+	select {
+	case q.wakeup <- struct{}{}:
+	default:
+	}
+
+	return id, nil
+}
+
+// Run processes the jobs until the given context is done.
+// If a drain timeout is set, the jobs that are due are still processed on shutdown until the timeout is reached.
+// The sequence is released on shutdown, so it has to be called only once.
+func (q *Queue) Run(ctx context.Context) {
+	jobs := make(chan *Job)
+
+	// the jobs are processed with their own context, so they are not interrupted while draining
+	processCtx, cancelProcess := context.WithCancel(context.Background())
+	defer cancelProcess()
+
+	var wg sync.WaitGroup
+	for i := 0; i < q.workerCount; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				q.process(processCtx, job)
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(q.pollInterval)
+	defer ticker.Stop()
+
+	defer func() {
+		q.drain(jobs)
+		cancelProcess()
+
+		close(jobs)
+		wg.Wait()
+
+		if err := q.sequence.Release(); err != nil {
+			q.LogErrorf("unable to release job sequence: %s", err)
+		}
+	}()
+
+	for {
+		if !q.dispatch(ctx, jobs) {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-q.wakeup:
+		}
+	}
+}
+
+// dispatch passes the due jobs to the workers, it returns false if the context is done.
+func (q *Queue) dispatch(ctx context.Context, jobs chan<- *Job) bool {
+	dueJobs, err := q.dueJobs()
+	if err != nil {
+		q.LogErrorf("unable to load jobs: %s", err)
+	}
+
+	for i, job := range dueJobs {
+		select {
+		case jobs <- job:
+		case <-ctx.Done():
+			q.releaseJobs(dueJobs[i:])
+
+			return false
+		}
+	}
+
+	return true
+}
+
+// releaseJobs removes the jobs that were not passed to a worker from the in-flight jobs.
+func (q *Queue) releaseJobs(jobs []*Job) {
+	q.inFlightMutex.Lock()
+	defer q.inFlightMutex.Unlock()
+
+	for _, job := range jobs {
+		delete(q.inFlight, job.ID)
+	}
+}
+
+// inFlightCount returns the amount of jobs that are processed at the moment.
+func (q *Queue) inFlightCount() int {
+	q.inFlightMutex.Lock()
+	defer q.inFlightMutex.Unlock()
+
+	return len(q.inFlight)
+}
+
+// drain processes the jobs that are due until none are left or the drain timeout is reached.
+func (q *Queue) drain(jobs chan<- *Job) {
+	if q.drainTimeout <= 0 {
+		return
+	}
+
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), q.drainTimeout)
+	defer cancelDrain()
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for drainCtx.Err() == nil {
+		if !q.dispatch(drainCtx, jobs) {
+			break
+		}
+
+		if q.inFlightCount() == 0 {
+			// failed jobs are due later, so nothing is left to do
+			dueJobs, err := q.dueJobs()
+			if err != nil || len(dueJobs) == 0 {
+				q.releaseJobs(dueJobs)
+
+				break
+			}
+			q.releaseJobs(dueJobs)
+		}
+
+		select {
+		case <-drainCtx.Done():
+		case <-ticker.C:
+		}
+	}
+
+	if pending, err := q.pendingCount(); err == nil && pending > 0 {
+		q.LogInfof("%d pending jobs are kept for processing after the restart", pending)
+	}
+}
+
+// pendingCount returns the amount of pending jobs in the store.
+func (q *Queue) pendingCount() (int, error) {
+	count := 0
+	if err := q.store.IterateKeys([]byte{storePrefixPending}, func(_ kvstore.Key) bool {
+		count++
+
+		return true
+	}); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// dueJobs returns all pending jobs that are not in flight and whose retry time has passed, ordered by ID.
+// The returned jobs are marked as in flight.
+func (q *Queue) dueJobs() ([]*Job, error) {
+	q.inFlightMutex.Lock()
+	defer q.inFlightMutex.Unlock()
+
+	now := time.Now()
+
+	var innerErr error
+	dueJobs := make([]*Job, 0)
+	if err := q.store.Iterate([]byte{storePrefixPending}, func(_ kvstore.Key, value kvstore.Value) bool {
+		job := &Job{}
+		if err := json.Unmarshal(value, job); err != nil {
+			innerErr = err
+
+			return false
+		}
+
+		if _, inFlight := q.inFlight[job.ID]; inFlight || job.NotBefore.After(now) {
+			return true
+		}
+
+		dueJobs = append(dueJobs, job)
+
+		return true
+	}); err != nil {
+		return nil, err
+	}
+	if innerErr != nil {
+		return nil, innerErr
+	}
+
+	sort.Slice(dueJobs, func(i int, j int) bool {
+		return dueJobs[i].ID < dueJobs[j].ID
+	})
+
+	for _, job := range dueJobs {
+		q.inFlight[job.ID] = struct{}{}
+	}
+
+	return dueJobs, nil
+}
+
+func (q *Queue) process(ctx context.Context, job *Job) {
+	defer func() {
+		q.inFlightMutex.Lock()
+		defer q.inFlightMutex.Unlock()
+		delete(q.inFlight, job.ID)
+	}()
+
+	handler, exists := q.handler(job.Type)
+	if !exists {
+		q.fail(job, fmt.Errorf("%w: %s", ErrNoHandler, job.Type))
+
+		return
+	}
+
+	if err := handler(ctx, job); err != nil {
+		if ctx.Err() != nil {
+			// the job was interrupted by the shutdown, it will be processed again after the restart
+			return
+		}
+		q.fail(job, err)
+
+		return
+	}
+
+	if err := q.store.Delete(jobKey(storePrefixPending, job.ID)); err != nil {
+		q.LogErrorf("unable to delete job %d: %s", job.ID, err)
+	}
+}
+
+// retryDelay returns the delay before the retry after the given amount of failed attempts.
+// The delay is doubled step by step instead of shifted at once, so it is capped before it can overflow.
+func (q *Queue) retryDelay(attempts uint32) time.Duration {
+	delay := q.retryBackoff
+	for i := uint32(1); i < attempts; i++ {
+		if delay > q.maxRetryBackoff/2 {
+			return q.maxRetryBackoff
+		}
+		delay *= 2
+	}
+
+	if delay > q.maxRetryBackoff {
+		return q.maxRetryBackoff
+	}
+
+	return delay
+}
+
+func (q *Queue) fail(job *Job, jobErr error) {
+	job.Attempts++
+	job.LastError = jobErr.Error()
+
+	if job.Attempts < q.maxAttempts {
+		job.NotBefore = time.Now().Add(q.retryDelay(job.Attempts))
+		q.LogDebugf("job %d (%s) failed, attempt %d/%d: %s", job.ID, job.Type, job.Attempts, q.maxAttempts, jobErr)
+
+		if err := q.storeJob(storePrefixPending, job); err != nil {
+			q.LogErrorf("unable to store job %d: %s", job.ID, err)
+		}
+
+		return
+	}
+
+	q.LogWarnf("job %d (%s) moved to dead letters after %d attempts: %s", job.ID, job.Type, job.Attempts, jobErr)
+
+	if err := q.moveJob(job, storePrefixPending, storePrefixDeadLetter); err != nil {
+		q.LogErrorf("unable to move job %d to dead letters: %s", job.ID, err)
+	}
+}
+
+// DeadLetters returns all jobs that exceeded the maximum amount of attempts, ordered by ID.
+func (q *Queue) DeadLetters() ([]*Job, error) {
+	var innerErr error
+	jobs := make([]*Job, 0)
+	if err := q.store.Iterate([]byte{storePrefixDeadLetter}, func(_ kvstore.Key, value kvstore.Value) bool {
+		job := &Job{}
+		if err := json.Unmarshal(value, job); err != nil {
+			innerErr = err
+
+			return false
+		}
+		jobs = append(jobs, job)
+
+		return true
+	}); err != nil {
+		return nil, err
+	}
+	if innerErr != nil {
+		return nil, innerErr
+	}
+
+	sort.Slice(jobs, func(i int, j int) bool {
+		return jobs[i].ID < jobs[j].ID
+	})
+
+	return jobs, nil
+}
+
+// RequeueDeadLetter moves the dead letter with the given ID back to the pending jobs and resets its attempts.
+func (q *Queue) RequeueDeadLetter(id uint64) error {
+	value, err := q.store.Get(jobKey(storePrefixDeadLetter, id))
+	if err != nil {
+		if errors.Is(err, kvstore.ErrKeyNotFound) {
+			return fmt.Errorf("%w: %d", ErrJobNotFound, id)
+		}
+
+		return err
+	}
+
+	job := &Job{}
+	if err := json.Unmarshal(value, job); err != nil {
+		return err
+	}
+	job.Attempts = 0
+	job.NotBefore = time.Now()
+
+	if err := q.moveJob(job, storePrefixDeadLetter, storePrefixPending); err != nil {
+		return err
+	}
+
+	select {
+	case q.wakeup <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// DeleteDeadLetter removes the dead letter with the given ID.
+func (q *Queue) DeleteDeadLetter(id uint64) error {
+	return q.store.Delete(jobKey(storePrefixDeadLetter, id))
+}
+
+func (q *Queue) storeJob(prefix byte, job *Job) error {
+	value, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	return q.store.Set(jobKey(prefix, job.ID), value)
+}
+
+func (q *Queue) moveJob(job *Job, fromPrefix byte, toPrefix byte) error {
+	value, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	batch, err := q.store.Batched()
+	if err != nil {
+		return err
+	}
+
+	if err := batch.Set(jobKey(toPrefix, job.ID), value); err != nil {
+		batch.Cancel()
+
+		return err
+	}
+
+	if err := batch.Delete(jobKey(fromPrefix, job.ID)); err != nil {
+		batch.Cancel()
+
+		return err
+	}
+
+	return batch.Commit()
+}
+
+func jobKey(prefix byte, id uint64) []byte {
+	key := make([]byte, 9)
+	key[0] = prefix
+	binary.BigEndian.PutUint64(key[1:], id)
+
+	return key
+}
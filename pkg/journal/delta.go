@@ -0,0 +1,204 @@
+package journal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/iotaledger/hive.go/serializer/v2"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+	inx "github.com/iotaledger/inx/go"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// AddressDelta is the change of the state of an address between two ledger indexes.
+type AddressDelta struct {
+	// Address is the bech32 encoded address.
+	Address string `json:"address"`
+	// BalanceChange is the change of the base token balance owned by the address.
+	BalanceChange int64 `json:"balanceChange"`
+	// CreatedOutputs are the hex encoded IDs of the outputs that were created for the address and are still unspent.
+	CreatedOutputs []string `json:"createdOutputs"`
+	// SpentOutputs are the hex encoded IDs of the outputs of the address that existed before and were spent.
+	SpentOutputs []string `json:"spentOutputs"`
+}
+
+// Delta is the change of the ledger state between two ledger indexes, derived from the journaled ledger updates.
+// Outputs that were created and spent within the range cancel out, so applying the delta to the state
+// at FromIndex results in the state at ToIndex.
+type Delta struct {
+	// FromIndex is the ledger index the delta starts at, its own changes are not included.
+	FromIndex uint32 `json:"fromIndex"`
+	// ToIndex is the ledger index the delta ends at, its changes are included.
+	ToIndex uint32 `json:"toIndex"`
+	// CreatedOutputs are the hex encoded IDs of the outputs that were created and are still unspent at ToIndex.
+	CreatedOutputs []string `json:"createdOutputs"`
+	// SpentOutputs are the hex encoded IDs of the outputs that existed at FromIndex and were spent until ToIndex.
+	SpentOutputs []string `json:"spentOutputs"`
+	// Addresses are the changes per address, ordered by address.
+	Addresses []*AddressDelta `json:"addresses"`
+}
+
+type deltaBuilder struct {
+	hrp       iotago.NetworkPrefix
+	created   map[iotago.OutputID]struct{}
+	spent     map[iotago.OutputID]struct{}
+	addresses map[string]*addressDeltaBuilder
+}
+
+type addressDeltaBuilder struct {
+	address       iotago.Address
+	balanceChange int64
+	created       map[iotago.OutputID]struct{}
+	spent         map[iotago.OutputID]struct{}
+}
+
+func (b *deltaBuilder) address(address iotago.Address) *addressDeltaBuilder {
+	builder, exists := b.addresses[address.Key()]
+	if !exists {
+		builder = &addressDeltaBuilder{
+			address: address,
+			created: make(map[iotago.OutputID]struct{}),
+			spent:   make(map[iotago.OutputID]struct{}),
+		}
+		b.addresses[address.Key()] = builder
+	}
+
+	return builder
+}
+
+func (b *deltaBuilder) apply(update *nodebridge.LedgerUpdate) error {
+	// created outputs are applied first, because outputs can be created and spent in the same milestone
+	for _, created := range update.Created {
+		if err := b.applyOutput(created, true); err != nil {
+			return fmt.Errorf("milestone %d: processing created output failed: %w", update.MilestoneIndex, err)
+		}
+	}
+
+	for _, spent := range update.Consumed {
+		if err := b.applyOutput(spent.GetOutput(), false); err != nil {
+			return fmt.Errorf("milestone %d: processing consumed output failed: %w", update.MilestoneIndex, err)
+		}
+	}
+
+	return nil
+}
+
+func (b *deltaBuilder) applyOutput(ledgerOutput *inx.LedgerOutput, created bool) error {
+	output, err := ledgerOutput.UnwrapOutput(serializer.DeSeriModeNoValidation, nil)
+	if err != nil {
+		return err
+	}
+	outputID := ledgerOutput.UnwrapOutputID()
+
+	var addressDelta *addressDeltaBuilder
+	if owner, ok := nodebridge.OutputOwner(output); ok {
+		addressDelta = b.address(owner)
+	}
+
+	if created {
+		b.created[outputID] = struct{}{}
+		if addressDelta != nil {
+			addressDelta.balanceChange += int64(output.Deposit())
+			addressDelta.created[outputID] = struct{}{}
+		}
+
+		return nil
+	}
+
+	_, createdInRange := b.created[outputID]
+	if createdInRange {
+		delete(b.created, outputID)
+	} else {
+		b.spent[outputID] = struct{}{}
+	}
+
+	if addressDelta != nil {
+		addressDelta.balanceChange -= int64(output.Deposit())
+		if createdInRange {
+			delete(addressDelta.created, outputID)
+		} else {
+			addressDelta.spent[outputID] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+func sortedOutputIDs(outputIDs map[iotago.OutputID]struct{}) []string {
+	result := make([]string, 0, len(outputIDs))
+	for outputID := range outputIDs {
+		result = append(result, outputID.ToHex())
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+func (b *deltaBuilder) build(fromIndex uint32, toIndex uint32) *Delta {
+	delta := &Delta{
+		FromIndex:      fromIndex,
+		ToIndex:        toIndex,
+		CreatedOutputs: sortedOutputIDs(b.created),
+		SpentOutputs:   sortedOutputIDs(b.spent),
+		Addresses:      make([]*AddressDelta, 0, len(b.addresses)),
+	}
+
+	for _, addressDelta := range b.addresses {
+		if addressDelta.balanceChange == 0 && len(addressDelta.created) == 0 && len(addressDelta.spent) == 0 {
+			// everything the address received in the range was spent again
+			continue
+		}
+
+		delta.Addresses = append(delta.Addresses, &AddressDelta{
+			Address:        addressDelta.address.Bech32(b.hrp),
+			BalanceChange:  addressDelta.balanceChange,
+			CreatedOutputs: sortedOutputIDs(addressDelta.created),
+			SpentOutputs:   sortedOutputIDs(addressDelta.spent),
+		})
+	}
+
+	sort.Slice(delta.Addresses, func(i int, j int) bool {
+		return delta.Addresses[i].Address < delta.Addresses[j].Address
+	})
+
+	return delta
+}
+
+// Delta computes the change of the ledger state between the two ledger indexes from the journaled ledger updates,
+// e.g. for settlement and reconciliation reports. The changes of fromIndex are not included, those of toIndex are.
+// The journal must contain all milestones after fromIndex up to toIndex.
+func (j *Journal) Delta(ctx context.Context, hrp iotago.NetworkPrefix, fromIndex uint32, toIndex uint32) (*Delta, error) {
+	if fromIndex >= toIndex {
+		return nil, fmt.Errorf("invalid range: from index %d must be lower than to index %d", fromIndex, toIndex)
+	}
+
+	firstIndex, lastIndex := j.Range()
+	if lastIndex == 0 || fromIndex+1 < firstIndex || toIndex > lastIndex {
+		return nil, fmt.Errorf("%w: requested range %d-%d, journaled range %d-%d", ErrEntryNotFound, fromIndex+1, toIndex, firstIndex, lastIndex)
+	}
+
+	builder := &deltaBuilder{
+		hrp:       hrp,
+		created:   make(map[iotago.OutputID]struct{}),
+		spent:     make(map[iotago.OutputID]struct{}),
+		addresses: make(map[string]*addressDeltaBuilder),
+	}
+
+	for index := fromIndex + 1; index <= toIndex; index++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		update, err := j.Read(index)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := builder.apply(update); err != nil {
+			return nil, err
+		}
+	}
+
+	return builder.build(fromIndex, toIndex), nil
+}
@@ -0,0 +1,349 @@
+package journal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/iotaledger/hive.go/core/kvstore"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+	inx "github.com/iotaledger/inx/go"
+)
+
+const (
+	storePrefixEntry byte = 0
+	storePrefixRange byte = 1
+
+	entryVersion byte = 1
+)
+
+var (
+	// ErrEntryOutOfOrder is returned when a ledger update would leave a gap after the last journaled milestone.
+	ErrEntryOutOfOrder = errors.New("journal entry out of order")
+	// ErrEntryMismatch is returned when a ledger update of an already journaled milestone differs from the journaled one.
+	ErrEntryMismatch = errors.New("journal entry mismatch")
+	// ErrEntryNotFound is returned when the journal does not contain the requested milestone.
+	ErrEntryNotFound = errors.New("journal entry not found")
+	// ErrInvalidEntry is returned when a journal entry is malformed.
+	ErrInvalidEntry = errors.New("invalid journal entry")
+)
+
+// Journal is a write-ahead journal of ledger updates.
+// Every ledger update is persisted before it is applied to the app state,
+// so the state can be rebuilt from the journal alone, without access to a node.
+type Journal struct {
+	store kvstore.KVStore
+
+	rangeMutex sync.RWMutex
+	firstIndex uint32
+	lastIndex  uint32
+}
+
+// New creates a new Journal that persists its entries in the given store.
+func New(store kvstore.KVStore) (*Journal, error) {
+	j := &Journal{
+		store: store,
+	}
+
+	value, err := store.Get([]byte{storePrefixRange})
+	if err != nil {
+		if errors.Is(err, kvstore.ErrKeyNotFound) {
+			return j, nil
+		}
+
+		return nil, err
+	}
+
+	if len(value) != 8 {
+		return nil, fmt.Errorf("%w: invalid range length: %d", ErrInvalidEntry, len(value))
+	}
+	j.firstIndex = binary.LittleEndian.Uint32(value[:4])
+	j.lastIndex = binary.LittleEndian.Uint32(value[4:])
+
+	return j, nil
+}
+
+func entryKey(index uint32) kvstore.Key {
+	// big endian keeps the entries sorted by milestone index in ordered stores
+	key := make([]byte, 5)
+	key[0] = storePrefixEntry
+	binary.BigEndian.PutUint32(key[1:], index)
+
+	return key
+}
+
+func rangeValue(firstIndex uint32, lastIndex uint32) kvstore.Value {
+	value := make([]byte, 8)
+	binary.LittleEndian.PutUint32(value[:4], firstIndex)
+	binary.LittleEndian.PutUint32(value[4:], lastIndex)
+
+	return value
+}
+
+// Range returns the first and the last milestone index in the journal.
+// Both are 0 if the journal is empty.
+func (j *Journal) Range() (uint32, uint32) {
+	j.rangeMutex.RLock()
+	defer j.rangeMutex.RUnlock()
+
+	return j.firstIndex, j.lastIndex
+}
+
+// Append persists the ledger update.
+// The milestone index must directly follow the last journaled milestone, unless the journal is empty.
+// Already journaled milestones are ignored, they are delivered again after a restart or a stream resume.
+func (j *Journal) Append(update *nodebridge.LedgerUpdate) error {
+	value, err := marshalLedgerUpdate(update)
+	if err != nil {
+		return err
+	}
+
+	j.rangeMutex.Lock()
+	defer j.rangeMutex.Unlock()
+
+	firstIndex := j.firstIndex
+	switch {
+	case j.lastIndex == 0:
+		firstIndex = update.MilestoneIndex
+	case update.MilestoneIndex <= j.lastIndex:
+		return j.checkJournaledWithoutLocking(update.MilestoneIndex, value)
+	case update.MilestoneIndex != j.lastIndex+1:
+		return fmt.Errorf("%w: last index %d, milestone index %d", ErrEntryOutOfOrder, j.lastIndex, update.MilestoneIndex)
+	}
+
+	batch, err := j.store.Batched()
+	if err != nil {
+		return err
+	}
+
+	if err := batch.Set(entryKey(update.MilestoneIndex), value); err != nil {
+		batch.Cancel()
+
+		return err
+	}
+
+	if err := batch.Set([]byte{storePrefixRange}, rangeValue(firstIndex, update.MilestoneIndex)); err != nil {
+		batch.Cancel()
+
+		return err
+	}
+
+	if err := batch.Commit(); err != nil {
+		return err
+	}
+
+	j.firstIndex = firstIndex
+	j.lastIndex = update.MilestoneIndex
+
+	return nil
+}
+
+// checkJournaledWithoutLocking checks that the entry of an already journaled milestone equals the given value.
+// Pruned entries can't be checked anymore and are accepted.
+func (j *Journal) checkJournaledWithoutLocking(index uint32, value []byte) error {
+	if index < j.firstIndex {
+		return nil
+	}
+
+	journaled, err := j.store.Get(entryKey(index))
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(journaled, value) {
+		return fmt.Errorf("%w: milestone %d", ErrEntryMismatch, index)
+	}
+
+	return nil
+}
+
+// Consumer wraps the given ledger update consumer, so that every update is journaled before it is consumed.
+// Updates of already journaled milestones are consumed again, the app may have stopped before applying them.
+// It can be passed to NodeBridge.ListenToLedgerUpdates.
+func (j *Journal) Consumer(consume func(update *nodebridge.LedgerUpdate) error) func(update *nodebridge.LedgerUpdate) error {
+	return func(update *nodebridge.LedgerUpdate) error {
+		if err := j.Append(update); err != nil {
+			return fmt.Errorf("journaling milestone %d failed: %w", update.MilestoneIndex, err)
+		}
+
+		return consume(update)
+	}
+}
+
+// Read returns the journaled ledger update of the milestone with the given index.
+func (j *Journal) Read(index uint32) (*nodebridge.LedgerUpdate, error) {
+	value, err := j.store.Get(entryKey(index))
+	if err != nil {
+		if errors.Is(err, kvstore.ErrKeyNotFound) {
+			return nil, fmt.Errorf("%w: %d", ErrEntryNotFound, index)
+		}
+
+		return nil, err
+	}
+
+	return unmarshalLedgerUpdate(index, value)
+}
+
+// PruneUntil deletes all entries up to and including the milestone with the given index.
+func (j *Journal) PruneUntil(index uint32) error {
+	j.rangeMutex.Lock()
+	defer j.rangeMutex.Unlock()
+
+	if j.lastIndex == 0 || index < j.firstIndex {
+		return nil
+	}
+
+	batch, err := j.store.Batched()
+	if err != nil {
+		return err
+	}
+
+	pruneUntil := index
+	if pruneUntil > j.lastIndex {
+		pruneUntil = j.lastIndex
+	}
+
+	for i := j.firstIndex; i <= pruneUntil; i++ {
+		if err := batch.Delete(entryKey(i)); err != nil {
+			batch.Cancel()
+
+			return err
+		}
+	}
+
+	firstIndex, lastIndex := pruneUntil+1, j.lastIndex
+	if pruneUntil == j.lastIndex {
+		firstIndex, lastIndex = 0, 0
+		if err := batch.Delete([]byte{storePrefixRange}); err != nil {
+			batch.Cancel()
+
+			return err
+		}
+	} else if err := batch.Set([]byte{storePrefixRange}, rangeValue(firstIndex, lastIndex)); err != nil {
+		batch.Cancel()
+
+		return err
+	}
+
+	if err := batch.Commit(); err != nil {
+		return err
+	}
+
+	j.firstIndex = firstIndex
+	j.lastIndex = lastIndex
+
+	return nil
+}
+
+func writeMessage(buf *bytes.Buffer, message proto.Message) error {
+	// the encoding has to be stable, entries of milestones delivered again are compared byte by byte
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	lengthBuf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(lengthBuf, uint64(len(data)))
+	buf.Write(lengthBuf[:n])
+	buf.Write(data)
+
+	return nil
+}
+
+func readMessage(r *bytes.Reader, message proto.Message) error {
+	length, err := binary.ReadUvarint(r)
+	if err != nil {
+		return err
+	}
+	if length > uint64(r.Len()) {
+		return io.ErrUnexpectedEOF
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return err
+	}
+
+	return proto.Unmarshal(data, message)
+}
+
+func marshalLedgerUpdate(update *nodebridge.LedgerUpdate) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte(entryVersion)
+
+	countBuf := make([]byte, 8)
+	binary.LittleEndian.PutUint32(countBuf[:4], uint32(len(update.Consumed)))
+	binary.LittleEndian.PutUint32(countBuf[4:], uint32(len(update.Created)))
+	buf.Write(countBuf)
+
+	for _, spent := range update.Consumed {
+		if err := writeMessage(buf, spent); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, output := range update.Created {
+		if err := writeMessage(buf, output); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func unmarshalLedgerUpdate(index uint32, value []byte) (*nodebridge.LedgerUpdate, error) {
+	r := bytes.NewReader(value)
+
+	version, err := r.ReadByte()
+	if err != nil {
+		return nil, fmt.Errorf("%w: milestone %d: %s", ErrInvalidEntry, index, err.Error())
+	}
+	if version != entryVersion {
+		return nil, fmt.Errorf("%w: milestone %d: unsupported version %d", ErrInvalidEntry, index, version)
+	}
+
+	countBuf := make([]byte, 8)
+	if _, err := io.ReadFull(r, countBuf); err != nil {
+		return nil, fmt.Errorf("%w: milestone %d: %s", ErrInvalidEntry, index, err.Error())
+	}
+	consumedCount := binary.LittleEndian.Uint32(countBuf[:4])
+	createdCount := binary.LittleEndian.Uint32(countBuf[4:])
+
+	// every message has at least a length prefix, so bigger counts are malformed
+	if uint64(consumedCount)+uint64(createdCount) > uint64(r.Len()) {
+		return nil, fmt.Errorf("%w: milestone %d: invalid counts", ErrInvalidEntry, index)
+	}
+
+	update := &nodebridge.LedgerUpdate{
+		MilestoneIndex: index,
+		Consumed:       make([]*inx.LedgerSpent, 0, consumedCount),
+		Created:        make([]*inx.LedgerOutput, 0, createdCount),
+	}
+
+	for i := uint32(0); i < consumedCount; i++ {
+		spent := &inx.LedgerSpent{}
+		if err := readMessage(r, spent); err != nil {
+			return nil, fmt.Errorf("%w: milestone %d: consumed output %d: %s", ErrInvalidEntry, index, i, err.Error())
+		}
+		update.Consumed = append(update.Consumed, spent)
+	}
+
+	for i := uint32(0); i < createdCount; i++ {
+		output := &inx.LedgerOutput{}
+		if err := readMessage(r, output); err != nil {
+			return nil, fmt.Errorf("%w: milestone %d: created output %d: %s", ErrInvalidEntry, index, i, err.Error())
+		}
+		update.Created = append(update.Created, output)
+	}
+
+	if r.Len() > 0 {
+		return nil, fmt.Errorf("%w: milestone %d: %d trailing bytes", ErrInvalidEntry, index, r.Len())
+	}
+
+	return update, nil
+}
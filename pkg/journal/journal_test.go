@@ -0,0 +1,155 @@
+package journal
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/iotaledger/hive.go/core/kvstore/mapdb"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+	inx "github.com/iotaledger/inx/go"
+)
+
+func testLedgerUpdate(index uint32, data string) *nodebridge.LedgerUpdate {
+	output := &inx.LedgerOutput{
+		MilestoneIndexBooked: index,
+		Output:               &inx.RawOutput{Data: []byte(data)},
+	}
+
+	return &nodebridge.LedgerUpdate{
+		MilestoneIndex: index,
+		Consumed:       []*inx.LedgerSpent{{Output: output, MilestoneIndexSpent: index}},
+		Created:        []*inx.LedgerOutput{output, {MilestoneIndexBooked: index}},
+	}
+}
+
+func checkRange(t *testing.T, j *Journal, wantFirst uint32, wantLast uint32) {
+	t.Helper()
+
+	if first, last := j.Range(); first != wantFirst || last != wantLast {
+		t.Fatalf("expected range [%d, %d], got [%d, %d]", wantFirst, wantLast, first, last)
+	}
+}
+
+func TestJournalRoundTrip(t *testing.T) {
+	store := mapdb.NewMapDB()
+
+	j, err := New(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRange(t, j, 0, 0)
+
+	updates := []*nodebridge.LedgerUpdate{testLedgerUpdate(5, "a"), testLedgerUpdate(6, "b"), testLedgerUpdate(7, "c")}
+	for _, update := range updates {
+		if err := j.Append(update); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkRange(t, j, 5, 7)
+
+	// the range is restored from the store
+	j, err = New(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRange(t, j, 5, 7)
+
+	for _, update := range updates {
+		journaled, err := j.Read(update.MilestoneIndex)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if journaled.MilestoneIndex != update.MilestoneIndex || len(journaled.Consumed) != len(update.Consumed) || len(journaled.Created) != len(update.Created) {
+			t.Fatalf("milestone %d: journaled update differs", update.MilestoneIndex)
+		}
+		for i := range update.Consumed {
+			if !proto.Equal(journaled.Consumed[i], update.Consumed[i]) {
+				t.Fatalf("milestone %d: consumed output %d differs", update.MilestoneIndex, i)
+			}
+		}
+		for i := range update.Created {
+			if !proto.Equal(journaled.Created[i], update.Created[i]) {
+				t.Fatalf("milestone %d: created output %d differs", update.MilestoneIndex, i)
+			}
+		}
+	}
+
+	if _, err := j.Read(8); !errors.Is(err, ErrEntryNotFound) {
+		t.Fatalf("expected ErrEntryNotFound, got %v", err)
+	}
+}
+
+func TestJournalAppendOrder(t *testing.T) {
+	j, err := New(mapdb.NewMapDB())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for index := uint32(5); index <= 7; index++ {
+		if err := j.Append(testLedgerUpdate(index, "a")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		update  *nodebridge.LedgerUpdate
+		wantErr error
+	}{
+		{name: "next milestone", update: testLedgerUpdate(8, "a")},
+		{name: "last milestone delivered again", update: testLedgerUpdate(8, "a")},
+		{name: "older milestone delivered again", update: testLedgerUpdate(6, "a")},
+		{name: "changed milestone", update: testLedgerUpdate(7, "b"), wantErr: ErrEntryMismatch},
+		{name: "gap", update: testLedgerUpdate(10, "a"), wantErr: ErrEntryOutOfOrder},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := j.Append(test.update); !errors.Is(err, test.wantErr) {
+				t.Fatalf("expected %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+	checkRange(t, j, 5, 8)
+
+	if err := j.PruneUntil(6); err != nil {
+		t.Fatal(err)
+	}
+	checkRange(t, j, 7, 8)
+
+	// pruned milestones can't be compared anymore and are ignored
+	if err := j.Append(testLedgerUpdate(5, "b")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := j.Read(5); !errors.Is(err, ErrEntryNotFound) {
+		t.Fatalf("expected ErrEntryNotFound, got %v", err)
+	}
+}
+
+func TestJournalConsumerRedelivery(t *testing.T) {
+	j, err := New(mapdb.NewMapDB())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var consumed []uint32
+	consume := j.Consumer(func(update *nodebridge.LedgerUpdate) error {
+		consumed = append(consumed, update.MilestoneIndex)
+
+		return nil
+	})
+
+	// a resumed stream delivers the last journaled milestone again
+	for _, index := range []uint32{1, 2, 2, 3} {
+		if err := consume(testLedgerUpdate(index, "a")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(consumed) != 4 || consumed[2] != 2 {
+		t.Fatalf("expected all updates to be consumed, got %v", consumed)
+	}
+}
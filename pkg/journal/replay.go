@@ -0,0 +1,129 @@
+package journal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/iotaledger/hive.go/core/logger"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+)
+
+const (
+	// replayProgressInterval is the minimum interval between two progress reports.
+	replayProgressInterval = time.Second
+)
+
+// ReplayProgress is the progress of replaying the journal.
+type ReplayProgress struct {
+	// StartIndex is the first milestone index that is replayed.
+	StartIndex uint32 `json:"startIndex"`
+	// CurrentIndex is the last milestone index that was replayed.
+	CurrentIndex uint32 `json:"currentIndex"`
+	// TargetIndex is the last milestone index that is replayed.
+	TargetIndex uint32 `json:"targetIndex"`
+	// Percent is the progress in percent.
+	Percent float64 `json:"percent"`
+	// MilestonesPerSecond is the observed replay rate.
+	MilestonesPerSecond float64 `json:"milestonesPerSecond"`
+	// Done tells whether the target index was reached.
+	Done bool `json:"done"`
+}
+
+// Replay feeds the journaled ledger updates from startIndex to endIndex to the consumer, without access to a node.
+// A startIndex of 0 starts at the first and an endIndex of 0 ends at the last journaled milestone.
+// The optional onProgress callback is called at most once per second and once the replay is done.
+func (j *Journal) Replay(ctx context.Context, startIndex uint32, endIndex uint32, consume func(update *nodebridge.LedgerUpdate) error, onProgress func(progress *ReplayProgress)) error {
+	firstIndex, lastIndex := j.Range()
+	if lastIndex == 0 {
+		return nil
+	}
+
+	if startIndex == 0 {
+		startIndex = firstIndex
+	}
+	if endIndex == 0 {
+		endIndex = lastIndex
+	}
+
+	if startIndex < firstIndex || endIndex > lastIndex {
+		return fmt.Errorf("%w: requested range %d-%d, journaled range %d-%d", ErrEntryNotFound, startIndex, endIndex, firstIndex, lastIndex)
+	}
+
+	startTime := time.Now()
+	lastProgressTime := startTime
+
+	reportProgress := func(index uint32, done bool) {
+		if onProgress == nil {
+			return
+		}
+
+		replayed := float64(index - startIndex + 1)
+		total := float64(endIndex - startIndex + 1)
+
+		progress := &ReplayProgress{
+			StartIndex:   startIndex,
+			CurrentIndex: index,
+			TargetIndex:  endIndex,
+			Percent:      replayed / total * 100,
+			Done:         done,
+		}
+		if elapsed := time.Since(startTime).Seconds(); elapsed > 0 {
+			progress.MilestonesPerSecond = replayed / elapsed
+		}
+
+		onProgress(progress)
+	}
+
+	for index := startIndex; index <= endIndex; index++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		update, err := j.Read(index)
+		if err != nil {
+			return err
+		}
+
+		if err := consume(update); err != nil {
+			return fmt.Errorf("replaying milestone %d failed: %w", index, err)
+		}
+
+		if index == endIndex {
+			reportProgress(index, true)
+
+			break
+		}
+
+		if time.Since(lastProgressTime) >= replayProgressInterval {
+			lastProgressTime = time.Now()
+			reportProgress(index, false)
+		}
+	}
+
+	return nil
+}
+
+// Rebuild rebuilds the app state by replaying the whole journal and logs the progress.
+// It is meant for disaster recovery and for debugging state divergence, because the result
+// only depends on the journal and the consumer.
+func (j *Journal) Rebuild(ctx context.Context, log *logger.Logger, consume func(update *nodebridge.LedgerUpdate) error) error {
+	firstIndex, lastIndex := j.Range()
+	if lastIndex == 0 {
+		log.Info("Rebuilding state from journal ... skipped, journal is empty")
+
+		return nil
+	}
+
+	log.Infof("Rebuilding state from journal (milestones %d-%d) ...", firstIndex, lastIndex)
+
+	if err := j.Replay(ctx, 0, 0, consume, func(progress *ReplayProgress) {
+		log.Infof("Rebuilding state from journal: milestone %d/%d (%.2f%%, %.2f MPS)", progress.CurrentIndex, progress.TargetIndex, progress.Percent, progress.MilestonesPerSecond)
+	}); err != nil {
+		return fmt.Errorf("rebuilding state from journal failed: %w", err)
+	}
+
+	log.Info("Rebuilding state from journal ... done")
+
+	return nil
+}
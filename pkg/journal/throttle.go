@@ -0,0 +1,121 @@
+package journal
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/time/rate"
+
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+)
+
+// ThrottleStatus is the state of a Throttle.
+type ThrottleStatus struct {
+	// MilestonesPerSecond is the maximum replay rate, 0 means unlimited.
+	MilestonesPerSecond float64 `json:"milestonesPerSecond"`
+	// Paused tells whether the replay is paused.
+	Paused bool `json:"paused"`
+}
+
+// Throttle limits the speed of replays and backfills, so they don't starve live processing.
+// The rate can be changed and the replay can be paused and resumed while it is running, e.g. via the admin API.
+// It can be used for journal replays as well as for milestone ranges streamed from the node.
+type Throttle struct {
+	mutex               sync.Mutex
+	milestonesPerSecond float64
+	limiter             *rate.Limiter
+	resumed             chan struct{}
+}
+
+// NewThrottle creates a new Throttle with the given maximum rate, 0 means unlimited.
+func NewThrottle(milestonesPerSecond float64) *Throttle {
+	t := &Throttle{
+		limiter: rate.NewLimiter(rate.Inf, 1),
+	}
+	t.SetRate(milestonesPerSecond)
+
+	return t
+}
+
+// SetRate sets the maximum replay rate, 0 means unlimited.
+func (t *Throttle) SetRate(milestonesPerSecond float64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if milestonesPerSecond < 0 {
+		milestonesPerSecond = 0
+	}
+	t.milestonesPerSecond = milestonesPerSecond
+
+	if milestonesPerSecond == 0 {
+		t.limiter.SetLimit(rate.Inf)
+
+		return
+	}
+	t.limiter.SetLimit(rate.Limit(milestonesPerSecond))
+}
+
+// Pause pauses the replay before the next milestone.
+func (t *Throttle) Pause() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.resumed == nil {
+		t.resumed = make(chan struct{})
+	}
+}
+
+// Resume resumes a paused replay.
+func (t *Throttle) Resume() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.resumed != nil {
+		close(t.resumed)
+		t.resumed = nil
+	}
+}
+
+// Status returns the current state of the throttle.
+func (t *Throttle) Status() *ThrottleStatus {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return &ThrottleStatus{
+		MilestonesPerSecond: t.milestonesPerSecond,
+		Paused:              t.resumed != nil,
+	}
+}
+
+// Wait blocks while the replay is paused and until the rate allows the next milestone, or the given context is done.
+func (t *Throttle) Wait(ctx context.Context) error {
+	for {
+		t.mutex.Lock()
+		resumed := t.resumed
+		t.mutex.Unlock()
+
+		if resumed == nil {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-resumed:
+		}
+	}
+
+	return t.limiter.Wait(ctx)
+}
+
+// Consumer wraps the given consumer, so every ledger update waits for the throttle before it is consumed.
+// The returned consumer can be passed to Journal.Replay or NodeBridge.ListenToLedgerUpdates.
+func (t *Throttle) Consumer(ctx context.Context, consume func(update *nodebridge.LedgerUpdate) error) func(update *nodebridge.LedgerUpdate) error {
+	return func(update *nodebridge.LedgerUpdate) error {
+		if err := t.Wait(ctx); err != nil {
+			return err
+		}
+
+		return consume(update)
+	}
+}
@@ -0,0 +1,322 @@
+// Package latency tracks the time between a block being issued or observed and its confirmation by a milestone,
+// so operators can quantify the network conditions from within their app.
+package latency
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/inx-app/pkg/httpserver"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+	inx "github.com/iotaledger/inx/go"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// SourceIssued is the source of blocks issued by the app.
+	SourceIssued = "issued"
+	// SourceNetwork is the source of blocks observed in the network, measured from the time they became solid.
+	SourceNetwork = "network"
+)
+
+// RouteLatency is the route to get the confirmation latency statistics.
+// GET returns the statistics per source.
+const RouteLatency = "/latency"
+
+// Stats is the latency distribution of the recent confirmations of a source.
+type Stats struct {
+	// Count is the total amount of confirmed blocks.
+	Count uint64 `json:"count"`
+	// Samples is the amount of recent confirmations the percentiles are computed from.
+	Samples int `json:"samples"`
+	// Min is the lowest recent latency.
+	Min time.Duration `json:"min"`
+	// Max is the highest recent latency.
+	Max time.Duration `json:"max"`
+	// Mean is the average recent latency.
+	Mean time.Duration `json:"mean"`
+	// P50 is the median of the recent latencies.
+	P50 time.Duration `json:"p50"`
+	// P90 is the 90th percentile of the recent latencies.
+	P90 time.Duration `json:"p90"`
+	// P99 is the 99th percentile of the recent latencies.
+	P99 time.Duration `json:"p99"`
+}
+
+// pendingBlock is a tracked block that was not confirmed yet.
+type pendingBlock struct {
+	source  string
+	startAt time.Time
+}
+
+// samples is a ring buffer of the recent latencies of a source.
+type samples struct {
+	count     uint64
+	latencies []time.Duration
+	next      int
+}
+
+func (s *samples) add(latency time.Duration, size int) {
+	s.count++
+	if len(s.latencies) < size {
+		s.latencies = append(s.latencies, latency)
+
+		return
+	}
+	s.latencies[s.next] = latency
+	s.next = (s.next + 1) % size
+}
+
+func (s *samples) stats() Stats {
+	stats := Stats{
+		Count:   s.count,
+		Samples: len(s.latencies),
+	}
+	if len(s.latencies) == 0 {
+		return stats
+	}
+
+	sorted := append(make([]time.Duration, 0, len(s.latencies)), s.latencies...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	var sum time.Duration
+	for _, latency := range sorted {
+		sum += latency
+	}
+
+	percentile := func(p float64) time.Duration {
+		return sorted[int(p*float64(len(sorted)-1))]
+	}
+
+	stats.Min = sorted[0]
+	stats.Max = sorted[len(sorted)-1]
+	stats.Mean = sum / time.Duration(len(sorted))
+	stats.P50 = percentile(0.5)
+	stats.P90 = percentile(0.9)
+	stats.P99 = percentile(0.99)
+
+	return stats
+}
+
+// Collector collects the confirmation latencies of blocks issued by the app and, if a TangleListener is given,
+// of all blocks observed in the network. The latency ends when the app learns about the confirming milestone.
+// Collector implements prometheus.Collector, so it can be registered directly.
+type Collector struct {
+	nodeBridge     *nodebridge.NodeBridge
+	tangleListener *nodebridge.TangleListener
+
+	sampleSize       int
+	maxPendingAge    time.Duration
+	maxPendingBlocks int
+
+	mutex   sync.Mutex
+	pending map[iotago.BlockID]*pendingBlock
+	samples map[string]*samples
+
+	histogram *prometheus.HistogramVec
+}
+
+// WithTangleListener also tracks all blocks that become solid on the node, using the given TangleListener.
+func WithTangleListener(tangleListener *nodebridge.TangleListener) options.Option[Collector] {
+	return func(c *Collector) {
+		c.tangleListener = tangleListener
+	}
+}
+
+// WithSampleSize sets the amount of recent confirmations per source the statistics are computed from.
+func WithSampleSize(sampleSize int) options.Option[Collector] {
+	return func(c *Collector) {
+		c.sampleSize = sampleSize
+	}
+}
+
+// WithMaxPendingAge sets the duration after which unconfirmed blocks are no longer tracked.
+func WithMaxPendingAge(maxPendingAge time.Duration) options.Option[Collector] {
+	return func(c *Collector) {
+		c.maxPendingAge = maxPendingAge
+	}
+}
+
+// WithMaxPendingBlocks sets the maximum amount of tracked unconfirmed blocks.
+// Observed network blocks are ignored if the limit is reached, blocks issued by the app are always tracked.
+func WithMaxPendingBlocks(maxPendingBlocks int) options.Option[Collector] {
+	return func(c *Collector) {
+		c.maxPendingBlocks = maxPendingBlocks
+	}
+}
+
+// NewCollector creates a new Collector. The namespace is used as prefix of the prometheus metric names.
+func NewCollector(nodeBridge *nodebridge.NodeBridge, namespace string, opts ...options.Option[Collector]) *Collector {
+	return options.Apply(&Collector{
+		nodeBridge:       nodeBridge,
+		sampleSize:       1000,
+		maxPendingAge:    5 * time.Minute,
+		maxPendingBlocks: 100_000,
+		pending:          make(map[iotago.BlockID]*pendingBlock),
+		samples:          make(map[string]*samples),
+		histogram: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "latency",
+			Name:      "confirmation_seconds",
+			Help:      "The time between a block being issued or observed and its confirmation.",
+			Buckets:   []float64{1, 2, 5, 10, 15, 20, 30, 45, 60, 90, 120, 300},
+		}, []string{"source"}),
+	}, opts)
+}
+
+// TrackIssued tracks the confirmation latency of a block issued by the app at the given time.
+func (c *Collector) TrackIssued(blockID iotago.BlockID, issuedAt time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.pending[blockID] = &pendingBlock{source: SourceIssued, startAt: issuedAt}
+}
+
+func (c *Collector) trackObserved(metadata *inx.BlockMetadata) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if len(c.pending) >= c.maxPendingBlocks {
+		return
+	}
+
+	blockID := metadata.UnwrapBlockID()
+	if _, exists := c.pending[blockID]; !exists {
+		c.pending[blockID] = &pendingBlock{source: SourceNetwork, startAt: time.Now()}
+	}
+}
+
+func (c *Collector) confirmed(blockID iotago.BlockID, confirmedAt time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	pending, exists := c.pending[blockID]
+	if !exists {
+		return
+	}
+	delete(c.pending, blockID)
+
+	latency := confirmedAt.Sub(pending.startAt)
+	if latency < 0 {
+		latency = 0
+	}
+
+	sourceSamples, exists := c.samples[pending.source]
+	if !exists {
+		sourceSamples = &samples{}
+		c.samples[pending.source] = sourceSamples
+	}
+	sourceSamples.add(latency, c.sampleSize)
+	c.histogram.WithLabelValues(pending.source).Observe(latency.Seconds())
+}
+
+// prune stops tracking blocks that were not confirmed within the max pending age.
+func (c *Collector) prune(now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for blockID, pending := range c.pending {
+		if now.Sub(pending.startAt) > c.maxPendingAge {
+			delete(c.pending, blockID)
+		}
+	}
+}
+
+func (c *Collector) processMilestone(ctx context.Context, index uint32) {
+	confirmedAt := time.Now()
+
+	coneCtx, cancel := context.WithCancel(ctx)
+	if err := c.nodeBridge.MilestoneConeMetadata(coneCtx, cancel, index, func(metadata *inx.BlockMetadata) {
+		c.confirmed(metadata.UnwrapBlockID(), confirmedAt)
+	}); err != nil {
+		c.nodeBridge.LogWarnf("reading the cone of milestone %d failed: %s", index, err.Error())
+	}
+
+	c.prune(confirmedAt)
+}
+
+// Run collects the latencies until the given context is done.
+// Every confirmed milestone is processed, also if the collector fell behind and the confirmed index moved on by more than one.
+func (c *Collector) Run(ctx context.Context) {
+	milestoneConfirmed := make(chan struct{}, 1)
+
+	onConfirmedMilestone := events.NewClosure(func(_ *nodebridge.Milestone) {
+		select {
+		case milestoneConfirmed <- struct{}{}:
+		default:
+			// the collector is already signaled, it catches up to the latest confirmed milestone
+		}
+	})
+	c.nodeBridge.Events.ConfirmedMilestoneChanged.Hook(onConfirmedMilestone)
+	defer c.nodeBridge.Events.ConfirmedMilestoneChanged.Detach(onConfirmedMilestone)
+
+	if c.tangleListener != nil {
+		onBlockSolid := events.NewClosure(c.trackObserved)
+		c.tangleListener.Events.BlockSolid.Hook(onBlockSolid)
+		defer c.tangleListener.Events.BlockSolid.Detach(onBlockSolid)
+	}
+
+	// milestones confirmed before the collector was started don't contain tracked blocks
+	lastProcessedIndex := c.nodeBridge.ConfirmedMilestoneIndex()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-milestoneConfirmed:
+			confirmedIndex := c.nodeBridge.ConfirmedMilestoneIndex()
+			for index := lastProcessedIndex + 1; index <= confirmedIndex; index++ {
+				if ctx.Err() != nil {
+					return
+				}
+				c.processMilestone(ctx, index)
+				lastProcessedIndex = index
+			}
+		}
+	}
+}
+
+// Stats returns the latency statistics per source.
+func (c *Collector) Stats() map[string]Stats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	stats := make(map[string]Stats, len(c.samples))
+	for source, sourceSamples := range c.samples {
+		stats[source] = sourceSamples.stats()
+	}
+
+	return stats
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.histogram.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.histogram.Collect(ch)
+}
+
+// Handler returns a handler that serves the latency statistics of all sources.
+func (c *Collector) Handler() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		return httpserver.JSONResponse(ctx, http.StatusOK, c.Stats())
+	}
+}
+
+// RegisterRoutes registers the latency route on the given group.
+func (c *Collector) RegisterRoutes(routeGroup *echo.Group) {
+	routeGroup.GET(RouteLatency, c.Handler())
+}
@@ -0,0 +1,181 @@
+package migration
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/iotaledger/hive.go/core/kvstore"
+	"github.com/iotaledger/hive.go/core/logger"
+)
+
+var (
+	// ErrInvalidMigrations is returned when the migrations are not numbered consecutively starting at 1.
+	ErrInvalidMigrations = errors.New("invalid migrations")
+	// ErrUnknownSchemaVersion is returned when the stored schema version is newer than the latest known migration.
+	ErrUnknownSchemaVersion = errors.New("unknown schema version")
+	// ErrIrreversibleMigration is returned when a migration without Down function needs to be reverted.
+	ErrIrreversibleMigration = errors.New("migration can't be reverted")
+)
+
+// Migration migrates the persisted state from the previous schema version to Version and back.
+type Migration struct {
+	// Version is the schema version after the migration was applied.
+	Version uint32
+	// Name describes the migration.
+	Name string
+	// Up migrates the state from Version-1 to Version.
+	Up func() error
+	// Down reverts the state from Version to Version-1 (optional).
+	Down func() error
+}
+
+// VersionStore persists the schema version.
+// It can be implemented for any storage, e.g. a kvstore or a SQL table.
+type VersionStore interface {
+	// SchemaVersion returns the stored schema version, or 0 if none was stored yet.
+	SchemaVersion() (uint32, error)
+	// SetSchemaVersion stores the schema version.
+	SetSchemaVersion(version uint32) error
+}
+
+type kvStoreVersionStore struct {
+	store kvstore.KVStore
+	key   kvstore.Key
+}
+
+// NewKVStoreVersionStore returns a VersionStore that persists the schema version under the given key in the store.
+func NewKVStoreVersionStore(store kvstore.KVStore, key kvstore.Key) VersionStore {
+	return &kvStoreVersionStore{
+		store: store,
+		key:   key,
+	}
+}
+
+func (s *kvStoreVersionStore) SchemaVersion() (uint32, error) {
+	value, err := s.store.Get(s.key)
+	if err != nil {
+		if errors.Is(err, kvstore.ErrKeyNotFound) {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	if len(value) != 4 {
+		return 0, fmt.Errorf("invalid schema version length: %d", len(value))
+	}
+
+	return binary.LittleEndian.Uint32(value), nil
+}
+
+func (s *kvStoreVersionStore) SetSchemaVersion(version uint32) error {
+	value := make([]byte, 4)
+	binary.LittleEndian.PutUint32(value, version)
+
+	if err := s.store.Set(s.key, value); err != nil {
+		return err
+	}
+
+	return s.store.Flush()
+}
+
+// Runner applies migrations and keeps track of the schema version.
+type Runner struct {
+	// the logger used to log events.
+	*logger.WrappedLogger
+
+	versionStore VersionStore
+	migrations   []*Migration
+}
+
+// NewRunner creates a new Runner.
+// The migrations have to be numbered consecutively starting at 1, the order they are passed in doesn't matter.
+func NewRunner(log *logger.Logger, versionStore VersionStore, migrations ...*Migration) (*Runner, error) {
+	sorted := make([]*Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i int, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+
+	for i, migration := range sorted {
+		if migration.Version != uint32(i+1) {
+			return nil, fmt.Errorf("%w: expected version %d, got %d (%s)", ErrInvalidMigrations, i+1, migration.Version, migration.Name)
+		}
+		if migration.Up == nil {
+			return nil, fmt.Errorf("%w: migration %d (%s) has no Up function", ErrInvalidMigrations, migration.Version, migration.Name)
+		}
+	}
+
+	return &Runner{
+		WrappedLogger: logger.NewWrappedLogger(log),
+		versionStore:  versionStore,
+		migrations:    sorted,
+	}, nil
+}
+
+// CurrentVersion returns the stored schema version.
+func (r *Runner) CurrentVersion() (uint32, error) {
+	return r.versionStore.SchemaVersion()
+}
+
+// LatestVersion returns the schema version after all migrations were applied.
+func (r *Runner) LatestVersion() uint32 {
+	return uint32(len(r.migrations))
+}
+
+// MigrateUp applies all pending migrations.
+func (r *Runner) MigrateUp() error {
+	return r.MigrateTo(r.LatestVersion())
+}
+
+// MigrateTo applies or reverts migrations until the schema has the target version.
+// The schema version is stored after every single migration, so an interrupted run can be continued.
+func (r *Runner) MigrateTo(targetVersion uint32) error {
+	if targetVersion > r.LatestVersion() {
+		return fmt.Errorf("%w: target version %d, latest version %d", ErrUnknownSchemaVersion, targetVersion, r.LatestVersion())
+	}
+
+	currentVersion, err := r.CurrentVersion()
+	if err != nil {
+		return err
+	}
+
+	if currentVersion > r.LatestVersion() {
+		return fmt.Errorf("%w: stored version %d, latest version %d", ErrUnknownSchemaVersion, currentVersion, r.LatestVersion())
+	}
+
+	for currentVersion < targetVersion {
+		migration := r.migrations[currentVersion]
+
+		r.LogInfof("Applying migration %d (%s) ...", migration.Version, migration.Name)
+		if err := migration.Up(); err != nil {
+			return fmt.Errorf("applying migration %d (%s) failed: %w", migration.Version, migration.Name, err)
+		}
+
+		if err := r.versionStore.SetSchemaVersion(migration.Version); err != nil {
+			return fmt.Errorf("storing schema version %d failed: %w", migration.Version, err)
+		}
+		currentVersion = migration.Version
+	}
+
+	for currentVersion > targetVersion {
+		migration := r.migrations[currentVersion-1]
+		if migration.Down == nil {
+			return fmt.Errorf("%w: %d (%s)", ErrIrreversibleMigration, migration.Version, migration.Name)
+		}
+
+		r.LogInfof("Reverting migration %d (%s) ...", migration.Version, migration.Name)
+		if err := migration.Down(); err != nil {
+			return fmt.Errorf("reverting migration %d (%s) failed: %w", migration.Version, migration.Name, err)
+		}
+
+		if err := r.versionStore.SetSchemaVersion(migration.Version - 1); err != nil {
+			return fmt.Errorf("storing schema version %d failed: %w", migration.Version-1, err)
+		}
+		currentVersion = migration.Version - 1
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package nodebridge
+
+import (
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// AddressFormat controls which forms of an address are included in a FormattedAddress.
+type AddressFormat byte
+
+const (
+	// AddressFormatHex includes the hex form of the address.
+	AddressFormatHex AddressFormat = 1 << iota
+	// AddressFormatBech32 includes the bech32 form of the address.
+	AddressFormatBech32
+
+	// AddressFormatBoth includes the hex and the bech32 form of the address.
+	AddressFormatBoth = AddressFormatHex | AddressFormatBech32
+)
+
+// FormattedAddress is the JSON representation of an address or a chain ID in responses.
+type FormattedAddress struct {
+	// Type is the type of the address, e.g. 0 for Ed25519, 8 for alias and 16 for NFT addresses.
+	Type iotago.AddressType `json:"type"`
+	// Hex is the hex encoded hash of the address, or the chain ID of alias and NFT addresses.
+	Hex string `json:"hex,omitempty"`
+	// Bech32 is the bech32 encoded address.
+	Bech32 string `json:"bech32,omitempty"`
+}
+
+// FormatAddress renders the address in the forms selected by the given format.
+func FormatAddress(hrp iotago.NetworkPrefix, address iotago.Address, format AddressFormat) *FormattedAddress {
+	if address == nil {
+		return nil
+	}
+
+	formatted := &FormattedAddress{
+		Type: address.Type(),
+	}
+	if format&AddressFormatHex != 0 {
+		formatted.Hex = address.String()
+	}
+	if format&AddressFormatBech32 != 0 {
+		formatted.Bech32 = address.Bech32(hrp)
+	}
+
+	return formatted
+}
+
+// FormatChainID renders the chain ID of an alias or NFT in the forms selected by the given format.
+// The bech32 form is the one of the address of the chain.
+func FormatChainID(hrp iotago.NetworkPrefix, chainID iotago.ChainID, format AddressFormat) *FormattedAddress {
+	if chainID == nil || !chainID.Addressable() {
+		return nil
+	}
+
+	return FormatAddress(hrp, chainID.ToAddress(), format)
+}
+
+// FormatAddress renders the address in the forms selected by the given format, using the HRP of the network.
+func (n *NodeBridge) FormatAddress(address iotago.Address, format AddressFormat) *FormattedAddress {
+	return FormatAddress(n.ProtocolParameters().Bech32HRP, address, format)
+}
+
+// FormatAliasID renders the alias ID in the forms selected by the given format, using the HRP of the network.
+func (n *NodeBridge) FormatAliasID(aliasID iotago.AliasID, format AddressFormat) *FormattedAddress {
+	return FormatChainID(n.ProtocolParameters().Bech32HRP, aliasID, format)
+}
+
+// FormatNFTID renders the NFT ID in the forms selected by the given format, using the HRP of the network.
+func (n *NodeBridge) FormatNFTID(nftID iotago.NFTID, format AddressFormat) *FormattedAddress {
+	return FormatChainID(n.ProtocolParameters().Bech32HRP, nftID, format)
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/iotaledger/inx-app/pkg/httpserver"
 	inx "github.com/iotaledger/inx/go"
 	"github.com/iotaledger/iota.go/v3/nodeclient"
 )
@@ -15,6 +16,11 @@ func (n *NodeBridge) INXNodeClient() *nodeclient.Client {
 }
 
 func (n *NodeBridge) RegisterAPIRoute(ctx context.Context, route string, bindAddress string) error {
+	if httpserver.IsUnixSocketAddress(bindAddress) {
+		// the node only proxies requests to TCP addresses
+		return fmt.Errorf("the node can't proxy the route %s to the unix socket %s, expose the server via TCP or a local reverse proxy", route, bindAddress)
+	}
+
 	bindAddressParts := strings.Split(bindAddress, ":")
 	if len(bindAddressParts) != 2 {
 		return fmt.Errorf("invalid address %s", bindAddress)
@@ -0,0 +1,123 @@
+package nodebridge
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/inx-app/pkg/httpserver"
+	inx "github.com/iotaledger/inx/go"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// WaitForBlockReferenced blocks until the block is referenced by a milestone or the context is done.
+// It returns the latest metadata of the block, also if the context is done, together with the error of the context.
+func (t *TangleListener) WaitForBlockReferenced(ctx context.Context, blockID iotago.BlockID) (*inx.BlockMetadata, error) {
+	// the metadata is checked again for every confirmed milestone, the confirmed index may skip milestones
+	milestoneConfirmed := make(chan struct{}, 1)
+	onMilestoneConfirmed := events.NewClosure(func(_ *Milestone) {
+		select {
+		case milestoneConfirmed <- struct{}{}:
+		default:
+		}
+	})
+
+	// the event is hooked before the metadata is checked, so a milestone confirmed in between is not missed
+	t.nodeBridge.Events.ConfirmedMilestoneChanged.Hook(onMilestoneConfirmed)
+	defer t.nodeBridge.Events.ConfirmedMilestoneChanged.Detach(onMilestoneConfirmed)
+
+	for {
+		metadata, err := t.nodeBridge.BlockMetadata(ctx, blockID)
+		if err != nil {
+			return nil, err
+		}
+
+		if metadata.GetReferencedByMilestoneIndex() != 0 {
+			return metadata, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return metadata, ctx.Err()
+		case <-milestoneConfirmed:
+		}
+	}
+}
+
+// BlockConfirmationResponse defines the response of the BlockConfirmationHandler.
+type BlockConfirmationResponse struct {
+	// BlockID is the hex encoded ID of the block.
+	BlockID string `json:"blockId"`
+	// Referenced tells whether the block is referenced by a milestone.
+	Referenced bool `json:"referenced"`
+	// ReferencedByMilestoneIndex is the index of the milestone that referenced the block, if it is referenced.
+	ReferencedByMilestoneIndex uint32 `json:"referencedByMilestoneIndex,omitempty"`
+	// LedgerInclusionState is "noTransaction", "included" or "conflicting", if the block is referenced.
+	LedgerInclusionState string `json:"ledgerInclusionState,omitempty"`
+	// TimedOut tells whether the block was not referenced before the timeout of a waiting request.
+	TimedOut bool `json:"timedOut,omitempty"`
+}
+
+func ledgerInclusionState(state inx.BlockMetadata_LedgerInclusionState) string {
+	switch state {
+	case inx.BlockMetadata_LEDGER_INCLUSION_STATE_INCLUDED:
+		return "included"
+	case inx.BlockMetadata_LEDGER_INCLUSION_STATE_CONFLICTING:
+		return "conflicting"
+	default:
+		return "noTransaction"
+	}
+}
+
+// BlockConfirmationHandler returns a handler that tells whether the block of the given path parameter is referenced.
+// With "wait=true" the request blocks until the block is referenced or the timeout is reached,
+// so clients can wait for the confirmation over plain HTTP. The "timeout" defaults to defaultTimeout,
+// it must not exceed maxTimeout. A request that timed out is answered with the current state and TimedOut set.
+func BlockConfirmationHandler(tangleListener *TangleListener, paramName string, defaultTimeout time.Duration, maxTimeout time.Duration) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		blockID, err := httpserver.ParseBlockIDParam(c, paramName)
+		if err != nil {
+			return err
+		}
+
+		longPoll, err := httpserver.ParseLongPollQueryParams(c, defaultTimeout, maxTimeout)
+		if err != nil {
+			return err
+		}
+
+		var metadata *inx.BlockMetadata
+		timedOut := false
+		if longPoll.Wait {
+			waitCtx, waitCancel := longPoll.WaitContext(c)
+			defer waitCancel()
+
+			metadata, err = tangleListener.WaitForBlockReferenced(waitCtx, blockID)
+			if err != nil {
+				if !httpserver.TimedOut(c, waitCtx) || metadata == nil {
+					return httpserver.MapGRPCError(err)
+				}
+				timedOut = true
+			}
+		} else {
+			metadata, err = tangleListener.nodeBridge.BlockMetadata(c.Request().Context(), blockID)
+			if err != nil {
+				return httpserver.MapGRPCError(err)
+			}
+		}
+
+		response := &BlockConfirmationResponse{
+			BlockID:  blockID.ToHex(),
+			TimedOut: timedOut,
+		}
+		if referencedIndex := metadata.GetReferencedByMilestoneIndex(); referencedIndex != 0 {
+			response.Referenced = true
+			response.ReferencedByMilestoneIndex = referencedIndex
+			response.LedgerInclusionState = ledgerInclusionState(metadata.GetLedgerInclusionState())
+		}
+
+		return httpserver.JSONResponse(c, http.StatusOK, response)
+	}
+}
@@ -2,17 +2,17 @@ package nodebridge
 
 import (
 	"context"
+	"encoding/binary"
 	"errors"
 	"io"
 
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
-
 	"github.com/iotaledger/hive.go/serializer/v2"
 	inx "github.com/iotaledger/inx/go"
 	iotago "github.com/iotaledger/iota.go/v3"
 )
 
+// SubmitBlock submits the block to the node.
+// If the node rejects the block, a *SubmissionError is returned that tells whether to rebuild, redo the PoW or drop it.
 func (n *NodeBridge) SubmitBlock(ctx context.Context, block *iotago.Block) (iotago.BlockID, error) {
 	blk, err := inx.WrapBlock(block)
 	if err != nil {
@@ -21,7 +21,7 @@ func (n *NodeBridge) SubmitBlock(ctx context.Context, block *iotago.Block) (iota
 
 	response, err := n.client.SubmitBlock(ctx, blk)
 	if err != nil {
-		return iotago.BlockID{}, err
+		return iotago.BlockID{}, newSubmissionError(err)
 	}
 
 	return response.Unwrap(), nil
@@ -40,31 +40,81 @@ func (n *NodeBridge) Block(ctx context.Context, blockID iotago.BlockID) (*iotago
 	return inxMsg.UnwrapBlock(serializer.DeSeriModeNoValidation, nil)
 }
 
+// blockPayloadType peeks at the payload type of a serialized block without deserializing it.
+// It returns false if the block has no payload or is malformed.
+func blockPayloadType(data []byte) (iotago.PayloadType, bool) {
+	// protocol version, parents count
+	const parentsOffset = serializer.OneByte + serializer.OneByte
+
+	if len(data) < parentsOffset {
+		return 0, false
+	}
+
+	payloadLengthOffset := parentsOffset + int(data[serializer.OneByte])*iotago.BlockIDLength
+	payloadTypeOffset := payloadLengthOffset + serializer.UInt32ByteSize
+	if len(data) < payloadTypeOffset+serializer.TypeDenotationByteSize {
+		return 0, false
+	}
+
+	if binary.LittleEndian.Uint32(data[payloadLengthOffset:payloadTypeOffset]) == 0 {
+		return 0, false
+	}
+
+	return iotago.PayloadType(binary.LittleEndian.Uint32(data[payloadTypeOffset:])), true
+}
+
 func (n *NodeBridge) ListenToBlocks(ctx context.Context, cancel context.CancelFunc, consumer func(block *iotago.Block)) error {
+	return n.ListenToBlocksWithPayloadTypes(ctx, cancel, nil, consumer)
+}
+
+// ListenToBlocksWithPayloadTypes listens to blocks that contain a payload of one of the given types.
+// Blocks are filtered before they are decoded, so apps that e.g. only care about transactions
+// don't pay for decoding the majority of data-only blocks. All blocks are passed if no payload types are given.
+func (n *NodeBridge) ListenToBlocksWithPayloadTypes(ctx context.Context, cancel context.CancelFunc, payloadTypes []iotago.PayloadType, consumer func(block *iotago.Block)) error {
 	defer cancel()
 
+	filter := make(map[iotago.PayloadType]struct{}, len(payloadTypes))
+	for _, payloadType := range payloadTypes {
+		filter[payloadType] = struct{}{}
+	}
+
 	stream, err := n.client.ListenToBlocks(ctx, &inx.NoParams{})
 	if err != nil {
-		return err
+		return newStreamError("ListenToBlocks", err)
 	}
 
 	for {
 		block, err := stream.Recv()
 		if err != nil {
-			if errors.Is(err, io.EOF) || status.Code(err) == codes.Canceled {
+			if errors.Is(err, io.EOF) || ctx.Err() != nil {
 				break
 			}
-			n.LogErrorf("ListenToBlocks: %s", err.Error())
 
-			break
+			return newStreamError("ListenToBlocks", err)
 		}
 		if ctx.Err() != nil {
 			break
 		}
 
-		consumer(block.MustUnwrapBlock(serializer.DeSeriModeNoValidation, nil))
+		if len(filter) > 0 {
+			payloadType, hasPayload := blockPayloadType(block.GetBlock().GetData())
+			if !hasPayload {
+				continue
+			}
+			if _, matches := filter[payloadType]; !matches {
+				continue
+			}
+		}
+
+		iotaBlock, err := block.UnwrapBlock(serializer.DeSeriModeNoValidation, nil)
+		if err != nil {
+			n.sampledLogger.LogWarnf("ListenToBlocksDecode", "ListenToBlocks: unable to decode block: %s", err.Error())
+
+			continue
+		}
+
+		consumer(iotaBlock)
 	}
 
-	//nolint:nilerr // false positive
 	return nil
 }
@@ -0,0 +1,122 @@
+package nodebridge
+
+import (
+	"time"
+)
+
+const (
+	// catchUpProgressEventInterval is the minimum interval between two CatchUpProgressUpdated events.
+	catchUpProgressEventInterval = time.Second
+)
+
+// CatchUpProgress is the progress of replaying historical milestones.
+type CatchUpProgress struct {
+	// StartIndex is the first milestone index that was replayed.
+	StartIndex uint32 `json:"startIndex"`
+	// CurrentIndex is the last milestone index that was consumed.
+	CurrentIndex uint32 `json:"currentIndex"`
+	// TargetIndex is the milestone index at which the catch-up is done.
+	TargetIndex uint32 `json:"targetIndex"`
+	// Percent is the progress in percent.
+	Percent float64 `json:"percent"`
+	// MilestonesPerSecond is the observed replay rate.
+	MilestonesPerSecond float64 `json:"milestonesPerSecond"`
+	// EstimatedTimeRemaining is the estimated time until the target index is reached.
+	EstimatedTimeRemaining time.Duration `json:"estimatedTimeRemaining"`
+	// Done tells whether the target index was reached.
+	Done bool `json:"done"`
+}
+
+func CatchUpProgressCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(progress *CatchUpProgress))(params[0].(*CatchUpProgress))
+}
+
+// CatchUpProgress returns the progress of the last catch-up, or nil if no catch-up happened.
+func (n *NodeBridge) CatchUpProgress() *CatchUpProgress {
+	n.catchUpProgressMutex.RLock()
+	defer n.catchUpProgressMutex.RUnlock()
+
+	if n.catchUpProgress == nil {
+		return nil
+	}
+	progress := *n.catchUpProgress
+
+	return &progress
+}
+
+// catchUpTracker tracks the progress of a single catch-up.
+type catchUpTracker struct {
+	nodeBridge    *NodeBridge
+	startIndex    uint32
+	targetIndex   uint32
+	startTime     time.Time
+	lastEventTime time.Time
+}
+
+// newCatchUpTracker returns a tracker if the given range needs a catch-up, otherwise nil.
+func (n *NodeBridge) newCatchUpTracker(startIndex uint32, endIndex uint32) *catchUpTracker {
+	targetIndex := endIndex
+	if targetIndex == 0 {
+		// the stream is unbounded, so the catch-up is done once the current confirmed milestone was consumed
+		targetIndex = n.ConfirmedMilestoneIndex()
+	}
+
+	if startIndex == 0 || startIndex > targetIndex {
+		return nil
+	}
+
+	return &catchUpTracker{
+		nodeBridge:  n,
+		startIndex:  startIndex,
+		targetIndex: targetIndex,
+		startTime:   time.Now(),
+	}
+}
+
+// update records that the milestone with the given index was consumed.
+// It returns false once the catch-up is done and the tracker should no longer be used.
+func (t *catchUpTracker) update(index uint32) bool {
+	now := time.Now()
+	done := index >= t.targetIndex
+
+	if !done && now.Sub(t.lastEventTime) < catchUpProgressEventInterval {
+		return true
+	}
+	t.lastEventTime = now
+
+	total := float64(t.targetIndex - t.startIndex + 1)
+	consumed := float64(index - t.startIndex + 1)
+
+	var rate float64
+	if elapsed := now.Sub(t.startTime).Seconds(); elapsed > 0 {
+		rate = consumed / elapsed
+	}
+
+	percent := 100.0
+	var remaining time.Duration
+	if !done {
+		percent = consumed / total * 100
+		if rate > 0 {
+			remaining = time.Duration(float64(t.targetIndex-index) / rate * float64(time.Second))
+		}
+	}
+
+	progress := &CatchUpProgress{
+		StartIndex:             t.startIndex,
+		CurrentIndex:           index,
+		TargetIndex:            t.targetIndex,
+		Percent:                percent,
+		MilestonesPerSecond:    rate,
+		EstimatedTimeRemaining: remaining,
+		Done:                   done,
+	}
+
+	t.nodeBridge.catchUpProgressMutex.Lock()
+	t.nodeBridge.catchUpProgress = progress
+	t.nodeBridge.catchUpProgressMutex.Unlock()
+
+	t.nodeBridge.Events.CatchUpProgressUpdated.Trigger(progress)
+
+	return !done
+}
@@ -0,0 +1,77 @@
+package nodebridge
+
+import (
+	"errors"
+	"fmt"
+
+	inx "github.com/iotaledger/inx/go"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// ErrNodeInconsistent is returned if the node served data that contradicts data it served before,
+// e.g. after a resync from a snapshot. Apps must not continue processing, because their state would be corrupted.
+var ErrNodeInconsistent = errors.New("node served inconsistent data")
+
+// InconsistencyKind names the assertion that failed.
+type InconsistencyKind string
+
+const (
+	// InconsistencyLedgerIndexRegression means the ledger index of the node decreased.
+	InconsistencyLedgerIndexRegression InconsistencyKind = "ledgerIndexRegression"
+	// InconsistencyConfirmedMilestoneRegression means the confirmed milestone index of the node decreased.
+	InconsistencyConfirmedMilestoneRegression InconsistencyKind = "confirmedMilestoneRegression"
+	// InconsistencyConfirmedMilestoneMismatch means the node reported a different milestone for an already confirmed index.
+	InconsistencyConfirmedMilestoneMismatch InconsistencyKind = "confirmedMilestoneMismatch"
+	// InconsistencyLedgerUpdateSequence means the ledger updates of a stream were not consecutive.
+	InconsistencyLedgerUpdateSequence InconsistencyKind = "ledgerUpdateSequence"
+)
+
+// InconsistencyError describes which consistency assertion failed. It matches ErrNodeInconsistent.
+type InconsistencyError struct {
+	// Kind is the assertion that failed.
+	Kind InconsistencyKind
+	// Previous is the index that was observed before.
+	Previous iotago.MilestoneIndex
+	// Current is the index that violated the assertion.
+	Current iotago.MilestoneIndex
+}
+
+func (e *InconsistencyError) Error() string {
+	return fmt.Sprintf("%s: %s, previous index %d, current index %d", ErrNodeInconsistent, e.Kind, e.Previous, e.Current)
+}
+
+func (e *InconsistencyError) Unwrap() error {
+	return ErrNodeInconsistent
+}
+
+func InconsistencyErrorCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(err *InconsistencyError))(params[0].(*InconsistencyError))
+}
+
+// checkNodeStatusConsistency asserts that the new node status does not contradict the previous one.
+func checkNodeStatusConsistency(previous *inx.NodeStatus, current *inx.NodeStatus) *InconsistencyError {
+	if current.GetLedgerIndex() < previous.GetLedgerIndex() {
+		return &InconsistencyError{Kind: InconsistencyLedgerIndexRegression, Previous: previous.GetLedgerIndex(), Current: current.GetLedgerIndex()}
+	}
+
+	previousConfirmed := previous.GetConfirmedMilestone().GetMilestoneInfo()
+	currentConfirmed := current.GetConfirmedMilestone().GetMilestoneInfo()
+	if currentConfirmed.GetMilestoneIndex() < previousConfirmed.GetMilestoneIndex() {
+		return &InconsistencyError{Kind: InconsistencyConfirmedMilestoneRegression, Previous: previousConfirmed.GetMilestoneIndex(), Current: currentConfirmed.GetMilestoneIndex()}
+	}
+
+	if currentConfirmed.GetMilestoneIndex() == previousConfirmed.GetMilestoneIndex() &&
+		previousConfirmed.GetMilestoneId() != nil && currentConfirmed.GetMilestoneId() != nil &&
+		previousConfirmed.GetMilestoneId().Unwrap() != currentConfirmed.GetMilestoneId().Unwrap() {
+		return &InconsistencyError{Kind: InconsistencyConfirmedMilestoneMismatch, Previous: previousConfirmed.GetMilestoneIndex(), Current: currentConfirmed.GetMilestoneIndex()}
+	}
+
+	return nil
+}
+
+// nodeInconsistent triggers the NodeInconsistent event and logs the inconsistency.
+func (n *NodeBridge) nodeInconsistent(err *InconsistencyError) {
+	n.LogErrorf("%s", err.Error())
+	n.Events.NodeInconsistent.Trigger(err)
+}
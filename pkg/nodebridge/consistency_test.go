@@ -0,0 +1,54 @@
+package nodebridge
+
+import (
+	"errors"
+	"testing"
+
+	inx "github.com/iotaledger/inx/go"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func testNodeStatus(ledgerIndex uint32, confirmedIndex uint32, confirmedID byte) *inx.NodeStatus {
+	return &inx.NodeStatus{
+		LedgerIndex: ledgerIndex,
+		ConfirmedMilestone: &inx.Milestone{
+			MilestoneInfo: inx.NewMilestoneInfo(iotago.MilestoneID{confirmedID}, confirmedIndex, 0),
+		},
+	}
+}
+
+func TestCheckNodeStatusConsistency(t *testing.T) {
+	previous := testNodeStatus(10, 10, 1)
+
+	tests := []struct {
+		name     string
+		current  *inx.NodeStatus
+		wantKind InconsistencyKind
+	}{
+		{name: "unchanged", current: testNodeStatus(10, 10, 1)},
+		{name: "progressed", current: testNodeStatus(11, 11, 2)},
+		{name: "ledger index regression", current: testNodeStatus(9, 10, 1), wantKind: InconsistencyLedgerIndexRegression},
+		{name: "confirmed milestone regression", current: testNodeStatus(10, 9, 1), wantKind: InconsistencyConfirmedMilestoneRegression},
+		{name: "confirmed milestone mismatch", current: testNodeStatus(10, 10, 2), wantKind: InconsistencyConfirmedMilestoneMismatch},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			inconsistency := checkNodeStatusConsistency(previous, test.current)
+			if test.wantKind == "" {
+				if inconsistency != nil {
+					t.Fatalf("unexpected inconsistency: %s", inconsistency)
+				}
+
+				return
+			}
+
+			if inconsistency == nil || inconsistency.Kind != test.wantKind {
+				t.Fatalf("expected inconsistency %s, got %v", test.wantKind, inconsistency)
+			}
+			if !errors.Is(inconsistency, ErrNodeInconsistent) {
+				t.Fatal("expected the inconsistency to match ErrNodeInconsistent")
+			}
+		})
+	}
+}
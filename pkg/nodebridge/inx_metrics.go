@@ -0,0 +1,121 @@
+package nodebridge
+
+import (
+	"context"
+	"path"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+)
+
+// INXMetrics records the latencies of the INX methods called by the NodeBridge, labeled by the method, e.g. "ReadOutput".
+// The time a stream waits for the next message of the node is recorded separately from the time the app needs
+// to consume a message, so operators can tell whether slowness originates in the node and the network, or in the app.
+// INXMetrics implements prometheus.Collector, so it can be registered directly.
+type INXMetrics struct {
+	requestDuration *prometheus.HistogramVec
+	streamRecvWait  *prometheus.HistogramVec
+	streamConsume   *prometheus.HistogramVec
+}
+
+// NewINXMetrics creates new INXMetrics. The namespace is used as prefix of the prometheus metric names.
+func NewINXMetrics(namespace string) *INXMetrics {
+	newHistogram := func(name string, help string, buckets []float64) *prometheus.HistogramVec {
+		return prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "inx",
+			Name:      name,
+			Help:      help,
+			Buckets:   buckets,
+		}, []string{"method"})
+	}
+
+	return &INXMetrics{
+		requestDuration: newHistogram("request_duration_seconds", "The time it took the node to answer a request.", prometheus.DefBuckets),
+		streamRecvWait:  newHistogram("stream_recv_wait_seconds", "The time a stream waited for the next message of the node.", prometheus.ExponentialBuckets(0.001, 4, 10)),
+		streamConsume:   newHistogram("stream_consume_seconds", "The time the app needed to consume a message of a stream before receiving the next one.", prometheus.ExponentialBuckets(0.0001, 4, 10)),
+	}
+}
+
+// WithINXMetrics records the latencies of the INX methods in the given INXMetrics.
+func WithINXMetrics(metrics *INXMetrics) options.Option[NodeBridge] {
+	return func(n *NodeBridge) {
+		n.inxMetrics = metrics
+	}
+}
+
+// methodName returns the name of the INX method without the service, e.g. "ReadOutput" for "/inx.INX/ReadOutput".
+func methodName(fullMethod string) string {
+	return path.Base(fullMethod)
+}
+
+// UnaryClientInterceptor returns the interceptor that records the latencies of requests.
+func (m *INXMetrics) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req interface{}, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		m.requestDuration.WithLabelValues(methodName(method)).Observe(time.Since(start).Seconds())
+
+		return err
+	}
+}
+
+// StreamClientInterceptor returns the interceptor that records the latencies of the messages of streams.
+func (m *INXMetrics) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		clientStream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		name := methodName(method)
+
+		return &measuredClientStream{
+			ClientStream: clientStream,
+			recvWait:     m.streamRecvWait.WithLabelValues(name),
+			consume:      m.streamConsume.WithLabelValues(name),
+		}, nil
+	}
+}
+
+// measuredClientStream records the time RecvMsg blocks, and the time between a message was received and the next RecvMsg call.
+type measuredClientStream struct {
+	grpc.ClientStream
+
+	recvWait     prometheus.Observer
+	consume      prometheus.Observer
+	lastReceived time.Time
+}
+
+func (s *measuredClientStream) RecvMsg(msg interface{}) error {
+	start := time.Now()
+	if !s.lastReceived.IsZero() {
+		s.consume.Observe(start.Sub(s.lastReceived).Seconds())
+	}
+
+	err := s.ClientStream.RecvMsg(msg)
+
+	s.lastReceived = time.Now()
+	if err == nil {
+		s.recvWait.Observe(s.lastReceived.Sub(start).Seconds())
+	}
+
+	return err
+}
+
+// Describe implements prometheus.Collector.
+func (m *INXMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.requestDuration.Describe(ch)
+	m.streamRecvWait.Describe(ch)
+	m.streamConsume.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (m *INXMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.requestDuration.Collect(ch)
+	m.streamRecvWait.Collect(ch)
+	m.streamConsume.Collect(ch)
+}
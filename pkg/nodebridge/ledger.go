@@ -5,9 +5,6 @@ import (
 	"errors"
 	"io"
 
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
-
 	inx "github.com/iotaledger/inx/go"
 	iotago "github.com/iotaledger/iota.go/v3"
 )
@@ -25,6 +22,31 @@ type LedgerUpdate struct {
 }
 
 func (n *NodeBridge) ListenToLedgerUpdates(ctx context.Context, startIndex uint32, endIndex uint32, consume func(update *LedgerUpdate) error) error {
+	resumeIndex := startIndex
+	for {
+		err := n.listenToLedgerUpdates(ctx, resumeIndex, endIndex, func(update *LedgerUpdate) error {
+			if err := consume(update); err != nil {
+				return err
+			}
+			resumeIndex = update.MilestoneIndex + 1
+
+			return nil
+		})
+		if endIndex == 0 {
+			// an unbounded stream only ends if the node closed it
+			err = n.streamClosedByNode(ctx, "ListenToLedgerUpdates", err)
+		}
+		if err == nil || !n.handoff(ctx, "ListenToLedgerUpdates", err) {
+			return err
+		}
+
+		if endIndex != 0 && resumeIndex > endIndex {
+			return nil
+		}
+	}
+}
+
+func (n *NodeBridge) listenToLedgerUpdates(ctx context.Context, startIndex uint32, endIndex uint32, consume func(update *LedgerUpdate) error) error {
 	req := &inx.MilestoneRangeRequest{
 		StartMilestoneIndex: startIndex,
 		EndMilestoneIndex:   endIndex,
@@ -32,13 +54,16 @@ func (n *NodeBridge) ListenToLedgerUpdates(ctx context.Context, startIndex uint3
 
 	stream, err := n.client.ListenToLedgerUpdates(ctx, req)
 	if err != nil {
-		return err
+		return newStreamError("ListenToLedgerUpdates", err)
 	}
 
+	catchUp := n.newCatchUpTracker(startIndex, endIndex)
+
 	var update *LedgerUpdate
+	var lastIndex iotago.MilestoneIndex
 	for {
 		payload, err := stream.Recv()
-		if errors.Is(err, io.EOF) || status.Code(err) == codes.Canceled {
+		if errors.Is(err, io.EOF) {
 			break
 		}
 		if ctx.Err() != nil {
@@ -47,7 +72,7 @@ func (n *NodeBridge) ListenToLedgerUpdates(ctx context.Context, startIndex uint3
 			return nil
 		}
 		if err != nil {
-			return err
+			return newStreamError("ListenToLedgerUpdates", err)
 		}
 
 		switch op := payload.GetOp().(type) {
@@ -59,7 +84,7 @@ func (n *NodeBridge) ListenToLedgerUpdates(ctx context.Context, startIndex uint3
 			case inx.LedgerUpdate_Marker_BEGIN:
 				n.LogDebugf("BEGIN batch: %d consumed: %d, created: %d", op.BatchMarker.GetMilestoneIndex(), op.BatchMarker.GetConsumedCount(), op.BatchMarker.GetCreatedCount())
 				if update != nil {
-					return ErrLedgerUpdateTransactionAlreadyInProgress
+					return newStreamError("ListenToLedgerUpdates", ErrLedgerUpdateTransactionAlreadyInProgress)
 				}
 				update = &LedgerUpdate{
 					MilestoneIndex: op.BatchMarker.GetMilestoneIndex(),
@@ -71,31 +96,44 @@ func (n *NodeBridge) ListenToLedgerUpdates(ctx context.Context, startIndex uint3
 			case inx.LedgerUpdate_Marker_END:
 				n.LogDebugf("END batch: %d consumed: %d, created: %d", op.BatchMarker.GetMilestoneIndex(), op.BatchMarker.GetConsumedCount(), op.BatchMarker.GetCreatedCount())
 				if update == nil {
-					return ErrLedgerUpdateInvalidOperation
+					return newStreamError("ListenToLedgerUpdates", ErrLedgerUpdateInvalidOperation)
 				}
 				if uint32(len(update.Consumed)) != op.BatchMarker.GetConsumedCount() ||
 					uint32(len(update.Created)) != op.BatchMarker.GetCreatedCount() ||
 					update.MilestoneIndex != op.BatchMarker.MilestoneIndex {
-					return ErrLedgerUpdateEndedAbruptly
+					return newStreamError("ListenToLedgerUpdates", ErrLedgerUpdateEndedAbruptly)
 				}
 
+				if lastIndex != 0 && update.MilestoneIndex != lastIndex+1 {
+					inconsistency := &InconsistencyError{Kind: InconsistencyLedgerUpdateSequence, Previous: lastIndex, Current: update.MilestoneIndex}
+					n.nodeInconsistent(inconsistency)
+
+					return inconsistency
+				}
+				lastIndex = update.MilestoneIndex
+
 				if err := consume(update); err != nil {
 					return err
 				}
+				n.publishLedgerUpdate(update)
+
+				if catchUp != nil && !catchUp.update(update.MilestoneIndex) {
+					catchUp = nil
+				}
 				update = nil
 			}
 
 		//nolint:nosnakecase // grpc uses underscores
 		case *inx.LedgerUpdate_Consumed:
 			if update == nil {
-				return ErrLedgerUpdateInvalidOperation
+				return newStreamError("ListenToLedgerUpdates", ErrLedgerUpdateInvalidOperation)
 			}
 			update.Consumed = append(update.Consumed, op.Consumed)
 
 		//nolint:nosnakecase // grpc uses underscores
 		case *inx.LedgerUpdate_Created:
 			if update == nil {
-				return ErrLedgerUpdateInvalidOperation
+				return newStreamError("ListenToLedgerUpdates", ErrLedgerUpdateInvalidOperation)
 			}
 			update.Created = append(update.Created, op.Created)
 		}
@@ -103,3 +141,8 @@ func (n *NodeBridge) ListenToLedgerUpdates(ctx context.Context, startIndex uint3
 
 	return nil
 }
+
+// Output returns the output with the given ID and, if it was consumed, the spent information.
+func (n *NodeBridge) Output(ctx context.Context, outputID iotago.OutputID) (*inx.OutputResponse, error) {
+	return n.client.ReadOutput(ctx, inx.NewOutputId(outputID))
+}
@@ -0,0 +1,176 @@
+package nodebridge
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/iotaledger/hive.go/core/logger"
+)
+
+// ErrLedgerUpdateConsumerPanicked is returned by the RecoverLedgerUpdateMiddleware if the consumer panicked.
+var ErrLedgerUpdateConsumerPanicked = errors.New("ledger update consumer panicked")
+
+// LedgerUpdateConsumer consumes ledger updates, e.g. the consume function passed to ListenToLedgerUpdates.
+type LedgerUpdateConsumer = func(update *LedgerUpdate) error
+
+// LedgerUpdateMiddleware wraps a LedgerUpdateConsumer to add cross-cutting behavior, like HTTP middlewares do for handlers.
+// Consumer wrappers of other packages, e.g. Journal.Consumer, can be used as middlewares as well.
+type LedgerUpdateMiddleware = func(next LedgerUpdateConsumer) LedgerUpdateConsumer
+
+// ChainLedgerUpdateMiddlewares wraps the consumer with the given middlewares.
+// The first middleware is the outermost one, i.e. it sees the update first and the result of all others last.
+func ChainLedgerUpdateMiddlewares(consume LedgerUpdateConsumer, middlewares ...LedgerUpdateMiddleware) LedgerUpdateConsumer {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		consume = middlewares[i](consume)
+	}
+
+	return consume
+}
+
+// RecoverLedgerUpdateMiddleware recovers panics of the consumer, logs them with the stack trace
+// and returns ErrLedgerUpdateConsumerPanicked instead, so the app can shut down gracefully.
+func RecoverLedgerUpdateMiddleware(log *logger.Logger) LedgerUpdateMiddleware {
+	return func(next LedgerUpdateConsumer) LedgerUpdateConsumer {
+		return func(update *LedgerUpdate) (err error) {
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					log.Errorf("consuming ledger update of milestone %d panicked: %v\n%s", update.MilestoneIndex, recovered, debug.Stack())
+					err = fmt.Errorf("%w: milestone %d: %v", ErrLedgerUpdateConsumerPanicked, update.MilestoneIndex, recovered)
+				}
+			}()
+
+			return next(update)
+		}
+	}
+}
+
+// LogLedgerUpdateMiddleware logs every consumed ledger update with its size and duration on debug level,
+// and failed ones on error level.
+func LogLedgerUpdateMiddleware(log *logger.Logger) LedgerUpdateMiddleware {
+	return func(next LedgerUpdateConsumer) LedgerUpdateConsumer {
+		return func(update *LedgerUpdate) error {
+			start := time.Now()
+			if err := next(update); err != nil {
+				log.Errorf("consuming ledger update of milestone %d failed: %s", update.MilestoneIndex, err)
+
+				return err
+			}
+			log.Debugf("consumed ledger update of milestone %d, consumed: %d, created: %d, took: %v", update.MilestoneIndex, len(update.Consumed), len(update.Created), time.Since(start).Truncate(time.Millisecond))
+
+			return nil
+		}
+	}
+}
+
+// DedupeLedgerUpdateMiddleware skips ledger updates of milestones that were already consumed successfully,
+// e.g. if a stream is resumed at an earlier index after a handoff to another node.
+// Ledger updates are expected in ascending order, so every update at or below the last consumed index is skipped.
+func DedupeLedgerUpdateMiddleware() LedgerUpdateMiddleware {
+	return func(next LedgerUpdateConsumer) LedgerUpdateConsumer {
+		var lastIndexMutex sync.Mutex
+		var lastIndex uint32
+		var consumedAny bool
+
+		return func(update *LedgerUpdate) error {
+			lastIndexMutex.Lock()
+			defer lastIndexMutex.Unlock()
+
+			if consumedAny && update.MilestoneIndex <= lastIndex {
+				return nil
+			}
+
+			if err := next(update); err != nil {
+				return err
+			}
+			lastIndex = update.MilestoneIndex
+			consumedAny = true
+
+			return nil
+		}
+	}
+}
+
+// LedgerUpdateMetrics counts the ledger updates that pass its middleware.
+// LedgerUpdateMetrics implements prometheus.Collector, so it can be registered directly.
+type LedgerUpdateMetrics struct {
+	// the counters are accessed atomically and must stay 64-bit aligned.
+	consumed        uint64
+	failed          uint64
+	outputsConsumed uint64
+	outputsCreated  uint64
+	durationNanos   int64
+	lastIndex       uint32
+
+	consumedDesc        *prometheus.Desc
+	failedDesc          *prometheus.Desc
+	outputsConsumedDesc *prometheus.Desc
+	outputsCreatedDesc  *prometheus.Desc
+	durationDesc        *prometheus.Desc
+	lastIndexDesc       *prometheus.Desc
+}
+
+// NewLedgerUpdateMetrics creates new LedgerUpdateMetrics. The namespace is used as prefix of the prometheus metric names.
+func NewLedgerUpdateMetrics(namespace string) *LedgerUpdateMetrics {
+	newDesc := func(name string, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "ledger_updates", name), help, nil, nil)
+	}
+
+	return &LedgerUpdateMetrics{
+		consumedDesc:        newDesc("consumed_total", "The total amount of consumed ledger updates."),
+		failedDesc:          newDesc("failed_total", "The total amount of ledger updates the consumer failed on."),
+		outputsConsumedDesc: newDesc("outputs_consumed_total", "The total amount of consumed outputs in the consumed ledger updates."),
+		outputsCreatedDesc:  newDesc("outputs_created_total", "The total amount of created outputs in the consumed ledger updates."),
+		durationDesc:        newDesc("duration_seconds_total", "The total time spent consuming ledger updates."),
+		lastIndexDesc:       newDesc("last_milestone_index", "The milestone index of the last consumed ledger update."),
+	}
+}
+
+// Middleware returns the middleware that records the metrics.
+func (m *LedgerUpdateMetrics) Middleware() LedgerUpdateMiddleware {
+	return func(next LedgerUpdateConsumer) LedgerUpdateConsumer {
+		return func(update *LedgerUpdate) error {
+			start := time.Now()
+			err := next(update)
+			atomic.AddInt64(&m.durationNanos, int64(time.Since(start)))
+
+			if err != nil {
+				atomic.AddUint64(&m.failed, 1)
+
+				return err
+			}
+
+			atomic.AddUint64(&m.consumed, 1)
+			atomic.AddUint64(&m.outputsConsumed, uint64(len(update.Consumed)))
+			atomic.AddUint64(&m.outputsCreated, uint64(len(update.Created)))
+			atomic.StoreUint32(&m.lastIndex, update.MilestoneIndex)
+
+			return nil
+		}
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (m *LedgerUpdateMetrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- m.consumedDesc
+	ch <- m.failedDesc
+	ch <- m.outputsConsumedDesc
+	ch <- m.outputsCreatedDesc
+	ch <- m.durationDesc
+	ch <- m.lastIndexDesc
+}
+
+// Collect implements prometheus.Collector.
+func (m *LedgerUpdateMetrics) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(m.consumedDesc, prometheus.CounterValue, float64(atomic.LoadUint64(&m.consumed)))
+	ch <- prometheus.MustNewConstMetric(m.failedDesc, prometheus.CounterValue, float64(atomic.LoadUint64(&m.failed)))
+	ch <- prometheus.MustNewConstMetric(m.outputsConsumedDesc, prometheus.CounterValue, float64(atomic.LoadUint64(&m.outputsConsumed)))
+	ch <- prometheus.MustNewConstMetric(m.outputsCreatedDesc, prometheus.CounterValue, float64(atomic.LoadUint64(&m.outputsCreated)))
+	ch <- prometheus.MustNewConstMetric(m.durationDesc, prometheus.CounterValue, time.Duration(atomic.LoadInt64(&m.durationNanos)).Seconds())
+	ch <- prometheus.MustNewConstMetric(m.lastIndexDesc, prometheus.GaugeValue, float64(atomic.LoadUint32(&m.lastIndex)))
+}
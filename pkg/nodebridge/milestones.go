@@ -5,9 +5,6 @@ import (
 	"errors"
 	"io"
 
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
-
 	"github.com/iotaledger/hive.go/serializer/v2"
 	inx "github.com/iotaledger/inx/go"
 	iotago "github.com/iotaledger/iota.go/v3"
@@ -87,18 +84,17 @@ func (n *NodeBridge) MilestoneConeMetadata(ctx context.Context, cancel context.C
 
 	stream, err := n.client.ReadMilestoneConeMetadata(ctx, req)
 	if err != nil {
-		return err
+		return newStreamError("ReadMilestoneConeMetadata", err)
 	}
 
 	for {
 		metadata, err := stream.Recv()
 		if err != nil {
-			if errors.Is(err, io.EOF) || status.Code(err) == codes.Canceled {
+			if errors.Is(err, io.EOF) || ctx.Err() != nil {
 				break
 			}
-			n.LogErrorf("ReadMilestoneConeMetadata: %s", err.Error())
 
-			break
+			return newStreamError("ReadMilestoneConeMetadata", err)
 		}
 		if ctx.Err() != nil {
 			break
@@ -107,6 +103,5 @@ func (n *NodeBridge) MilestoneConeMetadata(ctx context.Context, cancel context.C
 		consumer(metadata)
 	}
 
-	//nolint:nilerr // false positive
 	return nil
 }
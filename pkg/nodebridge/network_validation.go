@@ -0,0 +1,100 @@
+package nodebridge
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// ErrNetworkMismatch is returned if the node does not belong to the expected network.
+var ErrNetworkMismatch = errors.New("network mismatch")
+
+// NetworkMismatch describes a protocol parameter of the node that does not match the expectation.
+type NetworkMismatch struct {
+	// Parameter is the name of the protocol parameter, e.g. "networkName".
+	Parameter string
+	// Expected is the configured value.
+	Expected string
+	// Actual is the value reported by the node.
+	Actual string
+}
+
+func (m *NetworkMismatch) Error() string {
+	return fmt.Sprintf("%s: %s is \"%s\", expected \"%s\"", ErrNetworkMismatch, m.Parameter, m.Actual, m.Expected)
+}
+
+func (m *NetworkMismatch) Unwrap() error {
+	return ErrNetworkMismatch
+}
+
+func NetworkMismatchCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(mismatch *NetworkMismatch))(params[0].(*NetworkMismatch))
+}
+
+// WithTargetBech32HRP checks if the bech32 HRP of the node is equal to the given targetBech32HRP.
+// If targetBech32HRP is empty, the check is disabled.
+func WithTargetBech32HRP(targetBech32HRP iotago.NetworkPrefix) options.Option[NodeBridge] {
+	return func(n *NodeBridge) {
+		n.targetBech32HRP = targetBech32HRP
+	}
+}
+
+// WithTargetProtocolVersion checks if the protocol version of the node is equal to the given targetProtocolVersion.
+// If targetProtocolVersion is 0, the check is disabled.
+func WithTargetProtocolVersion(targetProtocolVersion byte) options.Option[NodeBridge] {
+	return func(n *NodeBridge) {
+		n.targetProtocolVersion = targetProtocolVersion
+	}
+}
+
+// WithNetworkMismatchTolerated lets NewNodeBridge succeed even if the node does not match the target network.
+// The mismatches are logged as errors and reported by NetworkMismatches instead,
+// and later mismatches, e.g. after a protocol upgrade, trigger the NetworkMismatch event.
+func WithNetworkMismatchTolerated() options.Option[NodeBridge] {
+	return func(n *NodeBridge) {
+		n.networkMismatchTolerated = true
+	}
+}
+
+// networkMismatches compares the protocol parameters against the configured targets.
+func (n *NodeBridge) networkMismatches(protoParams *iotago.ProtocolParameters) []*NetworkMismatch {
+	mismatches := make([]*NetworkMismatch, 0)
+
+	if n.targetNetworkName != "" && n.targetNetworkName != protoParams.NetworkName {
+		mismatches = append(mismatches, &NetworkMismatch{Parameter: "networkName", Expected: n.targetNetworkName, Actual: protoParams.NetworkName})
+	}
+	if n.targetBech32HRP != "" && n.targetBech32HRP != protoParams.Bech32HRP {
+		mismatches = append(mismatches, &NetworkMismatch{Parameter: "bech32HRP", Expected: string(n.targetBech32HRP), Actual: string(protoParams.Bech32HRP)})
+	}
+	if n.targetProtocolVersion != 0 && n.targetProtocolVersion != protoParams.Version {
+		mismatches = append(mismatches, &NetworkMismatch{Parameter: "protocolVersion", Expected: fmt.Sprint(n.targetProtocolVersion), Actual: fmt.Sprint(protoParams.Version)})
+	}
+
+	return mismatches
+}
+
+// validateNetwork checks the protocol parameters of the node on startup.
+func (n *NodeBridge) validateNetwork(protoParams *iotago.ProtocolParameters) error {
+	mismatches := n.networkMismatches(protoParams)
+	if len(mismatches) == 0 {
+		return nil
+	}
+
+	if !n.networkMismatchTolerated {
+		return mismatches[0]
+	}
+
+	for _, mismatch := range mismatches {
+		n.LogErrorf("!!! The node does not belong to the expected network: %s !!!", mismatch.Error())
+	}
+
+	return nil
+}
+
+// NetworkMismatches returns the protocol parameters of the node that don't match the configured targets.
+func (n *NodeBridge) NetworkMismatches() []*NetworkMismatch {
+	return n.networkMismatches(n.ProtocolParameters())
+}
@@ -3,18 +3,20 @@ package nodebridge
 import (
 	"context"
 	"errors"
-	"fmt"
 	"sync"
 	"time"
 
 	grpcretry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
 	grpcprometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/iotaledger/hive.go/core/events"
 	"github.com/iotaledger/hive.go/core/generics/options"
 	"github.com/iotaledger/hive.go/core/logger"
+	"github.com/iotaledger/inx-app/pkg/pubsub"
+	"github.com/iotaledger/inx-app/pkg/sampledlog"
 	inx "github.com/iotaledger/inx/go"
 	iotago "github.com/iotaledger/iota.go/v3"
 	"github.com/iotaledger/iota.go/v3/nodeclient"
@@ -27,23 +29,44 @@ const (
 type NodeBridge struct {
 	// the logger used to log events.
 	*logger.WrappedLogger
+	// the logger used in the stream hot paths, so bursts of errors are rate limited.
+	sampledLogger *sampledlog.Logger
 
-	targetNetworkName string
+	targetNetworkName        string
+	targetBech32HRP          iotago.NetworkPrefix
+	targetProtocolVersion    byte
+	networkMismatchTolerated bool
 
 	conn       *grpc.ClientConn
 	client     inx.INXClient
 	NodeConfig *inx.NodeConfiguration
 
 	Events *Events
+	pubSub *pubsub.Bus
+
+	// the latencies of the INX methods are recorded here, if set.
+	inxMetrics *INXMetrics
+
+	// the maximum time the streams are parked while the node is not reachable, 0 disables the handoff.
+	streamHandoffMaxDowntime time.Duration
 
 	nodeStatusMutex    sync.RWMutex
 	nodeStatus         *inx.NodeStatus
 	protocolParameters *iotago.ProtocolParameters
+
+	catchUpProgressMutex sync.RWMutex
+	catchUpProgress      *CatchUpProgress
 }
 
 type Events struct {
 	LatestMilestoneChanged    *events.Event
 	ConfirmedMilestoneChanged *events.Event
+	CatchUpProgressUpdated    *events.Event
+	// NodeInconsistent is triggered with the *InconsistencyError if the node served inconsistent data.
+	NodeInconsistent *events.Event
+	// NetworkMismatch is triggered with the *NetworkMismatch if the protocol parameters of the node changed
+	// and don't match the configured targets anymore.
+	NetworkMismatch *events.Event
 }
 
 func MilestoneCaller(handler interface{}, params ...interface{}) {
@@ -60,9 +83,32 @@ func WithTargetNetworkName(targetNetworkName string) options.Option[NodeBridge]
 }
 
 func NewNodeBridge(ctx context.Context, address string, maxConnectionAttempts uint, log *logger.Logger, opts ...options.Option[NodeBridge]) (*NodeBridge, error) {
+	sampledLogger := sampledlog.New(log)
+
+	// the options are applied before connecting, the interceptors of the connection depend on them
+	nb := options.Apply(&NodeBridge{
+		WrappedLogger:     logger.NewWrappedLogger(log),
+		sampledLogger:     sampledLogger,
+		targetNetworkName: "",
+		Events: &Events{
+			LatestMilestoneChanged:    events.NewEvent(MilestoneCaller),
+			ConfirmedMilestoneChanged: events.NewEvent(MilestoneCaller),
+			CatchUpProgressUpdated:    events.NewEvent(CatchUpProgressCaller),
+			NodeInconsistent:          events.NewEvent(InconsistencyErrorCaller),
+			NetworkMismatch:           events.NewEvent(NetworkMismatchCaller),
+		},
+	}, opts)
+
+	unaryInterceptors := []grpc.UnaryClientInterceptor{grpcretry.UnaryClientInterceptor(), grpcprometheus.UnaryClientInterceptor}
+	streamInterceptors := []grpc.StreamClientInterceptor{grpcprometheus.StreamClientInterceptor}
+	if nb.inxMetrics != nil {
+		unaryInterceptors = append(unaryInterceptors, nb.inxMetrics.UnaryClientInterceptor())
+		streamInterceptors = append(streamInterceptors, nb.inxMetrics.StreamClientInterceptor())
+	}
+
 	conn, err := grpc.Dial(address,
-		grpc.WithChainUnaryInterceptor(grpcretry.UnaryClientInterceptor(), grpcprometheus.UnaryClientInterceptor),
-		grpc.WithStreamInterceptor(grpcprometheus.StreamClientInterceptor),
+		grpc.WithChainUnaryInterceptor(unaryInterceptors...),
+		grpc.WithChainStreamInterceptor(streamInterceptors...),
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 	)
 	if err != nil {
@@ -70,7 +116,7 @@ func NewNodeBridge(ctx context.Context, address string, maxConnectionAttempts ui
 	}
 	client := inx.NewINXClient(conn)
 	retryBackoff := func(_ uint) time.Duration {
-		log.Info("> retrying INX connection to node ...")
+		sampledLogger.LogInfof("retryINXConnection", "> retrying INX connection to node ...")
 		return 1 * time.Second
 	}
 
@@ -91,25 +137,15 @@ func NewNodeBridge(ctx context.Context, address string, maxConnectionAttempts ui
 		return nil, err
 	}
 
-	nb := options.Apply(&NodeBridge{
-		WrappedLogger:     logger.NewWrappedLogger(log),
-		targetNetworkName: "",
-		conn:              conn,
-		client:            client,
-		NodeConfig:        nodeConfig,
-		Events: &Events{
-			LatestMilestoneChanged:    events.NewEvent(MilestoneCaller),
-			ConfirmedMilestoneChanged: events.NewEvent(MilestoneCaller),
-		},
-		nodeStatus:         nodeStatus,
-		protocolParameters: protoParams,
-	}, opts)
+	nb.conn = conn
+	nb.client = client
+	nb.NodeConfig = nodeConfig
+	nb.nodeStatus = nodeStatus
+	nb.protocolParameters = protoParams
 
-	if nb.targetNetworkName != "" {
-		// we need to check for the correct target network name
-		if nb.targetNetworkName != protoParams.NetworkName {
-			return nil, fmt.Errorf("network name mismatch, networkName: \"%s\", targetNetworkName: \"%s\"", protoParams.NetworkName, nb.targetNetworkName)
-		}
+	// we need to check for the correct target network
+	if err := nb.validateNetwork(protoParams); err != nil {
+		return nil, err
 	}
 
 	return nb, nil
@@ -120,8 +156,17 @@ func (n *NodeBridge) Run(ctx context.Context) {
 	defer cancel()
 
 	go func() {
-		if err := n.listenToNodeStatus(c, cancel); err != nil {
-			n.LogErrorf("Error listening to node status: %s", err)
+		defer cancel()
+
+		for {
+			err := n.streamClosedByNode(c, "ListenToNodeStatus", n.listenToNodeStatus(c))
+			if err == nil || !n.handoff(c, "ListenToNodeStatus", err) {
+				if err != nil {
+					n.sampledLogger.LogErrorf("listenToNodeStatus", "Error listening to node status: %s", err)
+				}
+
+				return
+			}
 		}
 	}()
 
@@ -133,6 +178,11 @@ func (n *NodeBridge) Client() inx.INXClient {
 	return n.client
 }
 
+// ConnectionState returns the state of the gRPC connection to the node.
+func (n *NodeBridge) ConnectionState() connectivity.State {
+	return n.conn.GetState()
+}
+
 // Indexer returns the IndexerClient.
 // Returns ErrIndexerPluginNotAvailable if the current node does not support the plugin.
 // It retries every second until the given context is done.
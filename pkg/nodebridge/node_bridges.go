@@ -0,0 +1,209 @@
+package nodebridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrNetworkAlreadyRegistered is returned when a NodeBridge for the same network has already been added.
+	ErrNetworkAlreadyRegistered = errors.New("network is already registered")
+	// ErrNetworkNotRegistered is returned when no NodeBridge for the network was added.
+	ErrNetworkNotRegistered = errors.New("network is not registered")
+)
+
+// NamespacedRoute returns the API route of the given network.
+// It allows a single app to serve the same route for multiple networks on one bind address.
+func NamespacedRoute(route string, networkName string) string {
+	return path.Join(route, networkName)
+}
+
+type managedNodeBridge struct {
+	nodeBridge *NodeBridge
+	cancel     context.CancelFunc
+	done       chan struct{}
+}
+
+// NodeBridges holds one NodeBridge per network, e.g. to serve the IOTA and the Shimmer network from the same app.
+// Every NodeBridge keeps its own protocol parameters and runs independently of the others.
+type NodeBridges struct {
+	ctx context.Context //nolint:containedctx // the bridges are started with the context given in Run
+
+	bridgesMutex sync.RWMutex
+	bridges      map[string]*managedNodeBridge
+
+	// is called after a NodeBridge stopped.
+	onStopped func(networkName string)
+}
+
+// NewNodeBridges creates a new, empty set of NodeBridges.
+// The optional onStopped callback is called with the network name after a NodeBridge stopped.
+func NewNodeBridges(onStopped func(networkName string)) *NodeBridges {
+	return &NodeBridges{
+		bridges:   make(map[string]*managedNodeBridge),
+		onStopped: onStopped,
+	}
+}
+
+// Add adds a NodeBridge for the network the node operates on.
+// If the set is already running, the NodeBridge is started immediately.
+func (b *NodeBridges) Add(nodeBridge *NodeBridge) error {
+	networkName := nodeBridge.ProtocolParameters().NetworkName
+
+	b.bridgesMutex.Lock()
+	defer b.bridgesMutex.Unlock()
+
+	if _, exists := b.bridges[networkName]; exists {
+		return fmt.Errorf("%w: %s", ErrNetworkAlreadyRegistered, networkName)
+	}
+
+	managed := &managedNodeBridge{nodeBridge: nodeBridge}
+	b.bridges[networkName] = managed
+
+	if b.ctx != nil {
+		b.start(networkName, managed)
+	}
+
+	return nil
+}
+
+// Remove stops the NodeBridge of the given network and removes it from the set.
+func (b *NodeBridges) Remove(networkName string) error {
+	b.bridgesMutex.Lock()
+	managed, exists := b.bridges[networkName]
+	delete(b.bridges, networkName)
+	b.bridgesMutex.Unlock()
+
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrNetworkNotRegistered, networkName)
+	}
+
+	if managed.cancel != nil {
+		managed.cancel()
+		<-managed.done
+	}
+
+	return nil
+}
+
+// NodeBridge returns the NodeBridge of the given network.
+func (b *NodeBridges) NodeBridge(networkName string) (*NodeBridge, error) {
+	b.bridgesMutex.RLock()
+	defer b.bridgesMutex.RUnlock()
+
+	managed, exists := b.bridges[networkName]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrNetworkNotRegistered, networkName)
+	}
+
+	return managed.nodeBridge, nil
+}
+
+// NodeBridgeByBech32HRP returns the NodeBridge of the network that uses the given bech32 human-readable part.
+func (b *NodeBridges) NodeBridgeByBech32HRP(hrp iotago.NetworkPrefix) (*NodeBridge, error) {
+	b.bridgesMutex.RLock()
+	defer b.bridgesMutex.RUnlock()
+
+	for _, managed := range b.bridges {
+		if managed.nodeBridge.ProtocolParameters().Bech32HRP == hrp {
+			return managed.nodeBridge, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: bech32 HRP %s", ErrNetworkNotRegistered, hrp)
+}
+
+// NetworkNames returns the sorted names of all networks in the set.
+func (b *NodeBridges) NetworkNames() []string {
+	b.bridgesMutex.RLock()
+	defer b.bridgesMutex.RUnlock()
+
+	networkNames := make([]string, 0, len(b.bridges))
+	for networkName := range b.bridges {
+		networkNames = append(networkNames, networkName)
+	}
+	sort.Strings(networkNames)
+
+	return networkNames
+}
+
+// RegisterAPIRoute registers the namespaced route of every network on its node.
+func (b *NodeBridges) RegisterAPIRoute(ctx context.Context, route string, bindAddress string) error {
+	for _, networkName := range b.NetworkNames() {
+		nodeBridge, err := b.NodeBridge(networkName)
+		if err != nil {
+			continue
+		}
+
+		if err := nodeBridge.RegisterAPIRoute(ctx, NamespacedRoute(route, networkName), bindAddress); err != nil {
+			return fmt.Errorf("registering API route for network %s failed: %w", networkName, err)
+		}
+	}
+
+	return nil
+}
+
+// UnregisterAPIRoute unregisters the namespaced route of every network from its node.
+// All networks are tried, even if unregistering fails for one of them. The first error is returned.
+func (b *NodeBridges) UnregisterAPIRoute(ctx context.Context, route string) error {
+	var firstErr error
+	for _, networkName := range b.NetworkNames() {
+		nodeBridge, err := b.NodeBridge(networkName)
+		if err != nil {
+			continue
+		}
+
+		if err := nodeBridge.UnregisterAPIRoute(ctx, NamespacedRoute(route, networkName)); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("unregistering API route for network %s failed: %w", networkName, err)
+		}
+	}
+
+	return firstErr
+}
+
+func (b *NodeBridges) start(networkName string, managed *managedNodeBridge) {
+	ctx, cancel := context.WithCancel(b.ctx)
+	managed.cancel = cancel
+	managed.done = make(chan struct{})
+
+	go func() {
+		defer close(managed.done)
+		defer cancel()
+
+		managed.nodeBridge.Run(ctx)
+
+		if b.onStopped != nil {
+			b.onStopped(networkName)
+		}
+	}()
+}
+
+// Run runs all NodeBridges until the given context is done.
+// A NodeBridge that stops, e.g. because the connection to its node dropped, does not affect the others.
+func (b *NodeBridges) Run(ctx context.Context) {
+	b.bridgesMutex.Lock()
+	b.ctx = ctx
+	for networkName, managed := range b.bridges {
+		b.start(networkName, managed)
+	}
+	b.bridgesMutex.Unlock()
+
+	<-ctx.Done()
+
+	b.bridgesMutex.RLock()
+	running := make([]*managedNodeBridge, 0, len(b.bridges))
+	for _, managed := range b.bridges {
+		running = append(running, managed)
+	}
+	b.bridgesMutex.RUnlock()
+
+	for _, managed := range running {
+		<-managed.done
+	}
+}
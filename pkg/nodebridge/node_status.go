@@ -6,9 +6,6 @@ import (
 	"fmt"
 	"io"
 
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
-
 	"github.com/iotaledger/hive.go/serializer/v2"
 	inx "github.com/iotaledger/inx/go"
 	iotago "github.com/iotaledger/iota.go/v3"
@@ -57,35 +54,35 @@ func protocolParametersFromRaw(params *inx.RawProtocolParameters) (*iotago.Proto
 	return protoParams, nil
 }
 
-func (n *NodeBridge) listenToNodeStatus(ctx context.Context, cancel context.CancelFunc) error {
-	defer cancel()
-
+func (n *NodeBridge) listenToNodeStatus(ctx context.Context) error {
 	stream, err := n.client.ListenToNodeStatus(ctx, &inx.NodeStatusRequest{CooldownInMilliseconds: ListenToNodeStatusCooldownInMilliseconds})
 	if err != nil {
-		return err
+		return newStreamError("ListenToNodeStatus", err)
 	}
 
 	for {
 		nodeStatus, err := stream.Recv()
 		if err != nil {
-			if errors.Is(err, io.EOF) || status.Code(err) == codes.Canceled {
+			if errors.Is(err, io.EOF) || ctx.Err() != nil {
 				break
 			}
-			n.LogErrorf("listenToNodeStatus: %s", err.Error())
 
-			break
+			return newStreamError("ListenToNodeStatus", err)
 		}
 		if ctx.Err() != nil {
 			break
 		}
 
 		if err := n.processNodeStatus(nodeStatus); err != nil {
-			n.LogErrorf("processNodeStatus: %s", err.Error())
-			break
+			if errors.Is(err, ErrNodeInconsistent) {
+				// fatal, retrying would not help
+				return err
+			}
+
+			return &StreamError{Stream: "ListenToNodeStatus", Class: ErrStreamProtocolMismatch, Err: err}
 		}
 	}
 
-	//nolint:nilerr // false positive
 	return nil
 }
 
@@ -94,10 +91,18 @@ func (n *NodeBridge) processNodeStatus(nodeStatus *inx.NodeStatus) error {
 	var latestMilestoneChanged bool
 	var confirmedMilestoneChanged bool
 
+	var inconsistency *InconsistencyError
+	var protocolParametersChanged bool
+
 	updateStatus := func() error {
 		n.nodeStatusMutex.Lock()
 		defer n.nodeStatusMutex.Unlock()
 
+		if inconsistency = checkNodeStatusConsistency(n.nodeStatus, nodeStatus); inconsistency != nil {
+			// keep the last consistent status
+			return inconsistency
+		}
+
 		if nodeStatus.GetLatestMilestone().GetMilestoneInfo().GetMilestoneIndex() > n.nodeStatus.GetLatestMilestone().GetMilestoneInfo().GetMilestoneIndex() {
 			latestMilestoneChanged = true
 		}
@@ -111,19 +116,34 @@ func (n *NodeBridge) processNodeStatus(nodeStatus *inx.NodeStatus) error {
 		if err != nil {
 			return err
 		}
+		protocolParametersChanged = protocolParams.NetworkName != n.protocolParameters.NetworkName ||
+			protocolParams.Bech32HRP != n.protocolParameters.Bech32HRP ||
+			protocolParams.Version != n.protocolParameters.Version
 		n.protocolParameters = protocolParams
 
 		return nil
 	}
 
 	if err := updateStatus(); err != nil {
+		if inconsistency != nil {
+			n.nodeInconsistent(inconsistency)
+		}
+
 		return err
 	}
 
+	if protocolParametersChanged {
+		for _, mismatch := range n.NetworkMismatches() {
+			n.LogErrorf("!!! The node does not belong to the expected network anymore: %s !!!", mismatch.Error())
+			n.Events.NetworkMismatch.Trigger(mismatch)
+		}
+	}
+
 	if latestMilestoneChanged {
 		milestone, err := milestoneFromINXMilestone(nodeStatus.GetLatestMilestone())
 		if err == nil {
 			n.Events.LatestMilestoneChanged.Trigger(milestone)
+			n.publish(TopicLatestMilestone, milestone)
 		}
 	}
 
@@ -131,6 +151,7 @@ func (n *NodeBridge) processNodeStatus(nodeStatus *inx.NodeStatus) error {
 		milestone, err := milestoneFromINXMilestone(nodeStatus.GetConfirmedMilestone())
 		if err == nil {
 			n.Events.ConfirmedMilestoneChanged.Trigger(milestone)
+			n.publish(TopicConfirmedMilestone, milestone)
 		}
 	}
 
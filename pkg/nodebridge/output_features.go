@@ -0,0 +1,123 @@
+package nodebridge
+
+import (
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// immutableFeatureSet returns the immutable features of chain outputs, or nil for other outputs.
+func immutableFeatureSet(output iotago.Output) iotago.FeatureSet {
+	chainOutput, ok := output.(iotago.ChainConstrainedOutput)
+	if !ok {
+		return nil
+	}
+
+	return chainOutput.ImmutableFeatureSet()
+}
+
+// OutputSender returns the address of the sender feature of the output.
+func OutputSender(output iotago.Output) (iotago.Address, bool) {
+	senderFeature := output.FeatureSet().SenderFeature()
+	if senderFeature == nil {
+		return nil, false
+	}
+
+	return senderFeature.Address, true
+}
+
+// OutputIssuer returns the address of the immutable issuer feature of alias and NFT outputs.
+func OutputIssuer(output iotago.Output) (iotago.Address, bool) {
+	issuerFeature := immutableFeatureSet(output).IssuerFeature()
+	if issuerFeature == nil {
+		return nil, false
+	}
+
+	return issuerFeature.Address, true
+}
+
+// OutputMetadata returns the data of the metadata feature of the output.
+func OutputMetadata(output iotago.Output) ([]byte, bool) {
+	metadataFeature := output.FeatureSet().MetadataFeature()
+	if metadataFeature == nil {
+		return nil, false
+	}
+
+	return metadataFeature.Data, true
+}
+
+// OutputImmutableMetadata returns the data of the immutable metadata feature of alias and NFT outputs.
+func OutputImmutableMetadata(output iotago.Output) ([]byte, bool) {
+	metadataFeature := immutableFeatureSet(output).MetadataFeature()
+	if metadataFeature == nil {
+		return nil, false
+	}
+
+	return metadataFeature.Data, true
+}
+
+// OutputTag returns the tag of the tag feature of the output.
+func OutputTag(output iotago.Output) ([]byte, bool) {
+	tagFeature := output.FeatureSet().TagFeature()
+	if tagFeature == nil {
+		return nil, false
+	}
+
+	return tagFeature.Tag, true
+}
+
+// OutputAddressUnlock returns the address of the address unlock condition of the output.
+func OutputAddressUnlock(output iotago.Output) (iotago.Address, bool) {
+	addressUnlock := output.UnlockConditionSet().Address()
+	if addressUnlock == nil {
+		return nil, false
+	}
+
+	return addressUnlock.Address, true
+}
+
+// OutputOwner returns the address that owns the base tokens of the output,
+// i.e. the address unlock, the state controller or the immutable alias.
+func OutputOwner(output iotago.Output) (iotago.Address, bool) {
+	if address, ok := OutputAddressUnlock(output); ok {
+		return address, true
+	}
+
+	unlockConditions := output.UnlockConditionSet()
+	if stateControllerUnlock := unlockConditions.StateControllerAddress(); stateControllerUnlock != nil {
+		return stateControllerUnlock.Address, true
+	}
+	if immutableAliasUnlock := unlockConditions.ImmutableAlias(); immutableAliasUnlock != nil {
+		return immutableAliasUnlock.Address, true
+	}
+
+	return nil, false
+}
+
+// OutputStorageDepositReturn returns the return address and the amount of the storage deposit return unlock condition of the output.
+func OutputStorageDepositReturn(output iotago.Output) (iotago.Address, uint64, bool) {
+	storageDepositReturn := output.UnlockConditionSet().StorageDepositReturn()
+	if storageDepositReturn == nil {
+		return nil, 0, false
+	}
+
+	return storageDepositReturn.ReturnAddress, storageDepositReturn.Amount, true
+}
+
+// OutputTimelock returns the unix time until which the output is timelocked.
+func OutputTimelock(output iotago.Output) (uint32, bool) {
+	timelock := output.UnlockConditionSet().Timelock()
+	if timelock == nil {
+		return 0, false
+	}
+
+	return timelock.UnixTime, true
+}
+
+// OutputExpiration returns the return address and the unix time of the expiration unlock condition of the output.
+func OutputExpiration(output iotago.Output) (iotago.Address, uint32, bool) {
+	expiration := output.UnlockConditionSet().Expiration()
+	if expiration == nil {
+		return nil, 0, false
+	}
+
+	return expiration.ReturnAddress, expiration.UnixTime, true
+}
@@ -0,0 +1,115 @@
+package nodebridge
+
+import (
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/serializer/v2"
+	"github.com/iotaledger/inx-app/pkg/pubsub"
+	inx "github.com/iotaledger/inx/go"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// TopicLatestMilestone is the topic a *Milestone is published on if the latest milestone changed.
+	TopicLatestMilestone = pubsub.Topic("milestone", "latest")
+	// TopicConfirmedMilestone is the topic a *Milestone is published on if the confirmed milestone changed.
+	TopicConfirmedMilestone = pubsub.Topic("milestone", "confirmed")
+	// TopicLedgerUpdate is the topic a *LedgerUpdate is published on after it was consumed.
+	TopicLedgerUpdate = pubsub.Topic("ledger", "update")
+)
+
+// TopicOutputCreated returns the topic an *inx.LedgerOutput is published on if an output for the given bech32 address was created.
+func TopicOutputCreated(bech32Address string) string {
+	return pubsub.Topic("ledger", "output", "created", bech32Address)
+}
+
+// TopicOutputConsumed returns the topic an *inx.LedgerSpent is published on if an output for the given bech32 address was consumed.
+func TopicOutputConsumed(bech32Address string) string {
+	return pubsub.Topic("ledger", "output", "consumed", bech32Address)
+}
+
+// WithPubSub publishes milestone changes and consumed ledger updates on the given bus.
+func WithPubSub(bus *pubsub.Bus) options.Option[NodeBridge] {
+	return func(n *NodeBridge) {
+		n.pubSub = bus
+	}
+}
+
+func (n *NodeBridge) publish(topic string, payload any) {
+	if n.pubSub == nil {
+		return
+	}
+
+	n.pubSub.Publish(topic, payload)
+}
+
+func (n *NodeBridge) publishLedgerUpdate(update *LedgerUpdate) {
+	if n.pubSub == nil {
+		return
+	}
+
+	hrp := n.ProtocolParameters().Bech32HRP
+
+	for _, spent := range update.Consumed {
+		addresses, err := ledgerOutputAddresses(spent.GetOutput())
+		if err != nil {
+			n.sampledLogger.LogWarnf("publishLedgerUpdate", "publishing consumed output failed: %s", err.Error())
+
+			continue
+		}
+
+		for _, address := range addresses {
+			n.pubSub.Publish(TopicOutputConsumed(address.Bech32(hrp)), spent)
+		}
+	}
+
+	for _, created := range update.Created {
+		addresses, err := ledgerOutputAddresses(created)
+		if err != nil {
+			n.sampledLogger.LogWarnf("publishLedgerUpdate", "publishing created output failed: %s", err.Error())
+
+			continue
+		}
+
+		for _, address := range addresses {
+			n.pubSub.Publish(TopicOutputCreated(address.Bech32(hrp)), created)
+		}
+	}
+
+	n.pubSub.Publish(TopicLedgerUpdate, update)
+}
+
+// ledgerOutputAddresses returns all addresses that are able to unlock the given output.
+func ledgerOutputAddresses(ledgerOutput *inx.LedgerOutput) ([]iotago.Address, error) {
+	output, err := ledgerOutput.UnwrapOutput(serializer.DeSeriModeNoValidation, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return OutputAddresses(output), nil
+}
+
+// OutputAddresses returns all addresses that are able to unlock the given output.
+func OutputAddresses(output iotago.Output) []iotago.Address {
+	unlockConditions := output.UnlockConditionSet()
+
+	addresses := make([]iotago.Address, 0, 2)
+	if address, ok := OutputAddressUnlock(output); ok {
+		addresses = append(addresses, address)
+	}
+	if stateControllerUnlock := unlockConditions.StateControllerAddress(); stateControllerUnlock != nil {
+		addresses = append(addresses, stateControllerUnlock.Address)
+	}
+	if governorUnlock := unlockConditions.GovernorAddress(); governorUnlock != nil {
+		addresses = append(addresses, governorUnlock.Address)
+	}
+	if immutableAliasUnlock := unlockConditions.ImmutableAlias(); immutableAliasUnlock != nil {
+		addresses = append(addresses, immutableAliasUnlock.Address)
+	}
+
+	// the state controller and the governor are often the same address
+	if len(addresses) == 2 && addresses[0].Equal(addresses[1]) {
+		addresses = addresses[:1]
+	}
+
+	return addresses
+}
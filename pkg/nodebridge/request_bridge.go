@@ -0,0 +1,131 @@
+package nodebridge
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	inx "github.com/iotaledger/inx/go"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/nodeclient"
+)
+
+// the key of the RequestBridge in the echo context.
+const requestBridgeContextKey = "inx-app.requestBridge"
+
+// RequestBridge is a view of the NodeBridge that is scoped to a single HTTP request.
+// All node calls are bound to the context of the request, so they are aborted once the request is done,
+// its deadline is exceeded or the client disconnected, and they carry the values of the request context, like the trace context.
+type RequestBridge struct {
+	nodeBridge *NodeBridge
+	ctx        context.Context
+}
+
+// NewRequestBridge creates a new RequestBridge that binds all node calls to the given context.
+func NewRequestBridge(ctx context.Context, nodeBridge *NodeBridge) *RequestBridge {
+	return &RequestBridge{
+		nodeBridge: nodeBridge,
+		ctx:        ctx,
+	}
+}
+
+// RequestBridgeMiddleware injects a RequestBridge into the echo context of every request, it can be accessed with RequestBridgeFromContext.
+// If timeout is greater than zero, the context of the request is limited to it, so handlers can't make unbounded node calls.
+func RequestBridgeMiddleware(nodeBridge *NodeBridge, timeout time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+
+				c.SetRequest(c.Request().WithContext(ctx))
+			}
+
+			c.Set(requestBridgeContextKey, NewRequestBridge(ctx, nodeBridge))
+
+			return next(c)
+		}
+	}
+}
+
+// RequestBridgeFromContext returns the RequestBridge injected by the RequestBridgeMiddleware.
+// It returns false if the middleware is not used for the route.
+func RequestBridgeFromContext(c echo.Context) (*RequestBridge, bool) {
+	requestBridge, ok := c.Get(requestBridgeContextKey).(*RequestBridge)
+
+	return requestBridge, ok
+}
+
+// Context returns the context all node calls are bound to.
+func (r *RequestBridge) Context() context.Context {
+	return r.ctx
+}
+
+// ProtocolParameters returns the current protocol parameters of the node.
+func (r *RequestBridge) ProtocolParameters() *iotago.ProtocolParameters {
+	return r.nodeBridge.ProtocolParameters()
+}
+
+// NodeStatus returns the last known status of the node.
+func (r *RequestBridge) NodeStatus() *inx.NodeStatus {
+	return r.nodeBridge.NodeStatus()
+}
+
+// LatestMilestoneIndex returns the index of the latest milestone.
+func (r *RequestBridge) LatestMilestoneIndex() uint32 {
+	return r.nodeBridge.LatestMilestoneIndex()
+}
+
+// ConfirmedMilestoneIndex returns the index of the confirmed milestone.
+func (r *RequestBridge) ConfirmedMilestoneIndex() uint32 {
+	return r.nodeBridge.ConfirmedMilestoneIndex()
+}
+
+// SubmitBlock submits the block to the node.
+func (r *RequestBridge) SubmitBlock(block *iotago.Block) (iotago.BlockID, error) {
+	return r.nodeBridge.SubmitBlock(r.ctx, block)
+}
+
+// Block returns the block with the given ID.
+func (r *RequestBridge) Block(blockID iotago.BlockID) (*iotago.Block, error) {
+	return r.nodeBridge.Block(r.ctx, blockID)
+}
+
+// BlockMetadata returns the metadata of the block with the given ID.
+func (r *RequestBridge) BlockMetadata(blockID iotago.BlockID) (*inx.BlockMetadata, error) {
+	return r.nodeBridge.BlockMetadata(r.ctx, blockID)
+}
+
+// Output returns the output with the given ID and, if it was consumed, the spent information.
+func (r *RequestBridge) Output(outputID iotago.OutputID) (*inx.OutputResponse, error) {
+	return r.nodeBridge.Output(r.ctx, outputID)
+}
+
+// Milestone returns the milestone with the given index.
+func (r *RequestBridge) Milestone(index uint32) (*Milestone, error) {
+	return r.nodeBridge.Milestone(r.ctx, index)
+}
+
+// MilestoneConeMetadataOrdered returns the metadata of all blocks referenced by the milestone with the given index
+// in canonical white-flag order.
+func (r *RequestBridge) MilestoneConeMetadataOrdered(index uint32) ([]*inx.BlockMetadata, error) {
+	return r.nodeBridge.MilestoneConeMetadataOrdered(r.ctx, index)
+}
+
+// RequestTips requests the given amount of tips from the node.
+func (r *RequestBridge) RequestTips(count uint32, allowSemiLazy bool) (iotago.BlockIDs, error) {
+	return r.nodeBridge.RequestTips(r.ctx, count, allowSemiLazy)
+}
+
+// ValidateTransaction validates the transaction against the current ledger state of the node.
+func (r *RequestBridge) ValidateTransaction(tx *iotago.Transaction) (*TransactionValidationResult, error) {
+	return r.nodeBridge.ValidateTransaction(r.ctx, tx)
+}
+
+// Indexer returns the client of the indexer plugin of the node.
+func (r *RequestBridge) Indexer() (nodeclient.IndexerClient, error) {
+	return r.nodeBridge.Indexer(r.ctx)
+}
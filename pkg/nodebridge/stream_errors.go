@@ -0,0 +1,96 @@
+package nodebridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// ErrStreamTransient is the class of stream errors that are likely to disappear if the stream is opened again,
+	// e.g. because the connection to the node dropped.
+	ErrStreamTransient = errors.New("transient stream error")
+	// ErrStreamExhausted is the class of stream errors caused by exhausted resources on the node, e.g. rate limits.
+	// The stream should only be opened again after a backoff.
+	ErrStreamExhausted = errors.New("stream resources exhausted")
+	// ErrStreamProtocolMismatch is the class of stream errors caused by the node speaking a different protocol,
+	// e.g. unimplemented methods or malformed messages. Retrying won't help.
+	ErrStreamProtocolMismatch = errors.New("stream protocol mismatch")
+	// ErrStreamCanceled is the class of stream errors caused by the stream being canceled by the node or a deadline.
+	ErrStreamCanceled = errors.New("stream canceled")
+)
+
+// StreamError is returned by the Listen* methods if a stream failed.
+// Use errors.Is with one of the ErrStream* classes to decide whether to retry.
+type StreamError struct {
+	// Stream is the name of the stream that failed.
+	Stream string
+	// Class is one of the ErrStream* classes.
+	Class error
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *StreamError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", e.Stream, e.Class.Error(), e.Err.Error())
+}
+
+func (e *StreamError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is the class of the error.
+func (e *StreamError) Is(target error) bool {
+	return e.Class == target
+}
+
+// Retryable reports whether opening the stream again may succeed.
+func (e *StreamError) Retryable() bool {
+	return e.Class != ErrStreamProtocolMismatch
+}
+
+// StreamErrorClass returns the class of the given stream error.
+func StreamErrorClass(err error) error {
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return ErrStreamCanceled
+	case errors.Is(err, ErrLedgerUpdateTransactionAlreadyInProgress),
+		errors.Is(err, ErrLedgerUpdateInvalidOperation),
+		errors.Is(err, ErrLedgerUpdateEndedAbruptly),
+		errors.Is(err, ErrNodeInconsistent):
+		return ErrStreamProtocolMismatch
+	}
+
+	//nolint:exhaustive // all other codes are transient
+	switch status.Code(err) {
+	case codes.Canceled, codes.DeadlineExceeded:
+		return ErrStreamCanceled
+	case codes.ResourceExhausted:
+		return ErrStreamExhausted
+	case codes.Unimplemented, codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange, codes.DataLoss:
+		return ErrStreamProtocolMismatch
+	default:
+		return ErrStreamTransient
+	}
+}
+
+// newStreamError wraps the error of the given stream with its class.
+func newStreamError(stream string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var streamErr *StreamError
+	if errors.As(err, &streamErr) {
+		return err
+	}
+
+	return &StreamError{
+		Stream: stream,
+		Class:  StreamErrorClass(err),
+		Err:    err,
+	}
+}
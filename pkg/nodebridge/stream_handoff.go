@@ -0,0 +1,87 @@
+package nodebridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"google.golang.org/grpc/connectivity"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+)
+
+// ErrNodeReconnectTimeout is returned when the node did not come back within the maximum downtime of a stream handoff.
+var ErrNodeReconnectTimeout = errors.New("node did not reconnect in time")
+
+// WithStreamHandoff keeps the NodeBridge running if the node shuts down or the connection closes,
+// e.g. during a planned node upgrade. The streams are parked until the node is reachable again
+// and resume from the next milestone, without the consumers observing an error.
+// If the node is not reachable again within maxDowntime, the streams fail as usual.
+func WithStreamHandoff(maxDowntime time.Duration) options.Option[NodeBridge] {
+	return func(n *NodeBridge) {
+		n.streamHandoffMaxDowntime = maxDowntime
+	}
+}
+
+// isHandoffError reports whether the stream failed because the node went away.
+func isHandoffError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	return errors.Is(err, ErrStreamTransient) || errors.Is(err, ErrStreamCanceled)
+}
+
+// streamClosedByNode turns the regular end of an unbounded stream into a transient error if the handoff is enabled,
+// because the node only closes such streams if it shuts down.
+func (n *NodeBridge) streamClosedByNode(ctx context.Context, stream string, err error) error {
+	if err != nil || n.streamHandoffMaxDowntime == 0 || ctx.Err() != nil {
+		return err
+	}
+
+	return &StreamError{Stream: stream, Class: ErrStreamTransient, Err: io.EOF}
+}
+
+// waitForReconnect blocks until the connection to the node is ready again or the maximum downtime is exceeded.
+func (n *NodeBridge) waitForReconnect(ctx context.Context) error {
+	ctxTimeout, cancelTimeout := context.WithTimeout(ctx, n.streamHandoffMaxDowntime)
+	defer cancelTimeout()
+
+	for {
+		state := n.conn.GetState()
+		if state == connectivity.Ready {
+			return nil
+		}
+		if state == connectivity.Idle {
+			n.conn.Connect()
+		}
+
+		if !n.conn.WaitForStateChange(ctxTimeout, state) {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			return fmt.Errorf("%w: max downtime %s exceeded", ErrNodeReconnectTimeout, n.streamHandoffMaxDowntime)
+		}
+	}
+}
+
+// handoff parks the stream with the given name until the node is reachable again.
+// It returns false if the stream should fail with the given error instead.
+func (n *NodeBridge) handoff(ctx context.Context, stream string, err error) bool {
+	if n.streamHandoffMaxDowntime == 0 || !isHandoffError(ctx, err) {
+		return false
+	}
+
+	n.LogWarnf("%s interrupted, waiting for the node to come back: %s", stream, err.Error())
+	if err := n.waitForReconnect(ctx); err != nil {
+		n.LogWarnf("%s interrupted, waiting for the node to come back failed: %s", stream, err.Error())
+
+		return false
+	}
+	n.LogInfof("%s resumed after the node came back", stream)
+
+	return true
+}
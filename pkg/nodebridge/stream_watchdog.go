@@ -0,0 +1,365 @@
+package nodebridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/connectivity"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/hive.go/core/generics/options"
+)
+
+// StreamFunc runs a stream until it ends or the given context is done.
+// It has to report every received message to the given progress.
+type StreamFunc func(ctx context.Context, progress *StreamProgress) error
+
+// StreamProgress is the progress of a single stream.
+type StreamProgress struct {
+	mutex              sync.RWMutex
+	lastMessageTime    time.Time
+	lastMilestoneIndex uint32
+}
+
+// Report records that a message was received.
+// The milestone index is optional, 0 keeps the previous one.
+func (p *StreamProgress) Report(milestoneIndex uint32) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.lastMessageTime = time.Now()
+	if milestoneIndex != 0 {
+		p.lastMilestoneIndex = milestoneIndex
+	}
+}
+
+// LastMessageTime returns the time the last message was received.
+func (p *StreamProgress) LastMessageTime() time.Time {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.lastMessageTime
+}
+
+// LastMilestoneIndex returns the last reported milestone index, or 0 if none was reported.
+func (p *StreamProgress) LastMilestoneIndex() uint32 {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.lastMilestoneIndex
+}
+
+func (p *StreamProgress) resetTime() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.lastMessageTime = time.Now()
+}
+
+// StreamStall describes a stalled stream.
+type StreamStall struct {
+	// Stream is the name of the stalled stream.
+	Stream string
+	// LastMessageTime is the time the last message of the stream was received.
+	LastMessageTime time.Time
+	// LastMilestoneIndex is the last milestone index reported by the stream.
+	LastMilestoneIndex uint32
+	// ConfirmedMilestoneIndex is the confirmed milestone index of the node when the stall was detected.
+	ConfirmedMilestoneIndex uint32
+	// Restarts is the amount of times the stream was restarted, including this restart.
+	Restarts int
+}
+
+func StreamStallCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(stall *StreamStall))(params[0].(*StreamStall))
+}
+
+type StreamWatchdogEvents struct {
+	// StreamStalled is triggered before a stalled stream is restarted.
+	StreamStalled *events.Event
+}
+
+// ErrStreamNotFound is returned if no stream with the given name is run by the watchdog.
+var ErrStreamNotFound = errors.New("stream not found")
+
+// StreamInfo describes a stream run by the watchdog.
+type StreamInfo struct {
+	// Stream is the name of the stream.
+	Stream string `json:"stream"`
+	// LastMessageTime is the time the last message of the stream was received.
+	LastMessageTime time.Time `json:"lastMessageTime"`
+	// LastMilestoneIndex is the last milestone index reported by the stream.
+	LastMilestoneIndex uint32 `json:"lastMilestoneIndex"`
+	// Lag is the amount of confirmed milestones the stream is behind,
+	// it is 0 for streams that don't report milestone indexes.
+	Lag uint32 `json:"lag"`
+	// Restarts is the amount of times the stream was restarted.
+	Restarts int `json:"restarts"`
+}
+
+// watchedStream is the state of a running stream.
+type watchedStream struct {
+	progress *StreamProgress
+
+	mutex            sync.Mutex
+	cancel           context.CancelFunc
+	restarts         int
+	restartRequested bool
+}
+
+// requestRestart cancels the current run of the stream, so it is started again.
+func (s *watchedStream) requestRestart() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.restartRequested = true
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// takeRestartRequest returns whether a restart was requested and resets the request.
+func (s *watchedStream) takeRestartRequest() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	restartRequested := s.restartRequested
+	s.restartRequested = false
+
+	return restartRequested
+}
+
+// StreamWatchdog restarts streams that stopped receiving messages while the connection to the node is still healthy.
+// Streams that report milestone indexes are only considered stalled if the node confirmed a newer milestone,
+// so quiet periods without new milestones don't cause restarts.
+type StreamWatchdog struct {
+	nodeBridge *NodeBridge
+
+	stallThreshold time.Duration
+	checkInterval  time.Duration
+
+	streamsMutex sync.RWMutex
+	streams      map[string]*watchedStream
+
+	Events *StreamWatchdogEvents
+}
+
+// WithStallThreshold sets the duration without messages after which a stream is considered stalled.
+func WithStallThreshold(stallThreshold time.Duration) options.Option[StreamWatchdog] {
+	return func(w *StreamWatchdog) {
+		w.stallThreshold = stallThreshold
+	}
+}
+
+// WithStallCheckInterval sets the interval in which the streams are checked.
+func WithStallCheckInterval(checkInterval time.Duration) options.Option[StreamWatchdog] {
+	return func(w *StreamWatchdog) {
+		w.checkInterval = checkInterval
+	}
+}
+
+// NewStreamWatchdog creates a new StreamWatchdog.
+func NewStreamWatchdog(nodeBridge *NodeBridge, opts ...options.Option[StreamWatchdog]) *StreamWatchdog {
+	return options.Apply(&StreamWatchdog{
+		nodeBridge:     nodeBridge,
+		stallThreshold: time.Minute,
+		checkInterval:  5 * time.Second,
+		streams:        make(map[string]*watchedStream),
+		Events: &StreamWatchdogEvents{
+			StreamStalled: events.NewEvent(StreamStallCaller),
+		},
+	}, opts)
+}
+
+// connectionReady reports whether the gRPC connection to the node appears healthy.
+func (w *StreamWatchdog) connectionReady() bool {
+	return w.nodeBridge.ConnectionState() == connectivity.Ready
+}
+
+// stalled returns the stall of the stream, or nil if the stream is not stalled.
+func (w *StreamWatchdog) stalled(name string, progress *StreamProgress, restarts int) *StreamStall {
+	lastMessageTime := progress.LastMessageTime()
+	if time.Since(lastMessageTime) < w.stallThreshold {
+		return nil
+	}
+
+	// if the connection dropped, the stream fails on its own and the error is returned to the caller
+	if !w.connectionReady() {
+		return nil
+	}
+
+	lastMilestoneIndex := progress.LastMilestoneIndex()
+	confirmedMilestoneIndex := w.nodeBridge.ConfirmedMilestoneIndex()
+	if lastMilestoneIndex != 0 && lastMilestoneIndex >= confirmedMilestoneIndex {
+		// the stream is up to date, there was just nothing to send
+		return nil
+	}
+
+	return &StreamStall{
+		Stream:                  name,
+		LastMessageTime:         lastMessageTime,
+		LastMilestoneIndex:      lastMilestoneIndex,
+		ConfirmedMilestoneIndex: confirmedMilestoneIndex,
+		Restarts:                restarts + 1,
+	}
+}
+
+func (w *StreamWatchdog) watch(ctx context.Context, cancel context.CancelFunc, name string, progress *StreamProgress, restarts int, stalled chan<- *StreamStall) {
+	ticker := time.NewTicker(w.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			if stall := w.stalled(name, progress, restarts); stall != nil {
+				stalled <- stall
+				cancel()
+
+				return
+			}
+		}
+	}
+}
+
+// Run runs the stream and restarts it whenever it stalls or a restart is requested via RestartStream,
+// until the stream ends or the given context is done.
+// The progress is kept across restarts, so the stream can resume from the last reported milestone index.
+func (w *StreamWatchdog) Run(ctx context.Context, name string, stream StreamFunc) error {
+	watched := &watchedStream{progress: &StreamProgress{}}
+	watched.progress.resetTime()
+
+	w.streamsMutex.Lock()
+	w.streams[name] = watched
+	w.streamsMutex.Unlock()
+
+	defer func() {
+		w.streamsMutex.Lock()
+		defer w.streamsMutex.Unlock()
+
+		if w.streams[name] == watched {
+			delete(w.streams, name)
+		}
+	}()
+
+	progress := watched.progress
+	for {
+		streamCtx, cancel := context.WithCancel(ctx)
+		stalled := make(chan *StreamStall, 1)
+
+		watched.mutex.Lock()
+		watched.cancel = cancel
+		restarts := watched.restarts
+		watched.mutex.Unlock()
+
+		go w.watch(streamCtx, cancel, name, progress, restarts, stalled)
+
+		err := stream(streamCtx, progress)
+		cancel()
+
+		if ctx.Err() != nil {
+			return err
+		}
+
+		select {
+		case stall := <-stalled:
+			watched.mutex.Lock()
+			watched.restarts = stall.Restarts
+			watched.mutex.Unlock()
+
+			w.nodeBridge.LogWarnf("%s stalled since %s (last milestone index: %d, confirmed milestone index: %d), restarting stream ...", name, stall.LastMessageTime.Format(time.RFC3339), stall.LastMilestoneIndex, stall.ConfirmedMilestoneIndex)
+			w.Events.StreamStalled.Trigger(stall)
+			progress.resetTime()
+
+		default:
+			if !watched.takeRestartRequest() {
+				return err
+			}
+
+			watched.mutex.Lock()
+			watched.restarts++
+			watched.mutex.Unlock()
+
+			w.nodeBridge.LogInfof("%s restart requested, restarting stream ...", name)
+			progress.resetTime()
+		}
+	}
+}
+
+// Streams returns the info of all streams currently run by the watchdog, sorted by name.
+func (w *StreamWatchdog) Streams() []*StreamInfo {
+	w.streamsMutex.RLock()
+	defer w.streamsMutex.RUnlock()
+
+	confirmedMilestoneIndex := w.nodeBridge.ConfirmedMilestoneIndex()
+
+	infos := make([]*StreamInfo, 0, len(w.streams))
+	for name, watched := range w.streams {
+		watched.mutex.Lock()
+		restarts := watched.restarts
+		watched.mutex.Unlock()
+
+		info := &StreamInfo{
+			Stream:             name,
+			LastMessageTime:    watched.progress.LastMessageTime(),
+			LastMilestoneIndex: watched.progress.LastMilestoneIndex(),
+			Restarts:           restarts,
+		}
+		if info.LastMilestoneIndex != 0 && info.LastMilestoneIndex < confirmedMilestoneIndex {
+			info.Lag = confirmedMilestoneIndex - info.LastMilestoneIndex
+		}
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Stream < infos[j].Stream
+	})
+
+	return infos
+}
+
+// RestartStream restarts the stream with the given name.
+// Like after a stall, the stream resumes from the last reported milestone index.
+func (w *StreamWatchdog) RestartStream(name string) error {
+	w.streamsMutex.RLock()
+	watched, exists := w.streams[name]
+	w.streamsMutex.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrStreamNotFound, name)
+	}
+	watched.requestRestart()
+
+	return nil
+}
+
+// ListenToLedgerUpdates is NodeBridge.ListenToLedgerUpdates guarded by the watchdog.
+// A restarted stream resumes after the last consumed milestone.
+func (w *StreamWatchdog) ListenToLedgerUpdates(ctx context.Context, startIndex uint32, endIndex uint32, consume func(update *LedgerUpdate) error) error {
+	return w.Run(ctx, "ListenToLedgerUpdates", func(ctx context.Context, progress *StreamProgress) error {
+		resumeIndex := startIndex
+		if lastIndex := progress.LastMilestoneIndex(); lastIndex != 0 {
+			resumeIndex = lastIndex + 1
+		}
+
+		if endIndex != 0 && resumeIndex > endIndex {
+			return nil
+		}
+
+		return w.nodeBridge.ListenToLedgerUpdates(ctx, resumeIndex, endIndex, func(update *LedgerUpdate) error {
+			if err := consume(update); err != nil {
+				return err
+			}
+			progress.Report(update.MilestoneIndex)
+
+			return nil
+		})
+	})
+}
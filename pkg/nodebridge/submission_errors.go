@@ -0,0 +1,119 @@
+package nodebridge
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// ErrBlockInvalid is the class of submission errors caused by a syntactically or semantically invalid block.
+	// Submitting the same block again won't help, it has to be dropped or rebuilt with a different payload.
+	ErrBlockInvalid = errors.New("invalid block")
+	// ErrBlockBelowMaxDepth is the class of submission errors caused by parents that are too old to be referenced.
+	// The block has to be rebuilt with new tips.
+	ErrBlockBelowMaxDepth = errors.New("block below max depth")
+	// ErrBlockPoWInsufficient is the class of submission errors caused by a proof of work below the minimum score.
+	// The proof of work has to be done again.
+	ErrBlockPoWInsufficient = errors.New("insufficient proof of work")
+	// ErrBlockSubmissionUnavailable is the class of submission errors caused by the node being unable to attach blocks
+	// at the moment, e.g. because it is not synced or the connection dropped. The submission can be retried later.
+	ErrBlockSubmissionUnavailable = errors.New("block submission unavailable")
+)
+
+// SubmissionAction is what an issuer should do with a block whose submission failed.
+type SubmissionAction string
+
+const (
+	// SubmissionActionDrop means the block can't be fixed and should be dropped.
+	SubmissionActionDrop SubmissionAction = "drop"
+	// SubmissionActionRebuild means the block should be rebuilt with new tips.
+	SubmissionActionRebuild SubmissionAction = "rebuild"
+	// SubmissionActionRedoPoW means the proof of work of the block should be done again.
+	SubmissionActionRedoPoW SubmissionAction = "redoPoW"
+	// SubmissionActionRetry means the same block should be submitted again later.
+	SubmissionActionRetry SubmissionAction = "retry"
+)
+
+// SubmissionError is returned by SubmitBlock if the node rejected the block.
+// Use errors.Is with one of the ErrBlock* classes, or Action, to decide how to proceed.
+type SubmissionError struct {
+	// Class is one of the ErrBlock* classes.
+	Class error
+	// Code is the gRPC status code returned by the node.
+	Code codes.Code
+	// Detail is the reason reported by the node, e.g. which check of the block failed.
+	Detail string
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *SubmissionError) Error() string {
+	return fmt.Sprintf("submitting block failed: %s: %s", e.Class.Error(), e.Detail)
+}
+
+func (e *SubmissionError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is the class of the error.
+func (e *SubmissionError) Is(target error) bool {
+	return e.Class == target
+}
+
+// Action returns what the issuer should do with the block.
+func (e *SubmissionError) Action() SubmissionAction {
+	switch e.Class {
+	case ErrBlockBelowMaxDepth:
+		return SubmissionActionRebuild
+	case ErrBlockPoWInsufficient:
+		return SubmissionActionRedoPoW
+	case ErrBlockSubmissionUnavailable:
+		return SubmissionActionRetry
+	default:
+		return SubmissionActionDrop
+	}
+}
+
+// submissionErrorClass classifies the status returned by the node.
+// The node does not use dedicated codes for the reasons, so they are derived from the message of the status.
+func submissionErrorClass(s *status.Status) error {
+	message := strings.ToLower(s.Message())
+
+	switch {
+	case strings.Contains(message, "below max depth"):
+		return ErrBlockBelowMaxDepth
+	case strings.Contains(message, "pow") && (strings.Contains(message, "score") || strings.Contains(message, "insufficient")):
+		return ErrBlockPoWInsufficient
+	}
+
+	//nolint:exhaustive // all other codes are caused by the block
+	switch s.Code() {
+	case codes.Unavailable, codes.Internal, codes.ResourceExhausted, codes.DeadlineExceeded, codes.Canceled, codes.Aborted:
+		return ErrBlockSubmissionUnavailable
+	default:
+		return ErrBlockInvalid
+	}
+}
+
+// newSubmissionError wraps the error returned by the node for a block submission.
+func newSubmissionError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	return &SubmissionError{
+		Class:  submissionErrorClass(s),
+		Code:   s.Code(),
+		Detail: s.Message(),
+		Err:    err,
+	}
+}
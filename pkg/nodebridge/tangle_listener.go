@@ -7,9 +7,6 @@ import (
 	"io"
 	"sync"
 
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
-
 	"github.com/iotaledger/hive.go/core/events"
 	inx "github.com/iotaledger/inx/go"
 	iotago "github.com/iotaledger/iota.go/v3"
@@ -129,7 +126,7 @@ func (t *TangleListener) RegisterMilestoneConfirmedEvent(msIndex uint32) chan st
 
 	// check if the milestone is already confirmed
 	ms, err := t.nodeBridge.ConfirmedMilestone()
-	if err != nil {
+	if err == nil {
 		if ms != nil && ms.Milestone.Index >= msIndex {
 			// trigger the sync event, because the milestone is already confirmed
 			t.milestoneConfirmedSyncEvent.Trigger(msIndex)
@@ -149,7 +146,7 @@ func (t *TangleListener) Run(ctx context.Context) {
 
 	go func() {
 		if err := t.listenToSolidBlocks(c, cancel); err != nil {
-			t.nodeBridge.LogErrorf("Error listening to solid blocks: %s", err)
+			t.nodeBridge.sampledLogger.LogErrorf("listenToSolidBlocks", "Error listening to solid blocks: %s", err)
 		}
 	}()
 
@@ -167,18 +164,17 @@ func (t *TangleListener) listenToSolidBlocks(ctx context.Context, cancel context
 
 	stream, err := t.nodeBridge.Client().ListenToSolidBlocks(ctx, &inx.NoParams{})
 	if err != nil {
-		return err
+		return newStreamError("ListenToSolidBlocks", err)
 	}
 
 	for {
 		metadata, err := stream.Recv()
 		if err != nil {
-			if errors.Is(err, io.EOF) || status.Code(err) == codes.Canceled {
+			if errors.Is(err, io.EOF) || ctx.Err() != nil {
 				break
 			}
-			t.nodeBridge.LogErrorf("listenToSolidBlocks: %s", err.Error())
 
-			break
+			return newStreamError("ListenToSolidBlocks", err)
 		}
 		if ctx.Err() != nil {
 			break
@@ -188,6 +184,5 @@ func (t *TangleListener) listenToSolidBlocks(ctx context.Context, cancel context
 		t.Events.BlockSolid.Trigger(metadata)
 	}
 
-	//nolint:nilerr // false positive
 	return nil
 }
@@ -7,9 +7,6 @@ import (
 	"sync"
 	"time"
 
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
-
 	inx "github.com/iotaledger/inx/go"
 )
 
@@ -35,7 +32,7 @@ func (t *TipPoolListener) Run(ctx context.Context) {
 
 	go func() {
 		if err := t.listenToTipsMetrics(c, cancel); err != nil {
-			t.nodeBridge.LogErrorf("Error listening to tip metrics: %s", err)
+			t.nodeBridge.sampledLogger.LogErrorf("listenToTipsMetrics", "Error listening to tip metrics: %s", err)
 		}
 	}()
 
@@ -47,18 +44,17 @@ func (t *TipPoolListener) listenToTipsMetrics(ctx context.Context, cancel contex
 
 	stream, err := t.nodeBridge.Client().ListenToTipsMetrics(ctx, &inx.TipsMetricRequest{IntervalInMilliseconds: uint32(t.interval.Milliseconds())})
 	if err != nil {
-		return err
+		return newStreamError("ListenToTipsMetrics", err)
 	}
 
 	for {
 		tipsMetric, err := stream.Recv()
 		if err != nil {
-			if errors.Is(err, io.EOF) || status.Code(err) == codes.Canceled {
+			if errors.Is(err, io.EOF) || ctx.Err() != nil {
 				break
 			}
-			t.nodeBridge.LogErrorf("ListenToTipsMetrics: %s", err.Error())
 
-			break
+			return newStreamError("ListenToTipsMetrics", err)
 		}
 		if ctx.Err() != nil {
 			break
@@ -66,7 +62,6 @@ func (t *TipPoolListener) listenToTipsMetrics(ctx context.Context, cancel contex
 		t.processTipsMetric(tipsMetric)
 	}
 
-	//nolint:nilerr // false positive
 	return nil
 }
 
@@ -0,0 +1,176 @@
+package nodebridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotaledger/hive.go/serializer/v2"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrInputNotFound is returned if an input of a transaction is unknown to the node.
+	ErrInputNotFound = errors.New("input not found")
+	// ErrInputAlreadySpent is returned if an input of a transaction was already spent.
+	ErrInputAlreadySpent = errors.New("input already spent")
+)
+
+// TransactionCheck names a check of the transaction dry-run.
+type TransactionCheck string
+
+const (
+	// TransactionCheckSyntax checks the syntactic validity of the transaction.
+	TransactionCheckSyntax TransactionCheck = "syntax"
+	// TransactionCheckInputs checks that all inputs exist and are unspent.
+	TransactionCheckInputs TransactionCheck = "inputs"
+	// TransactionCheckStorageDeposit checks that every output covers its storage deposit.
+	TransactionCheckStorageDeposit TransactionCheck = "storageDeposit"
+	// TransactionCheckTimelocks checks that no input is timelocked.
+	TransactionCheckTimelocks TransactionCheck = "timelocks"
+	// TransactionCheckUnlocks checks that the unlocks satisfy the unlock conditions of the inputs.
+	TransactionCheckUnlocks TransactionCheck = "unlocks"
+	// TransactionCheckSenders checks that the sender features of the outputs are unlocked.
+	TransactionCheckSenders TransactionCheck = "senders"
+	// TransactionCheckBalances checks that the base token amounts of inputs and outputs match.
+	TransactionCheckBalances TransactionCheck = "balances"
+	// TransactionCheckNativeTokens checks that the native tokens of inputs and outputs match.
+	TransactionCheckNativeTokens TransactionCheck = "nativeTokens"
+	// TransactionCheckChains checks the state transitions of aliases, NFTs and foundries.
+	TransactionCheckChains TransactionCheck = "chains"
+)
+
+// TransactionValidationError is a single failed check of the transaction dry-run.
+type TransactionValidationError struct {
+	// Check is the check that failed.
+	Check TransactionCheck `json:"check"`
+	// InputIndex is the index of the offending input, if the check relates to a single input.
+	InputIndex *int `json:"inputIndex,omitempty"`
+	// OutputIndex is the index of the offending output, if the check relates to a single output.
+	OutputIndex *int `json:"outputIndex,omitempty"`
+	// Err is the reason the check failed.
+	Err error `json:"-"`
+	// Message is the message of Err.
+	Message string `json:"message"`
+}
+
+func (e *TransactionValidationError) Error() string {
+	return fmt.Sprintf("%s check failed: %s", e.Check, e.Message)
+}
+
+func (e *TransactionValidationError) Unwrap() error {
+	return e.Err
+}
+
+func newTransactionValidationError(check TransactionCheck, err error) *TransactionValidationError {
+	return &TransactionValidationError{Check: check, Err: err, Message: err.Error()}
+}
+
+// TransactionValidationResult is the result of the transaction dry-run.
+type TransactionValidationResult struct {
+	// Valid is true if all checks passed.
+	Valid bool `json:"valid"`
+	// Errors are the failed checks.
+	Errors []*TransactionValidationError `json:"errors,omitempty"`
+}
+
+func (r *TransactionValidationResult) add(err *TransactionValidationError) {
+	r.Valid = false
+	r.Errors = append(r.Errors, err)
+}
+
+// ValidateTransaction validates the transaction against the current ledger state of the node without submitting it.
+// Independent checks are all executed, so the result contains every problem that can be detected at once.
+// The returned error is only set if the node could not be queried.
+func (n *NodeBridge) ValidateTransaction(ctx context.Context, tx *iotago.Transaction) (*TransactionValidationResult, error) {
+	result := &TransactionValidationResult{Valid: true}
+	protoParas := n.ProtocolParameters()
+
+	// serializing with validation performs the syntactic checks
+	if _, err := tx.Serialize(serializer.DeSeriModePerformValidation, protoParas); err != nil {
+		result.add(newTransactionValidationError(TransactionCheckSyntax, err))
+
+		return result, nil
+	}
+
+	for i, output := range tx.Essence.Outputs {
+		if _, err := protoParas.RentStructure.CoversStateRent(output, output.Deposit()); err != nil {
+			outputIndex := i
+			validationErr := newTransactionValidationError(TransactionCheckStorageDeposit, err)
+			validationErr.OutputIndex = &outputIndex
+			result.add(validationErr)
+		}
+	}
+
+	inputs := make(iotago.OutputSet, len(tx.Essence.Inputs))
+	for i, input := range tx.Essence.Inputs {
+		//nolint:forcetypeassert // the syntactic validation only allows UTXO inputs
+		outputID := input.(*iotago.UTXOInput).ID()
+		inputIndex := i
+
+		response, err := n.Output(ctx, outputID)
+		if err != nil {
+			if status.Code(err) != codes.NotFound {
+				return nil, err
+			}
+			validationErr := newTransactionValidationError(TransactionCheckInputs, fmt.Errorf("%w: %s", ErrInputNotFound, outputID.ToHex()))
+			validationErr.InputIndex = &inputIndex
+			result.add(validationErr)
+
+			continue
+		}
+
+		if response.GetSpent() != nil {
+			validationErr := newTransactionValidationError(TransactionCheckInputs, fmt.Errorf("%w: %s", ErrInputAlreadySpent, outputID.ToHex()))
+			validationErr.InputIndex = &inputIndex
+			result.add(validationErr)
+
+			continue
+		}
+
+		output, err := response.GetOutput().UnwrapOutput(serializer.DeSeriModeNoValidation, nil)
+		if err != nil {
+			return nil, err
+		}
+		inputs[outputID] = output
+	}
+
+	if len(inputs) != len(tx.Essence.Inputs) {
+		// the semantic checks need all inputs
+		return result, nil
+	}
+
+	confirmedMilestone, err := n.ConfirmedMilestone()
+	if err != nil {
+		return nil, err
+	}
+	extParas := &iotago.ExternalUnlockParameters{}
+	if confirmedMilestone != nil {
+		extParas.ConfUnix = confirmedMilestone.Milestone.Timestamp
+	}
+
+	semanticCheck := func(check TransactionCheck, semValFuncs ...iotago.TxSemanticValidationFunc) bool {
+		if err := tx.SemanticallyValidate(&iotago.SemanticValidationContext{ExtParas: extParas}, inputs, semValFuncs...); err != nil {
+			result.add(newTransactionValidationError(check, err))
+
+			return false
+		}
+
+		return true
+	}
+
+	semanticCheck(TransactionCheckTimelocks, iotago.TxSemanticTimelock())
+	semanticCheck(TransactionCheckBalances, iotago.TxSemanticDeposit())
+	semanticCheck(TransactionCheckNativeTokens, iotago.TxSemanticNativeTokens())
+
+	// the sender and chain checks depend on the unlocked identities
+	if semanticCheck(TransactionCheckUnlocks, iotago.TxSemanticInputUnlocks()) {
+		semanticCheck(TransactionCheckSenders, iotago.TxSemanticInputUnlocks(), iotago.TxSemanticOutputsSender())
+		semanticCheck(TransactionCheckChains, iotago.TxSemanticInputUnlocks(), iotago.TxSemanticSTVFOnChains())
+	}
+
+	return result, nil
+}
@@ -0,0 +1,70 @@
+package nodebridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrUpdateLevelNotSupported is returned when the node does not support updates of the requested level.
+var ErrUpdateLevelNotSupported = errors.New("update level not supported by the node")
+
+// UpdateLevel is the level of finality an update was emitted at.
+type UpdateLevel byte
+
+const (
+	// UpdateLevelConfirmed updates are final, e.g. ledger changes confirmed by a milestone.
+	UpdateLevelConfirmed UpdateLevel = iota
+	// UpdateLevelAccepted updates are emitted as soon as the node accepted them, but may still be reverted.
+	// They are only supported by nodes of future protocol versions.
+	UpdateLevelAccepted
+)
+
+func (l UpdateLevel) String() string {
+	switch l {
+	case UpdateLevelConfirmed:
+		return "confirmed"
+	case UpdateLevelAccepted:
+		return "accepted"
+	default:
+		return fmt.Sprintf("unknown(%d)", l)
+	}
+}
+
+// Update is the common interface of all updates emitted by ListenToUpdates,
+// so consumers don't depend on the level they subscribed to.
+type Update interface {
+	// Level returns the level of finality of the update.
+	Level() UpdateLevel
+	// Index returns the index the update belongs to, e.g. the milestone index.
+	Index() uint32
+}
+
+// Level implements Update, ledger updates are confirmed by a milestone.
+func (u *LedgerUpdate) Level() UpdateLevel {
+	return UpdateLevelConfirmed
+}
+
+// Index implements Update.
+func (u *LedgerUpdate) Index() uint32 {
+	return u.MilestoneIndex
+}
+
+// SupportsUpdateLevel reports whether the node emits updates of the given level.
+func (n *NodeBridge) SupportsUpdateLevel(level UpdateLevel) bool {
+	// the current protocol only knows milestone confirmation
+	return level == UpdateLevelConfirmed
+}
+
+// ListenToUpdates listens to the ledger updates of the given level from startIndex to endIndex.
+// Consumers that only use the Update interface keep working once the node supports more levels.
+// It returns ErrUpdateLevelNotSupported if the node does not emit updates of the given level.
+func (n *NodeBridge) ListenToUpdates(ctx context.Context, level UpdateLevel, startIndex uint32, endIndex uint32, consume func(update Update) error) error {
+	if !n.SupportsUpdateLevel(level) {
+		return fmt.Errorf("%w: %s", ErrUpdateLevelNotSupported, level)
+	}
+
+	return n.ListenToLedgerUpdates(ctx, startIndex, endIndex, func(update *LedgerUpdate) error {
+		return consume(update)
+	})
+}
@@ -0,0 +1,81 @@
+package nodebridge
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sort"
+
+	inx "github.com/iotaledger/inx/go"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// lessWhiteFlagOrder reports whether the block a comes before the block b in canonical white-flag order.
+// Blocks are ordered by their white-flag index, ties are broken by the block ID.
+func lessWhiteFlagOrder(a *inx.BlockMetadata, b *inx.BlockMetadata) bool {
+	if a.GetWhiteFlagIndex() != b.GetWhiteFlagIndex() {
+		return a.GetWhiteFlagIndex() < b.GetWhiteFlagIndex()
+	}
+
+	aID := a.UnwrapBlockID()
+	bID := b.UnwrapBlockID()
+
+	return bytes.Compare(aID[:], bID[:]) < 0
+}
+
+// SortWhiteFlagOrder sorts the given block metadata in canonical white-flag order.
+// The tie-breaking by block ID guarantees the same order on every replica,
+// even if the metadata was received in a different order.
+func SortWhiteFlagOrder(metadata []*inx.BlockMetadata) {
+	sort.SliceStable(metadata, func(i int, j int) bool {
+		return lessWhiteFlagOrder(metadata[i], metadata[j])
+	})
+}
+
+// WhiteFlagOrderedBlockIDs returns the block IDs of the given metadata in canonical white-flag order.
+// The given slice is not modified.
+func WhiteFlagOrderedBlockIDs(metadata []*inx.BlockMetadata) iotago.BlockIDs {
+	sorted := make([]*inx.BlockMetadata, len(metadata))
+	copy(sorted, metadata)
+	SortWhiteFlagOrder(sorted)
+
+	blockIDs := make(iotago.BlockIDs, 0, len(sorted))
+	for _, m := range sorted {
+		blockIDs = append(blockIDs, m.UnwrapBlockID())
+	}
+
+	return blockIDs
+}
+
+// MilestoneConeMetadataOrdered returns the metadata of all blocks referenced by the milestone with the given index
+// in canonical white-flag order.
+// In contrast to MilestoneConeMetadata, any stream error is returned, because a partial cone can't be ordered deterministically.
+func (n *NodeBridge) MilestoneConeMetadataOrdered(ctx context.Context, index uint32) ([]*inx.BlockMetadata, error) {
+	req := &inx.MilestoneRequest{
+		MilestoneIndex: index,
+	}
+
+	stream, err := n.client.ReadMilestoneConeMetadata(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	cone := make([]*inx.BlockMetadata, 0)
+	for {
+		metadata, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, err
+		}
+
+		cone = append(cone, metadata)
+	}
+
+	SortWhiteFlagOrder(cone)
+
+	return cone, nil
+}
@@ -0,0 +1,134 @@
+// Package nodeproxy proxies endpoints of the core node through the API of the app, tunneled over INX.
+// The ledger index and sync status headers of the app can be attached to proxied and native routes alike,
+// so clients of the combined API get the same freshness semantics for all of them.
+package nodeproxy
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+	"github.com/iotaledger/inx-app/pkg/syncstate"
+	inx "github.com/iotaledger/inx/go"
+)
+
+const (
+	// HeaderLedgerIndex is the last milestone index that was processed by the app when the response was created.
+	HeaderLedgerIndex = "X-IOTA-Ledger-Index"
+	// HeaderSyncStatus is the sync phase of the app when the response was created, e.g. "synced".
+	HeaderSyncStatus = "X-IOTA-Sync-Status"
+)
+
+// LedgerStatus is the ledger state of the app that is reported in the response headers.
+type LedgerStatus struct {
+	// LedgerIndex is the last milestone index that was processed by the app.
+	LedgerIndex uint32
+	// SyncStatus is the sync phase of the app.
+	SyncStatus string
+}
+
+// LedgerStatusFunc returns the current ledger state of the app.
+type LedgerStatusFunc func() LedgerStatus
+
+// MachineLedgerStatus returns a LedgerStatusFunc that reports the progress and the phase of the given syncstate.Machine.
+func MachineLedgerStatus(machine *syncstate.Machine) LedgerStatusFunc {
+	return func() LedgerStatus {
+		status := machine.Status()
+
+		return LedgerStatus{
+			LedgerIndex: status.ProcessedIndex,
+			SyncStatus:  status.Phase.String(),
+		}
+	}
+}
+
+// setLedgerStatusHeaders sets the ledger index and sync status headers of the app.
+func setLedgerStatusHeaders(header http.Header, status LedgerStatus) {
+	header.Set(HeaderLedgerIndex, strconv.FormatUint(uint64(status.LedgerIndex), 10))
+	header.Set(HeaderSyncStatus, status.SyncStatus)
+}
+
+// LedgerStatusMiddleware attaches the ledger index and sync status headers of the app to the responses of native routes.
+func LedgerStatusMiddleware(ledgerStatus LedgerStatusFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			setLedgerStatusHeaders(c.Response().Header(), ledgerStatus())
+
+			return next(c)
+		}
+	}
+}
+
+// Proxy forwards requests to the REST API of the core node over INX.
+type Proxy struct {
+	transport    http.RoundTripper
+	ledgerStatus LedgerStatusFunc
+}
+
+// WithLedgerStatus rewrites the ledger index and sync status headers of proxied responses with the ones of the app.
+// Without this option the responses of the node are passed through unchanged.
+func WithLedgerStatus(ledgerStatus LedgerStatusFunc) options.Option[Proxy] {
+	return func(p *Proxy) {
+		p.ledgerStatus = ledgerStatus
+	}
+}
+
+// New creates a new Proxy to the node that is connected to the NodeBridge.
+func New(nodeBridge *nodebridge.NodeBridge, opts ...options.Option[Proxy]) *Proxy {
+	return options.Apply(&Proxy{
+		transport: inx.NewAPIRoundTripper(nodeBridge.Client()),
+	}, opts)
+}
+
+// Handler returns the handler that forwards the request with its original path and query to the node,
+// e.g. to be registered with e.Any("/api/core/v2/*", proxy.Handler()).
+// Errors while reaching the node are returned as 502.
+func (p *Proxy) Handler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if p.ledgerStatus != nil {
+			// the headers are set before the node is queried, like for native routes,
+			// so the reported index never claims more than the app had processed when the request was received.
+			setLedgerStatusHeaders(c.Response().Header(), p.ledgerStatus())
+		}
+
+		var proxyErr error
+		reverseProxy := &httputil.ReverseProxy{
+			Director: func(req *http.Request) {
+				req.URL.Scheme = ""
+				req.URL.Host = ""
+				req.Host = ""
+			},
+			Transport:      p.transport,
+			ModifyResponse: p.modifyResponse,
+			ErrorHandler: func(_ http.ResponseWriter, _ *http.Request, err error) {
+				proxyErr = err
+			},
+		}
+
+		reverseProxy.ServeHTTP(c.Response(), c.Request())
+
+		if proxyErr != nil {
+			return errors.WithMessagef(echo.ErrBadGateway, "proxying request to node failed: %s", proxyErr)
+		}
+
+		return nil
+	}
+}
+
+// modifyResponse removes the ledger status headers of the node if the ones of the app are used,
+// otherwise the client would receive both.
+func (p *Proxy) modifyResponse(resp *http.Response) error {
+	if p.ledgerStatus == nil {
+		return nil
+	}
+
+	resp.Header.Del(HeaderLedgerIndex)
+	resp.Header.Del(HeaderSyncStatus)
+
+	return nil
+}
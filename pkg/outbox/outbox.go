@@ -0,0 +1,497 @@
+// Package outbox publishes events derived from ledger updates to external sinks, e.g. Kafka or webhooks,
+// following the outbox pattern: events are persisted together with the processing of a milestone,
+// published by a background worker and only marked as delivered once the sink accepted them.
+package outbox
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/core/kvstore"
+	"github.com/iotaledger/hive.go/core/logger"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+)
+
+const (
+	storePrefixEvent     byte = 0
+	storePrefixDelivered byte = 1
+
+	maxSinkNameLength = 255
+)
+
+var (
+	// ErrSinkAlreadyRegistered is returned when a sink with the same name was already registered.
+	ErrSinkAlreadyRegistered = errors.New("sink already registered")
+	// ErrSinkNotFound is returned when no sink with the given name was registered.
+	ErrSinkNotFound = errors.New("sink not found")
+	// ErrInvalidSinkName is returned when the name of a sink is empty or too long.
+	ErrInvalidSinkName = errors.New("invalid sink name")
+)
+
+// Message is a message that should be published to the sinks.
+type Message struct {
+	// Topic is the topic of the message, it can be used by sinks to route the message.
+	Topic string
+	// Payload is the sink independent payload, e.g. JSON.
+	Payload []byte
+}
+
+// Event is a persisted message for a single sink.
+type Event struct {
+	// ID identifies the event, it is derived from the milestone index and the position of the message
+	// and therefore stays the same if a milestone is processed again.
+	// Sinks should pass it on as idempotency key, so receivers can drop duplicates after a crash.
+	ID string `json:"id"`
+	// MilestoneIndex is the index of the milestone the message was derived from.
+	MilestoneIndex uint32 `json:"milestoneIndex"`
+	// Position is the position of the message within the messages of the milestone.
+	Position uint32 `json:"position"`
+	// Topic is the topic of the message.
+	Topic string `json:"topic"`
+	// Payload is the payload of the message.
+	Payload []byte `json:"payload"`
+	// CreatedAt is the time the event was persisted.
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Sink publishes events to an external system.
+type Sink interface {
+	// Name returns the unique name of the sink, it is part of the persisted keys and must not change.
+	Name() string
+	// Publish publishes the event. The event is published again until Publish returns nil.
+	Publish(ctx context.Context, event *Event) error
+}
+
+// SinkOptions are the per sink settings of the outbox.
+type SinkOptions struct {
+	retryInterval    time.Duration
+	maxRetryInterval time.Duration
+	filter           func(topic string) bool
+}
+
+// WithRetryInterval sets the delay before the first retry of a failed publication.
+// The delay is doubled with every further attempt, up to the given maximum.
+func WithRetryInterval(retryInterval time.Duration, maxRetryInterval time.Duration) options.Option[SinkOptions] {
+	return func(o *SinkOptions) {
+		o.retryInterval = retryInterval
+		o.maxRetryInterval = maxRetryInterval
+	}
+}
+
+// WithFilter sets the filter that decides which topics are published to the sink, by default all topics are.
+func WithFilter(filter func(topic string) bool) options.Option[SinkOptions] {
+	return func(o *SinkOptions) {
+		o.filter = filter
+	}
+}
+
+// position is the position of an event in the stream of a sink.
+type position struct {
+	milestoneIndex uint32
+	position       uint32
+}
+
+func (p position) after(other position) bool {
+	if p.milestoneIndex != other.milestoneIndex {
+		return p.milestoneIndex > other.milestoneIndex
+	}
+
+	return p.position > other.position
+}
+
+type registeredSink struct {
+	Sink
+	options *SinkOptions
+	wakeup  chan struct{}
+
+	deliveredMutex sync.RWMutex
+	hasDelivered   bool
+	delivered      position
+}
+
+// isDelivered reports whether the event at the given position was already delivered.
+func (s *registeredSink) isDelivered(pos position) bool {
+	s.deliveredMutex.RLock()
+	defer s.deliveredMutex.RUnlock()
+
+	return s.hasDelivered && !pos.after(s.delivered)
+}
+
+// Outbox persists events for its sinks and publishes them in order.
+// An event is only removed from the store once its sink accepted it, so the events survive crashes.
+// Events of milestones that are processed again replace the pending events, and events that were already
+// delivered are skipped, so together with idempotent receivers every event is published exactly once.
+type Outbox struct {
+	// the logger used to log events.
+	*logger.WrappedLogger
+
+	store        kvstore.KVStore
+	drainTimeout time.Duration
+
+	sinksMutex sync.RWMutex
+	sinks      map[string]*registeredSink
+}
+
+// WithDrainTimeout sets how long the pending events are still published after the shutdown was initiated.
+// Events that were not published until then stay persisted and are published after the restart.
+// By default publishing stops immediately.
+func WithDrainTimeout(drainTimeout time.Duration) options.Option[Outbox] {
+	return func(o *Outbox) {
+		o.drainTimeout = drainTimeout
+	}
+}
+
+// New creates a new Outbox that persists its events in the given store.
+func New(store kvstore.KVStore, log *logger.Logger, opts ...options.Option[Outbox]) *Outbox {
+	return options.Apply(&Outbox{
+		WrappedLogger: logger.NewWrappedLogger(log),
+		store:         store,
+		sinks:         make(map[string]*registeredSink),
+	}, opts)
+}
+
+func sinkPrefix(storePrefix byte, sinkName string) []byte {
+	prefix := make([]byte, 0, 2+len(sinkName))
+	prefix = append(prefix, storePrefix, byte(len(sinkName)))
+
+	return append(prefix, sinkName...)
+}
+
+func eventKey(sinkName string, pos position) kvstore.Key {
+	// big endian keeps the events sorted by position in ordered stores
+	indexes := make([]byte, 8)
+	binary.BigEndian.PutUint32(indexes[:4], pos.milestoneIndex)
+	binary.BigEndian.PutUint32(indexes[4:], pos.position)
+
+	return append(sinkPrefix(storePrefixEvent, sinkName), indexes...)
+}
+
+func deliveredKey(sinkName string) kvstore.Key {
+	return sinkPrefix(storePrefixDelivered, sinkName)
+}
+
+func positionValue(pos position) kvstore.Value {
+	value := make([]byte, 8)
+	binary.LittleEndian.PutUint32(value[:4], pos.milestoneIndex)
+	binary.LittleEndian.PutUint32(value[4:], pos.position)
+
+	return value
+}
+
+// RegisterSink registers a sink. Sinks must be registered before events are added and the outbox is run.
+func (o *Outbox) RegisterSink(sink Sink, opts ...options.Option[SinkOptions]) error {
+	name := sink.Name()
+	if name == "" || len(name) > maxSinkNameLength {
+		return fmt.Errorf("%w: \"%s\"", ErrInvalidSinkName, name)
+	}
+
+	o.sinksMutex.Lock()
+	defer o.sinksMutex.Unlock()
+
+	if _, exists := o.sinks[name]; exists {
+		return fmt.Errorf("%w: %s", ErrSinkAlreadyRegistered, name)
+	}
+
+	registered := &registeredSink{
+		Sink: sink,
+		options: options.Apply(&SinkOptions{
+			retryInterval:    time.Second,
+			maxRetryInterval: time.Minute,
+		}, opts),
+		wakeup: make(chan struct{}, 1),
+	}
+
+	value, err := o.store.Get(deliveredKey(name))
+	if err != nil && !errors.Is(err, kvstore.ErrKeyNotFound) {
+		return err
+	}
+	if err == nil {
+		if len(value) != 8 {
+			return fmt.Errorf("invalid delivered position of sink %s: length %d", name, len(value))
+		}
+		registered.hasDelivered = true
+		registered.delivered = position{
+			milestoneIndex: binary.LittleEndian.Uint32(value[:4]),
+			position:       binary.LittleEndian.Uint32(value[4:]),
+		}
+	}
+
+	o.sinks[name] = registered
+
+	return nil
+}
+
+func (o *Outbox) registeredSinks() []*registeredSink {
+	o.sinksMutex.RLock()
+	defer o.sinksMutex.RUnlock()
+
+	sinks := make([]*registeredSink, 0, len(o.sinks))
+	for _, sink := range o.sinks {
+		sinks = append(sinks, sink)
+	}
+
+	return sinks
+}
+
+// Add persists the messages derived from the milestone with the given index as events for all sinks, atomically.
+func (o *Outbox) Add(milestoneIndex uint32, messages []*Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	batch, err := o.store.Batched()
+	if err != nil {
+		return err
+	}
+
+	sinks := o.registeredSinks()
+	now := time.Now()
+
+	for i, message := range messages {
+		pos := position{milestoneIndex: milestoneIndex, position: uint32(i)}
+
+		for _, sink := range sinks {
+			if sink.options.filter != nil && !sink.options.filter(message.Topic) {
+				continue
+			}
+			if sink.isDelivered(pos) {
+				// the milestone is processed again after a crash, don't publish the delivered events twice
+				continue
+			}
+
+			value, err := json.Marshal(&Event{
+				ID:             fmt.Sprintf("%d-%d", milestoneIndex, i),
+				MilestoneIndex: milestoneIndex,
+				Position:       uint32(i),
+				Topic:          message.Topic,
+				Payload:        message.Payload,
+				CreatedAt:      now,
+			})
+			if err != nil {
+				batch.Cancel()
+
+				return err
+			}
+
+			if err := batch.Set(eventKey(sink.Name(), pos), value); err != nil {
+				batch.Cancel()
+
+				return err
+			}
+		}
+	}
+
+	if err := batch.Commit(); err != nil {
+		return err
+	}
+
+	for _, sink := range sinks {
+		select {
+		case sink.wakeup <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Consumer wraps the given ledger update consumer, so that the messages derived from every update are persisted
+// before the update is consumed. It can be wrapped by Journal.Consumer and passed to NodeBridge.ListenToLedgerUpdates.
+// The messages must be derived deterministically, so the event IDs stay the same if a milestone is processed again.
+func (o *Outbox) Consumer(toMessages func(update *nodebridge.LedgerUpdate) ([]*Message, error), consume func(update *nodebridge.LedgerUpdate) error) func(update *nodebridge.LedgerUpdate) error {
+	return func(update *nodebridge.LedgerUpdate) error {
+		messages, err := toMessages(update)
+		if err != nil {
+			return fmt.Errorf("deriving outbox messages of milestone %d failed: %w", update.MilestoneIndex, err)
+		}
+
+		if err := o.Add(update.MilestoneIndex, messages); err != nil {
+			return fmt.Errorf("adding outbox messages of milestone %d failed: %w", update.MilestoneIndex, err)
+		}
+
+		return consume(update)
+	}
+}
+
+// pendingEvents returns the pending events of the sink, ordered by position.
+func (o *Outbox) pendingEvents(sinkName string) ([]*Event, error) {
+	var innerErr error
+	events := make([]*Event, 0)
+	if err := o.store.Iterate(sinkPrefix(storePrefixEvent, sinkName), func(_ kvstore.Key, value kvstore.Value) bool {
+		event := &Event{}
+		if err := json.Unmarshal(value, event); err != nil {
+			innerErr = err
+
+			return false
+		}
+		events = append(events, event)
+
+		return true
+	}); err != nil {
+		return nil, err
+	}
+	if innerErr != nil {
+		return nil, innerErr
+	}
+
+	sort.Slice(events, func(i int, j int) bool {
+		return position{events[j].MilestoneIndex, events[j].Position}.after(position{events[i].MilestoneIndex, events[i].Position})
+	})
+
+	return events, nil
+}
+
+// Pending returns the amount of events that were not delivered to the sink with the given name yet.
+func (o *Outbox) Pending(sinkName string) (int, error) {
+	o.sinksMutex.RLock()
+	_, exists := o.sinks[sinkName]
+	o.sinksMutex.RUnlock()
+
+	if !exists {
+		return 0, fmt.Errorf("%w: %s", ErrSinkNotFound, sinkName)
+	}
+
+	events, err := o.pendingEvents(sinkName)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(events), nil
+}
+
+// markDelivered removes the event and remembers its position, atomically.
+func (o *Outbox) markDelivered(sink *registeredSink, event *Event) error {
+	pos := position{milestoneIndex: event.MilestoneIndex, position: event.Position}
+
+	batch, err := o.store.Batched()
+	if err != nil {
+		return err
+	}
+
+	if err := batch.Delete(eventKey(sink.Name(), pos)); err != nil {
+		batch.Cancel()
+
+		return err
+	}
+
+	if err := batch.Set(deliveredKey(sink.Name()), positionValue(pos)); err != nil {
+		batch.Cancel()
+
+		return err
+	}
+
+	if err := batch.Commit(); err != nil {
+		return err
+	}
+
+	sink.deliveredMutex.Lock()
+	defer sink.deliveredMutex.Unlock()
+
+	sink.hasDelivered = true
+	sink.delivered = pos
+
+	return nil
+}
+
+// publish publishes the event until the sink accepts it or the context is done.
+func (o *Outbox) publish(ctx context.Context, sink *registeredSink, event *Event) bool {
+	retryInterval := sink.options.retryInterval
+
+	for attempt := 1; ; attempt++ {
+		err := sink.Publish(ctx, event)
+		if err == nil {
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+
+		o.LogWarnf("publishing event %s to sink %s failed, attempt %d: %s", event.ID, sink.Name(), attempt, err)
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(retryInterval):
+		}
+
+		if retryInterval *= 2; retryInterval > sink.options.maxRetryInterval {
+			retryInterval = sink.options.maxRetryInterval
+		}
+	}
+}
+
+// deliverPending publishes the pending events of the sink in order, it returns false if publishing was interrupted.
+func (o *Outbox) deliverPending(ctx context.Context, sink *registeredSink) bool {
+	events, err := o.pendingEvents(sink.Name())
+	if err != nil {
+		o.LogErrorf("unable to load the events of sink %s: %s", sink.Name(), err)
+	}
+
+	for _, event := range events {
+		if !o.publish(ctx, sink, event) {
+			return false
+		}
+
+		if err := o.markDelivered(sink, event); err != nil {
+			o.LogErrorf("unable to mark event %s of sink %s as delivered: %s", event.ID, sink.Name(), err)
+		}
+	}
+
+	return true
+}
+
+func (o *Outbox) deliver(ctx context.Context, sink *registeredSink) {
+	// publishing is only interrupted by the shutdown
+	for o.deliverPending(ctx, sink) {
+		select {
+		case <-ctx.Done():
+			o.drain(sink)
+
+			return
+		case <-sink.wakeup:
+		case <-time.After(sink.options.maxRetryInterval):
+		}
+	}
+
+	o.drain(sink)
+}
+
+// drain publishes the pending events of the sink on shutdown until all were published or the drain timeout is reached.
+func (o *Outbox) drain(sink *registeredSink) {
+	if o.drainTimeout <= 0 {
+		return
+	}
+
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), o.drainTimeout)
+	defer cancelDrain()
+
+	if o.deliverPending(drainCtx, sink) {
+		return
+	}
+
+	if pending, err := o.Pending(sink.Name()); err == nil && pending > 0 {
+		o.LogInfof("%d pending events of sink %s are kept for publishing after the restart", pending, sink.Name())
+	}
+}
+
+// Run publishes the pending events to the sinks until the given context is done.
+// Every sink is served by its own worker, so a failing sink does not delay the others.
+// If a drain timeout is set, the pending events are still published on shutdown until the timeout is reached.
+func (o *Outbox) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, sink := range o.registeredSinks() {
+		wg.Add(1)
+		go func(sink *registeredSink) {
+			defer wg.Done()
+			o.deliver(ctx, sink)
+		}(sink)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,62 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// HeaderIdempotencyKey is the header that contains the ID of the event, receivers use it to drop duplicates.
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderTopic is the header that contains the topic of the event.
+	HeaderTopic = "X-Outbox-Topic"
+)
+
+// WebhookSink posts the payload of every event to an HTTP endpoint.
+type WebhookSink struct {
+	name        string
+	url         string
+	contentType string
+	client      *http.Client
+}
+
+// NewWebhookSink creates a new WebhookSink that posts the payloads with the given content type to the URL.
+func NewWebhookSink(name string, url string, contentType string, timeout time.Duration) *WebhookSink {
+	return &WebhookSink{
+		name:        name,
+		url:         url,
+		contentType: contentType,
+		client:      &http.Client{Timeout: timeout},
+	}
+}
+
+// Name returns the name of the sink.
+func (s *WebhookSink) Name() string {
+	return s.name
+}
+
+// Publish posts the payload of the event, the event ID is sent as HeaderIdempotencyKey.
+func (s *WebhookSink) Publish(ctx context.Context, event *Event) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(event.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", s.contentType)
+	req.Header.Set(HeaderIdempotencyKey, event.ID)
+	req.Header.Set(HeaderTopic, event.Topic)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s responded with status code %d", s.url, resp.StatusCode)
+	}
+
+	return nil
+}
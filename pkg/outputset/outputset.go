@@ -0,0 +1,276 @@
+// Package outputset provides a persisted set of output IDs for very large collections,
+// e.g. all outputs ever seen for a wallet.
+package outputset
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/iotaledger/hive.go/core/kvstore"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	storePrefixBucket byte = 0
+	storePrefixCount  byte = 1
+)
+
+// ErrInvalidBucket is returned when a persisted bucket is malformed.
+var ErrInvalidBucket = errors.New("invalid output set bucket")
+
+// Set is a persisted set of output IDs.
+// Storing one key per output ID bloats the store quickly, so the outputs of the same transaction are compacted
+// into a single bucket, keyed by the transaction ID, that contains a bitmap of the output indexes.
+// Buckets shrink when outputs are removed and are deleted once they are empty.
+type Set struct {
+	store kvstore.KVStore
+
+	mutex sync.RWMutex
+	count uint64
+}
+
+// New creates a new Set that persists its entries in the given store.
+func New(store kvstore.KVStore) (*Set, error) {
+	s := &Set{
+		store: store,
+	}
+
+	value, err := store.Get([]byte{storePrefixCount})
+	if err != nil {
+		if errors.Is(err, kvstore.ErrKeyNotFound) {
+			return s, nil
+		}
+
+		return nil, err
+	}
+
+	if len(value) != 8 {
+		return nil, fmt.Errorf("%w: invalid count length: %d", ErrInvalidBucket, len(value))
+	}
+	s.count = binary.LittleEndian.Uint64(value)
+
+	return s, nil
+}
+
+func bucketKey(transactionID iotago.TransactionID) kvstore.Key {
+	key := make([]byte, 1+iotago.TransactionIDLength)
+	key[0] = storePrefixBucket
+	copy(key[1:], transactionID[:])
+
+	return key
+}
+
+func countValue(count uint64) kvstore.Value {
+	value := make([]byte, 8)
+	binary.LittleEndian.PutUint64(value, count)
+
+	return value
+}
+
+// bucket is the bitmap of the output indexes of a transaction that are contained in the set.
+type bucket []byte
+
+func (b bucket) contains(index uint16) bool {
+	byteIndex := int(index / 8)
+
+	return byteIndex < len(b) && b[byteIndex]&(1<<(index%8)) != 0
+}
+
+// set adds or removes the index and returns the modified bucket and whether it changed.
+func (b bucket) set(index uint16, contained bool) (bucket, bool) {
+	if b.contains(index) == contained {
+		return b, false
+	}
+
+	byteIndex := int(index / 8)
+	if contained {
+		for len(b) <= byteIndex {
+			b = append(b, 0)
+		}
+		b[byteIndex] |= 1 << (index % 8)
+
+		return b, true
+	}
+
+	b[byteIndex] &^= 1 << (index % 8)
+
+	// trim trailing empty bytes, so removed outputs free their space
+	for len(b) > 0 && b[len(b)-1] == 0 {
+		b = b[:len(b)-1]
+	}
+
+	return b, true
+}
+
+func (b bucket) forEach(transactionID iotago.TransactionID, consumer func(outputID iotago.OutputID) bool) bool {
+	for byteIndex, bits := range b {
+		if bits == 0 {
+			continue
+		}
+
+		for bit := 0; bit < 8; bit++ {
+			if bits&(1<<bit) == 0 {
+				continue
+			}
+
+			if !consumer(iotago.OutputIDFromTransactionIDAndIndex(transactionID, uint16(byteIndex*8+bit))) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// readBucket returns a copy of the persisted bucket of the transaction, it is empty if the bucket does not exist.
+func (s *Set) readBucket(transactionID iotago.TransactionID) (bucket, error) {
+	value, err := s.store.Get(bucketKey(transactionID))
+	if err != nil {
+		if errors.Is(err, kvstore.ErrKeyNotFound) {
+			return bucket{}, nil
+		}
+
+		return nil, err
+	}
+
+	return append(bucket{}, value...), nil
+}
+
+// update adds or removes the given output IDs in a single batch.
+func (s *Set) update(outputIDs []iotago.OutputID, contained bool) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	buckets := make(map[iotago.TransactionID]bucket)
+	count := s.count
+	for _, outputID := range outputIDs {
+		transactionID := outputID.TransactionID()
+
+		b, loaded := buckets[transactionID]
+		if !loaded {
+			var err error
+			if b, err = s.readBucket(transactionID); err != nil {
+				return err
+			}
+		}
+
+		b, changed := b.set(outputID.Index(), contained)
+		buckets[transactionID] = b
+		if !changed {
+			continue
+		}
+
+		if contained {
+			count++
+		} else {
+			count--
+		}
+	}
+
+	if count == s.count {
+		return nil
+	}
+
+	batch, err := s.store.Batched()
+	if err != nil {
+		return err
+	}
+
+	for transactionID, b := range buckets {
+		if len(b) == 0 {
+			err = batch.Delete(bucketKey(transactionID))
+		} else {
+			err = batch.Set(bucketKey(transactionID), kvstore.Value(b))
+		}
+		if err != nil {
+			batch.Cancel()
+
+			return err
+		}
+	}
+
+	if err := batch.Set([]byte{storePrefixCount}, countValue(count)); err != nil {
+		batch.Cancel()
+
+		return err
+	}
+
+	if err := batch.Commit(); err != nil {
+		return err
+	}
+	s.count = count
+
+	return nil
+}
+
+// Add adds the output IDs to the set, output IDs that are already contained are ignored.
+func (s *Set) Add(outputIDs ...iotago.OutputID) error {
+	return s.update(outputIDs, true)
+}
+
+// Remove removes the output IDs from the set, output IDs that are not contained are ignored.
+func (s *Set) Remove(outputIDs ...iotago.OutputID) error {
+	return s.update(outputIDs, false)
+}
+
+// Contains returns whether the output ID is contained in the set.
+func (s *Set) Contains(outputID iotago.OutputID) (bool, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	b, err := s.readBucket(outputID.TransactionID())
+	if err != nil {
+		return false, err
+	}
+
+	return b.contains(outputID.Index()), nil
+}
+
+// Count returns the amount of output IDs in the set.
+func (s *Set) Count() uint64 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.count
+}
+
+// ForEach calls the consumer for every output ID in the set, until it returns false.
+// The output IDs of the same transaction are passed in ascending order of their index.
+// The set must not be modified by the consumer.
+func (s *Set) ForEach(consumer func(outputID iotago.OutputID) bool) error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var innerErr error
+	if err := s.store.Iterate([]byte{storePrefixBucket}, func(key kvstore.Key, value kvstore.Value) bool {
+		if len(key) != 1+iotago.TransactionIDLength {
+			innerErr = fmt.Errorf("%w: invalid key length: %d", ErrInvalidBucket, len(key))
+
+			return false
+		}
+
+		var transactionID iotago.TransactionID
+		copy(transactionID[:], key[1:])
+
+		return bucket(value).forEach(transactionID, consumer)
+	}); err != nil {
+		return err
+	}
+
+	return innerErr
+}
+
+// Clear removes all output IDs from the set.
+func (s *Set) Clear() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.store.Clear(); err != nil {
+		return err
+	}
+	s.count = 0
+
+	return nil
+}
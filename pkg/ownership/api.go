@@ -0,0 +1,131 @@
+package ownership
+
+import (
+	"crypto/ed25519"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/inx-app/pkg/httpserver"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// RouteChallenge is the route to get a challenge.
+	// POST issues a new single-use challenge that has to be signed by the owner of the address.
+	RouteChallenge = "/ownership/challenge"
+
+	// RouteVerify is the route to verify the ownership of an address.
+	// POST verifies the signature of a challenge, the challenge can't be used again afterwards.
+	RouteVerify = "/ownership/verify"
+)
+
+// ChallengeResponse defines the response of a POST RouteChallenge REST API call.
+type ChallengeResponse struct {
+	// Challenge is the hex encoded challenge.
+	Challenge string `json:"challenge"`
+	// Message is the hex encoded message that has to be signed, i.e. MessagePrefix followed by the challenge.
+	Message string `json:"message"`
+	// ExpiresAt is the unix timestamp after which the challenge can't be used anymore.
+	ExpiresAt int64 `json:"expiresAt"`
+}
+
+// VerifyRequest defines the request of a POST RouteVerify REST API call.
+type VerifyRequest struct {
+	// Address is the address whose ownership is proven, either bech32 or hex encoded.
+	Address string `json:"address"`
+	// Challenge is the hex encoded challenge that was signed.
+	Challenge string `json:"challenge"`
+	// PublicKey is the hex encoded Ed25519 public key of the address.
+	PublicKey string `json:"publicKey"`
+	// Signature is the hex encoded Ed25519 signature of the message of the challenge.
+	Signature string `json:"signature"`
+}
+
+// VerifyResponse defines the response of a POST RouteVerify REST API call.
+type VerifyResponse struct {
+	// Valid is true if the ownership of the address was proven.
+	Valid bool `json:"valid"`
+	// Error describes why the proof is invalid.
+	Error string `json:"error,omitempty"`
+}
+
+// RegisterRoutes registers the address ownership routes on the given group.
+// Challenges are issued to unauthenticated clients, so the issuance is limited per client IP by the given rate limiter.
+func RegisterRoutes(routeGroup *echo.Group, nodeBridge *nodebridge.NodeBridge, challenges *Challenges, challengeRateLimiter *httpserver.RateLimiter) {
+	routeGroup.POST(RouteChallenge, ChallengeHandler(challenges), challengeRateLimiter.Middleware())
+	routeGroup.POST(RouteVerify, VerifyHandler(nodeBridge, challenges, nil))
+}
+
+// ChallengeHandler returns a handler that issues a new challenge.
+func ChallengeHandler(challenges *Challenges) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		challenge, expiresAt, err := challenges.Issue()
+		if err != nil {
+			if errors.Is(err, ErrTooManyChallenges) {
+				return errors.WithMessage(httpserver.ErrTooManyRequests, err.Error())
+			}
+
+			return err
+		}
+
+		return httpserver.JSONResponse(c, http.StatusOK, &ChallengeResponse{
+			Challenge: iotago.EncodeHex(challenge[:]),
+			Message:   iotago.EncodeHex(challenge.Message()),
+			ExpiresAt: expiresAt.Unix(),
+		})
+	}
+}
+
+// VerifyHandler returns a handler that verifies the ownership of an address.
+// If given, onVerified is called with the address after the ownership was proven,
+// e.g. to grant the client access to a feature. Its error is returned to the client.
+func VerifyHandler(nodeBridge *nodebridge.NodeBridge, challenges *Challenges, onVerified func(c echo.Context, address iotago.Address) error) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		request := &VerifyRequest{}
+		if err := httpserver.BindJSONBody(c, request); err != nil {
+			return err
+		}
+
+		address, err := httpserver.ParseAddressBodyParam("address", request.Address, nodeBridge.ProtocolParameters().Bech32HRP)
+		if err != nil {
+			return err
+		}
+
+		challengeBytes, err := httpserver.ParseFixedHexBodyParam("challenge", request.Challenge, ChallengeLength)
+		if err != nil {
+			return err
+		}
+
+		publicKeyBytes, err := httpserver.ParseFixedHexBodyParam("publicKey", request.PublicKey, ed25519.PublicKeySize)
+		if err != nil {
+			return err
+		}
+
+		signatureBytes, err := httpserver.ParseFixedHexBodyParam("signature", request.Signature, ed25519.SignatureSize)
+		if err != nil {
+			return err
+		}
+
+		var challenge Challenge
+		copy(challenge[:], challengeBytes)
+
+		signature := &iotago.Ed25519Signature{}
+		copy(signature.PublicKey[:], publicKeyBytes)
+		copy(signature.Signature[:], signatureBytes)
+
+		if err := challenges.VerifyChallenge(address, challenge, signature); err != nil {
+			return httpserver.JSONResponse(c, http.StatusOK, &VerifyResponse{Valid: false, Error: err.Error()})
+		}
+
+		if onVerified != nil {
+			if err := onVerified(c, address); err != nil {
+				return err
+			}
+		}
+
+		return httpserver.JSONResponse(c, http.StatusOK, &VerifyResponse{Valid: true})
+	}
+}
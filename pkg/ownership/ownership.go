@@ -0,0 +1,159 @@
+// Package ownership provides helpers and ready-made handlers to verify that a client owns an address,
+// e.g. for apps that gate features on address ownership.
+// The app issues a single-use challenge, the client signs it with the Ed25519 key of the address,
+// and the app verifies the signature and that the public key belongs to the address.
+package ownership
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// ChallengeLength is the length of a challenge in bytes.
+	ChallengeLength = 32
+
+	// MessagePrefix is prepended to the challenge before it is signed,
+	// so a signed challenge can never be a valid signature of a transaction essence.
+	MessagePrefix = "IOTA address ownership proof:"
+
+	// DefaultMaxChallenges is the default maximum amount of issued challenges that were not consumed yet.
+	DefaultMaxChallenges = 10_000
+)
+
+var (
+	// ErrChallengeUnknown is returned if a challenge was not issued, already expired or was already used.
+	ErrChallengeUnknown = errors.New("unknown challenge")
+	// ErrUnsupportedAddress is returned if the ownership of an address that is not an Ed25519 address should be verified.
+	ErrUnsupportedAddress = errors.New("only the ownership of Ed25519 addresses can be verified")
+	// ErrTooManyChallenges is returned if no challenge can be issued, because the maximum amount of open challenges is reached.
+	ErrTooManyChallenges = errors.New("too many open challenges")
+)
+
+// Challenge is a random value that has to be signed by the owner of an address.
+type Challenge [ChallengeLength]byte
+
+// Message returns the message the owner of an address signs to prove the ownership.
+func (c Challenge) Message() []byte {
+	return append([]byte(MessagePrefix), c[:]...)
+}
+
+// Verify verifies that the signature of the challenge was created with the key of the given address.
+func Verify(address iotago.Address, challenge Challenge, signature *iotago.Ed25519Signature) error {
+	ed25519Address, ok := address.(*iotago.Ed25519Address)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnsupportedAddress, address.Type())
+	}
+
+	return signature.Valid(challenge.Message(), ed25519Address)
+}
+
+// Challenges issues single-use challenges that expire after a given duration.
+// Consuming a challenge before verifying the signature prevents replays of signed challenges.
+type Challenges struct {
+	ttl           time.Duration
+	maxChallenges int
+
+	mutex      sync.Mutex
+	challenges map[Challenge]time.Time
+	lastPrune  time.Time
+}
+
+// WithMaxChallenges sets the maximum amount of issued challenges that were not consumed yet.
+// Challenges are issued to unauthenticated clients, the limit bounds the memory they can occupy.
+func WithMaxChallenges(maxChallenges int) options.Option[Challenges] {
+	return func(c *Challenges) {
+		c.maxChallenges = maxChallenges
+	}
+}
+
+// NewChallenges creates new Challenges, the issued challenges expire after the given duration.
+func NewChallenges(ttl time.Duration, opts ...options.Option[Challenges]) *Challenges {
+	return options.Apply(&Challenges{
+		ttl:           ttl,
+		maxChallenges: DefaultMaxChallenges,
+		challenges:    make(map[Challenge]time.Time),
+	}, opts)
+}
+
+// Issue creates a new random challenge and returns it with its expiration time.
+// It fails with ErrTooManyChallenges if the maximum amount of open challenges is reached, even after pruning the expired ones.
+func (c *Challenges) Issue() (Challenge, time.Time, error) {
+	var challenge Challenge
+	if _, err := rand.Read(challenge[:]); err != nil {
+		return Challenge{}, time.Time{}, fmt.Errorf("creating challenge failed: %w", err)
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(c.ttl)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.pruneWithoutLocking(now, false)
+	if len(c.challenges) >= c.maxChallenges {
+		c.pruneWithoutLocking(now, true)
+		if len(c.challenges) >= c.maxChallenges {
+			return Challenge{}, time.Time{}, ErrTooManyChallenges
+		}
+	}
+	c.challenges[challenge] = expiresAt
+
+	return challenge, expiresAt, nil
+}
+
+// Consume removes the challenge, it fails with ErrChallengeUnknown if the challenge was not issued or is expired.
+func (c *Challenges) Consume(challenge Challenge) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	expiresAt, exists := c.challenges[challenge]
+	if !exists {
+		return ErrChallengeUnknown
+	}
+	delete(c.challenges, challenge)
+
+	if time.Now().After(expiresAt) {
+		return ErrChallengeUnknown
+	}
+
+	return nil
+}
+
+// Size returns the amount of issued challenges that were not consumed or pruned yet.
+func (c *Challenges) Size() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return len(c.challenges)
+}
+
+// pruneWithoutLocking removes the expired challenges, so unused challenges don't pile up.
+// Unless forced, it runs at most once per ttl, so issuing a challenge stays cheap.
+func (c *Challenges) pruneWithoutLocking(now time.Time, force bool) {
+	if !force && now.Sub(c.lastPrune) < c.ttl {
+		return
+	}
+	c.lastPrune = now
+
+	for challenge, expiresAt := range c.challenges {
+		if now.After(expiresAt) {
+			delete(c.challenges, challenge)
+		}
+	}
+}
+
+// VerifyChallenge consumes the challenge and verifies that its signature was created with the key of the given address.
+func (c *Challenges) VerifyChallenge(address iotago.Address, challenge Challenge, signature *iotago.Ed25519Signature) error {
+	if err := c.Consume(challenge); err != nil {
+		return err
+	}
+
+	return Verify(address, challenge, signature)
+}
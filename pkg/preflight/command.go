@@ -0,0 +1,105 @@
+package preflight
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	inx "github.com/iotaledger/inx/go"
+)
+
+// CommandName is the name of the preflight subcommand, e.g. "inx-indexer preflight --inx.address=localhost:9029".
+const CommandName = "preflight"
+
+const (
+	exitCodePassed = 0
+	exitCodeFailed = 1
+	exitCodeUsage  = 2
+)
+
+// IsCommand tells whether the arguments of the app, without the program name, invoke the preflight subcommand.
+func IsCommand(args []string) bool {
+	return len(args) > 0 && args[0] == CommandName
+}
+
+// RunCommand runs the preflight subcommand with the given arguments, which follow the command name,
+// writes the report to the writer and returns the exit code of the app, e.g.
+//
+//	if preflight.IsCommand(os.Args[1:]) {
+//		os.Exit(preflight.RunCommand(context.Background(), os.Args[2:], os.Stdout))
+//	}
+func RunCommand(ctx context.Context, args []string, w io.Writer) int {
+	flagSet := flag.NewFlagSet(CommandName, flag.ContinueOnError)
+	flagSet.SetOutput(w)
+
+	inxAddress := flagSet.String("inx.address", "localhost:9029", "the INX address to which to connect to")
+	route := flagSet.String("route", "", "the API route whose registration is checked, e.g. \"indexer/v1\" (optional)")
+	bindAddress := flagSet.String("bindAddress", "localhost:9091", "the bind address of the API that the route is registered for")
+	storage := flagSet.String("storage", "", "comma-separated directories that must be writable, e.g. the database path (optional)")
+	maxClockSkew := flagSet.Duration("maxClockSkew", time.Minute, "the maximum deviation of the local clock from the latest milestone")
+	timeout := flagSet.Duration("timeout", 5*time.Second, "the timeout of every check")
+
+	if err := flagSet.Parse(args); err != nil {
+		return exitCodeUsage
+	}
+
+	conn, err := grpc.DialContext(ctx, *inxAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		fmt.Fprintf(w, "connecting to INX failed: %s\n", err)
+
+		return exitCodeFailed
+	}
+	defer func() { _ = conn.Close() }()
+
+	client := inx.NewINXClient(conn)
+
+	checks := []*Check{
+		INXConnectivity(client),
+		INXPermissions(client),
+	}
+
+	if *route != "" {
+		host, portString, err := net.SplitHostPort(*bindAddress)
+		if err != nil {
+			fmt.Fprintf(w, "invalid bind address %s: %s\n", *bindAddress, err)
+
+			return exitCodeUsage
+		}
+
+		port, err := strconv.ParseUint(portString, 10, 32)
+		if err != nil {
+			fmt.Fprintf(w, "invalid port of bind address %s: %s\n", *bindAddress, err)
+
+			return exitCodeUsage
+		}
+
+		checks = append(checks, RouteRegistration(client, *route, host, uint32(port)))
+	}
+
+	for _, dir := range strings.Split(*storage, ",") {
+		if dir = strings.TrimSpace(dir); dir != "" {
+			checks = append(checks, StorageWritable(dir))
+		}
+	}
+
+	checks = append(checks, ClockSanity(client, *maxClockSkew))
+
+	report := Run(ctx, *timeout, checks...)
+	if err := report.Write(w); err != nil {
+		return exitCodeFailed
+	}
+
+	if !report.Passed() {
+		return exitCodeFailed
+	}
+
+	return exitCodePassed
+}
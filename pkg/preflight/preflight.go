@@ -0,0 +1,279 @@
+// Package preflight verifies the environment of an app before it starts, like the connection to the node via INX,
+// the registration of API routes, the writability of the storage and the sanity of the clock.
+// It produces a pass/fail report for operators, either programmatically or via the preflight command.
+package preflight
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	inx "github.com/iotaledger/inx/go"
+)
+
+var (
+	// ErrPermissionDenied is returned if the node denied an INX call the app needs.
+	ErrPermissionDenied = errors.New("permission denied by the node")
+	// ErrClockSkew is returned if the local clock deviates too much from the timestamp of the latest milestone.
+	ErrClockSkew = errors.New("clock skew too large")
+	// ErrNodeNotSynced is returned by the clock check if the node is not synced, so its milestones can't be used as reference.
+	ErrNodeNotSynced = errors.New("node not synced")
+)
+
+// CheckFunc checks a part of the environment, it returns an error describing the problem if the check failed.
+type CheckFunc func(ctx context.Context) error
+
+// Check is a named CheckFunc.
+type Check struct {
+	// Name is the name of the check shown in the report.
+	Name string
+	// Check runs the check.
+	Check CheckFunc
+	// Optional checks don't fail the report, they are only reported as warnings.
+	Optional bool
+}
+
+// Result is the result of a single check.
+type Result struct {
+	// Name is the name of the check.
+	Name string
+	// Optional tells whether the check does not fail the report.
+	Optional bool
+	// Err is the error of the failed check, nil if it passed.
+	Err error
+	// Duration is the time it took to run the check.
+	Duration time.Duration
+}
+
+// Passed tells whether the check passed.
+func (r *Result) Passed() bool {
+	return r.Err == nil
+}
+
+// Report is the result of all checks.
+type Report struct {
+	// Results are the results of the checks, in the order they were run.
+	Results []*Result
+}
+
+// Passed tells whether all mandatory checks passed.
+func (r *Report) Passed() bool {
+	for _, result := range r.Results {
+		if !result.Passed() && !result.Optional {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Write writes the report in a human-readable form, one line per check and a summary.
+func (r *Report) Write(w io.Writer) error {
+	var sb strings.Builder
+
+	for _, result := range r.Results {
+		state := "PASS"
+		switch {
+		case result.Passed():
+		case result.Optional:
+			state = "WARN"
+		default:
+			state = "FAIL"
+		}
+
+		sb.WriteString(fmt.Sprintf("[%s] %s (%v)", state, result.Name, result.Duration.Truncate(time.Millisecond)))
+		if result.Err != nil {
+			sb.WriteString(": " + result.Err.Error())
+		}
+		sb.WriteString("\n")
+	}
+
+	if r.Passed() {
+		sb.WriteString("preflight checks passed\n")
+	} else {
+		sb.WriteString("preflight checks failed\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// Run runs the checks one after another, every check is limited to the given timeout.
+// All checks are run, even if a previous one failed, so the report is complete.
+func Run(ctx context.Context, timeout time.Duration, checks ...*Check) *Report {
+	report := &Report{Results: make([]*Result, 0, len(checks))}
+
+	for _, check := range checks {
+		checkCtx, checkCancel := context.WithTimeout(ctx, timeout)
+		start := time.Now()
+		err := check.Check(checkCtx)
+		checkCancel()
+
+		report.Results = append(report.Results, &Result{
+			Name:     check.Name,
+			Optional: check.Optional,
+			Err:      err,
+			Duration: time.Since(start),
+		})
+	}
+
+	return report
+}
+
+// inxError reports denied calls with ErrPermissionDenied, so they are told apart from connection problems.
+func inxError(call string, err error) error {
+	switch status.Code(err) {
+	case codes.PermissionDenied, codes.Unauthenticated, codes.Unimplemented:
+		return fmt.Errorf("%w: %s: %s", ErrPermissionDenied, call, err)
+	default:
+		return fmt.Errorf("%s failed: %w", call, err)
+	}
+}
+
+// INXConnectivity checks that the node is reachable via INX and answers the basic requests.
+func INXConnectivity(client inx.INXClient) *Check {
+	return &Check{
+		Name: "INX connectivity",
+		Check: func(ctx context.Context) error {
+			if _, err := client.ReadNodeConfiguration(ctx, &inx.NoParams{}); err != nil {
+				return inxError("ReadNodeConfiguration", err)
+			}
+
+			if _, err := client.ReadNodeStatus(ctx, &inx.NoParams{}); err != nil {
+				return inxError("ReadNodeStatus", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// INXPermissions checks that the node allows the INX calls of the app, e.g. "ReadProtocolParameters".
+// The calls are only made to see whether they are denied, their results are ignored.
+func INXPermissions(client inx.INXClient) *Check {
+	return &Check{
+		Name: "INX permissions",
+		Check: func(ctx context.Context) error {
+			if _, err := client.ReadProtocolParameters(ctx, &inx.MilestoneRequest{}); err != nil {
+				return inxError("ReadProtocolParameters", err)
+			}
+
+			if _, err := client.RequestTips(ctx, &inx.TipsRequest{Count: 1}); err != nil {
+				return inxError("RequestTips", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// RouteRegistration checks that the node allows the app to register API routes.
+// The API route itself may already be registered by a running instance of the app, so it is never touched.
+// A unique probe route next to it is registered and unregistered instead.
+func RouteRegistration(client inx.INXClient, route string, host string, port uint32) *Check {
+	return &Check{
+		Name: "API route registration",
+		Check: func(ctx context.Context) error {
+			suffix := make([]byte, 8)
+			if _, err := rand.Read(suffix); err != nil {
+				return fmt.Errorf("generating probe route failed: %w", err)
+			}
+			probeRoute := fmt.Sprintf("%s-preflight-%s", route, hex.EncodeToString(suffix))
+
+			if _, err := client.RegisterAPIRoute(ctx, &inx.APIRouteRequest{Route: probeRoute, Host: host, Port: port}); err != nil {
+				return inxError("RegisterAPIRoute", err)
+			}
+
+			// only the probe route added by this check is unregistered
+			if _, err := client.UnregisterAPIRoute(ctx, &inx.APIRouteRequest{Route: probeRoute}); err != nil {
+				return inxError("UnregisterAPIRoute", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// StorageWritable checks that a file can be created, written and removed in the given directory.
+// The directory is created if it does not exist yet, like the database would.
+func StorageWritable(dir string) *Check {
+	return &Check{
+		Name: fmt.Sprintf("storage writable (%s)", dir),
+		Check: func(_ context.Context) error {
+			if err := os.MkdirAll(dir, 0o700); err != nil {
+				return fmt.Errorf("creating directory failed: %w", err)
+			}
+
+			file, err := os.CreateTemp(dir, ".preflight-*")
+			if err != nil {
+				return fmt.Errorf("creating file failed: %w", err)
+			}
+			filePath := file.Name()
+			defer func() { _ = os.Remove(filePath) }()
+
+			if _, err := file.Write([]byte("preflight")); err != nil {
+				_ = file.Close()
+
+				return fmt.Errorf("writing file failed: %w", err)
+			}
+
+			if err := file.Sync(); err != nil {
+				_ = file.Close()
+
+				return fmt.Errorf("syncing file failed: %w", err)
+			}
+
+			if err := file.Close(); err != nil {
+				return fmt.Errorf("closing file failed: %w", err)
+			}
+
+			if err := os.Remove(filePath); err != nil {
+				return fmt.Errorf("removing file %s failed: %w", filepath.Base(filePath), err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// ClockSanity checks that the local clock deviates at most maxSkew from the timestamp of the latest milestone of the node.
+// The check is optional, because the reference is only meaningful if the node is synced.
+func ClockSanity(client inx.INXClient, maxSkew time.Duration) *Check {
+	return &Check{
+		Name:     "clock sanity",
+		Optional: true,
+		Check: func(ctx context.Context) error {
+			nodeStatus, err := client.ReadNodeStatus(ctx, &inx.NoParams{})
+			if err != nil {
+				return inxError("ReadNodeStatus", err)
+			}
+
+			if !nodeStatus.GetIsSynced() {
+				return ErrNodeNotSynced
+			}
+
+			milestoneTime := time.Unix(int64(nodeStatus.GetLatestMilestone().GetMilestoneInfo().GetMilestoneTimestamp()), 0)
+			skew := time.Since(milestoneTime)
+			if skew < 0 {
+				skew = -skew
+			}
+
+			if skew > maxSkew {
+				return fmt.Errorf("%w: local time %s, latest milestone %s", ErrClockSkew, time.Now().UTC().Format(time.RFC3339), milestoneTime.UTC().Format(time.RFC3339))
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,158 @@
+package proof
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotaledger/inx-app/pkg/httpserver"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+	"github.com/iotaledger/iota.go/v3/keymanager"
+)
+
+const (
+	// ParameterBlockID is used to identify a block by its ID.
+	ParameterBlockID = "blockID"
+
+	// RouteBlockProof is the route to get the proof that a block is referenced by a milestone.
+	// GET returns the proof as JSON or as binary if the "application/vnd.iota.serializer-v1" Accept header is set.
+	RouteBlockProof = "/blocks/:" + ParameterBlockID + "/proof"
+
+	// RouteBlockAppliedProof is the route to get the proof that the transaction of a block was applied to the ledger.
+	// GET returns the proof as JSON or as binary if the "application/vnd.iota.serializer-v1" Accept header is set.
+	RouteBlockAppliedProof = "/blocks/:" + ParameterBlockID + "/proof/applied"
+
+	// RouteValidateProof is the route to validate a proof, including the signatures of its milestone.
+	// POST accepts the proof as JSON or as binary if the "application/vnd.iota.serializer-v1" Content-Type header is set.
+	RouteValidateProof = "/proofs/validate"
+)
+
+// maximum size of a proof sent to the validate endpoint.
+const maxProofRequestBodySize = 1 << 20
+
+// ValidateProofResponse defines the response of a POST RouteValidateProof REST API call.
+type ValidateProofResponse struct {
+	// Valid is true if the proof is valid.
+	Valid bool `json:"valid"`
+	// Error describes why the proof is invalid.
+	Error string `json:"error,omitempty"`
+}
+
+// RegisterRoutes registers the proof-of-inclusion routes on the given group.
+// The milestone public keys and the signature threshold of the network are needed to validate proofs.
+func RegisterRoutes(routeGroup *echo.Group, nodeBridge *nodebridge.NodeBridge, keyManager *keymanager.KeyManager, milestoneSigThreshold int) {
+	routeGroup.GET(RouteBlockProof, BlockProofHandler(nodeBridge))
+	routeGroup.GET(RouteBlockAppliedProof, BlockAppliedProofHandler(nodeBridge))
+	routeGroup.POST(RouteValidateProof, ValidateProofHandler(keyManager, milestoneSigThreshold))
+}
+
+// BlockProofHandler returns a handler that creates the proof that a block is referenced by a milestone.
+func BlockProofHandler(nodeBridge *nodebridge.NodeBridge) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		blockID, err := httpserver.ParseBlockIDParam(c, ParameterBlockID)
+		if err != nil {
+			return err
+		}
+
+		proof, err := CreateProof(c.Request().Context(), nodeBridge, blockID)
+		if err != nil {
+			return proofError(err)
+		}
+
+		return proofResponse(c, proof)
+	}
+}
+
+// BlockAppliedProofHandler returns a handler that creates the proof that the transaction of a block was applied to the ledger.
+func BlockAppliedProofHandler(nodeBridge *nodebridge.NodeBridge) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		blockID, err := httpserver.ParseBlockIDParam(c, ParameterBlockID)
+		if err != nil {
+			return err
+		}
+
+		proof, err := CreateAppliedProof(c.Request().Context(), nodeBridge, blockID)
+		if err != nil {
+			return proofError(err)
+		}
+
+		return proofResponse(c, proof)
+	}
+}
+
+// ValidateProofHandler returns a handler that validates a proof without node access.
+// A proof is only valid if its audit path leads to the merkle root of the milestone
+// and the milestone is signed by at least milestoneSigThreshold of the applicable keys of the key manager.
+func ValidateProofHandler(keyManager *keymanager.KeyManager, milestoneSigThreshold int) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		mimeType, err := httpserver.GetRequestContentType(c, httpserver.MIMEApplicationVendorIOTASerializerV1, echo.MIMEApplicationJSON)
+		if err != nil {
+			return err
+		}
+
+		proof := &Proof{}
+		switch mimeType {
+		case httpserver.MIMEApplicationVendorIOTASerializerV1:
+			data, err := io.ReadAll(io.LimitReader(c.Request().Body, maxProofRequestBodySize))
+			if err != nil {
+				return errors.WithMessagef(httpserver.ErrInvalidParameter, "unable to read proof, error: %s", err)
+			}
+
+			if proof, err = FromBytes(data); err != nil {
+				return errors.WithMessagef(httpserver.ErrInvalidParameter, "invalid proof, error: %s", err)
+			}
+
+		default:
+			c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, maxProofRequestBodySize)
+			if err := c.Bind(proof); err != nil {
+				return errors.WithMessagef(httpserver.ErrInvalidParameter, "invalid proof, error: %s", err)
+			}
+		}
+
+		if err := proof.Verify(); err != nil {
+			return httpserver.JSONResponse(c, http.StatusOK, &ValidateProofResponse{Valid: false, Error: err.Error()})
+		}
+
+		// the merkle root is only trustworthy if the milestone was issued by the coordinator
+		if err := proof.VerifyMilestone(milestoneSigThreshold, keyManager.PublicKeysSetForMilestoneIndex(proof.Milestone.Index)); err != nil {
+			return httpserver.JSONResponse(c, http.StatusOK, &ValidateProofResponse{Valid: false, Error: err.Error()})
+		}
+
+		return httpserver.JSONResponse(c, http.StatusOK, &ValidateProofResponse{Valid: true})
+	}
+}
+
+func proofResponse(c echo.Context, proof *Proof) error {
+	mimeType, err := httpserver.GetAcceptHeaderContentType(c, httpserver.MIMEApplicationVendorIOTASerializerV1, echo.MIMEApplicationJSON)
+	if err != nil && !errors.Is(err, httpserver.ErrNotAcceptable) {
+		return err
+	}
+
+	switch mimeType {
+	case httpserver.MIMEApplicationVendorIOTASerializerV1:
+		data, err := proof.Bytes()
+		if err != nil {
+			return err
+		}
+
+		return c.Blob(http.StatusOK, httpserver.MIMEApplicationVendorIOTASerializerV1, data)
+
+	default:
+		return httpserver.JSONResponse(c, http.StatusOK, proof)
+	}
+}
+
+func proofError(err error) error {
+	switch {
+	case errors.Is(err, ErrBlockNotReferenced), errors.Is(err, ErrBlockNotIncluded):
+		return errors.WithMessage(echo.ErrBadRequest, err.Error())
+	case status.Code(err) == codes.NotFound:
+		return errors.WithMessage(echo.ErrNotFound, err.Error())
+	default:
+		return err
+	}
+}
@@ -0,0 +1,188 @@
+package proof
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+
+	// import implementation.
+	_ "golang.org/x/crypto/blake2b"
+
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+	inx "github.com/iotaledger/inx/go"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/merklehasher"
+)
+
+// MerkleRoot is the milestone merkle root a proof was created for.
+type MerkleRoot string
+
+const (
+	// MerkleRootInclusion is the merkle root of all blocks referenced by the milestone.
+	MerkleRootInclusion MerkleRoot = "inclusion"
+	// MerkleRootApplied is the merkle root of all blocks referenced by the milestone that mutated the ledger.
+	MerkleRootApplied MerkleRoot = "applied"
+)
+
+var (
+	// ErrBlockNotReferenced is returned when a proof is requested for a block that is not referenced by a milestone yet.
+	ErrBlockNotReferenced = errors.New("block is not referenced by a milestone")
+	// ErrBlockNotIncluded is returned when an applied proof is requested for a block that did not mutate the ledger.
+	ErrBlockNotIncluded = errors.New("block did not mutate the ledger")
+	// ErrInvalidProof is returned when a proof does not prove the inclusion of its block.
+	ErrInvalidProof = errors.New("invalid proof")
+)
+
+// Proof proves that a block is part of the merkle tree committed to by a milestone.
+// It contains everything needed to verify it, so external parties don't have to trust the app that created it.
+type Proof struct {
+	// Root is the milestone merkle root the audit path leads to.
+	Root MerkleRoot `json:"root"`
+	// Milestone is the milestone that referenced the block.
+	Milestone *iotago.Milestone `json:"milestone"`
+	// Block is the proven block.
+	Block *iotago.Block `json:"block"`
+	// AuditPath is the merkle audit path from the block to the merkle root.
+	// It is nil if the block is the only leaf of the tree.
+	AuditPath *merklehasher.Proof `json:"proof,omitempty"`
+}
+
+// CreateProof creates a proof that the block is referenced by the milestone that confirmed it.
+func CreateProof(ctx context.Context, nodeBridge *nodebridge.NodeBridge, blockID iotago.BlockID) (*Proof, error) {
+	return createProof(ctx, nodeBridge, blockID, MerkleRootInclusion)
+}
+
+// CreateAppliedProof creates a proof that the transaction of the block was applied to the ledger by the milestone that confirmed it.
+func CreateAppliedProof(ctx context.Context, nodeBridge *nodebridge.NodeBridge, blockID iotago.BlockID) (*Proof, error) {
+	return createProof(ctx, nodeBridge, blockID, MerkleRootApplied)
+}
+
+func createProof(ctx context.Context, nodeBridge *nodebridge.NodeBridge, blockID iotago.BlockID, root MerkleRoot) (*Proof, error) {
+	metadata, err := nodeBridge.BlockMetadata(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	milestoneIndex := metadata.GetReferencedByMilestoneIndex()
+	if milestoneIndex == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrBlockNotReferenced, blockID.ToHex())
+	}
+
+	if root == MerkleRootApplied && metadata.GetLedgerInclusionState() != inx.BlockMetadata_LEDGER_INCLUSION_STATE_INCLUDED {
+		return nil, fmt.Errorf("%w: %s", ErrBlockNotIncluded, blockID.ToHex())
+	}
+
+	milestone, err := nodeBridge.Milestone(ctx, milestoneIndex)
+	if err != nil {
+		return nil, err
+	}
+	if milestone == nil {
+		return nil, fmt.Errorf("milestone %d not found", milestoneIndex)
+	}
+
+	block, err := nodeBridge.Block(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	cone, err := nodeBridge.MilestoneConeMetadataOrdered(ctx, milestoneIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	if root == MerkleRootApplied {
+		included := make([]*inx.BlockMetadata, 0, len(cone))
+		for _, m := range cone {
+			if m.GetLedgerInclusionState() == inx.BlockMetadata_LEDGER_INCLUSION_STATE_INCLUDED {
+				included = append(included, m)
+			}
+		}
+		cone = included
+	}
+
+	blockIDs := nodebridge.WhiteFlagOrderedBlockIDs(cone)
+
+	proof := &Proof{
+		Root:      root,
+		Milestone: milestone.Milestone,
+		Block:     block,
+	}
+
+	// the merkle tree of a single leaf has no audit path
+	if len(blockIDs) > 1 {
+		auditPath, err := merklehasher.NewHasher(crypto.BLAKE2b_256).ComputeProof(blockIDs, blockID)
+		if err != nil {
+			return nil, err
+		}
+		proof.AuditPath = auditPath
+	}
+
+	if err := proof.Verify(); err != nil {
+		return nil, err
+	}
+
+	return proof, nil
+}
+
+// merkleRoot returns the merkle root of the milestone the proof was created for.
+func (p *Proof) merkleRoot() ([]byte, error) {
+	switch p.Root {
+	case MerkleRootInclusion:
+		return p.Milestone.InclusionMerkleRoot[:], nil
+	case MerkleRootApplied:
+		return p.Milestone.AppliedMerkleRoot[:], nil
+	default:
+		return nil, fmt.Errorf("%w: unknown merkle root \"%s\"", ErrInvalidProof, p.Root)
+	}
+}
+
+// Verify checks that the audit path of the proof leads from the block to the merkle root of the milestone.
+// It does not verify the milestone itself, use VerifyMilestone to check its signatures.
+func (p *Proof) Verify() error {
+	if p.Milestone == nil || p.Block == nil {
+		return fmt.Errorf("%w: milestone or block missing", ErrInvalidProof)
+	}
+
+	blockID, err := p.Block.ID()
+	if err != nil {
+		return fmt.Errorf("%w: unable to compute block ID: %s", ErrInvalidProof, err.Error())
+	}
+
+	merkleRoot, err := p.merkleRoot()
+	if err != nil {
+		return err
+	}
+
+	hasher := merklehasher.NewHasher(crypto.BLAKE2b_256)
+
+	var hash []byte
+	if p.AuditPath == nil {
+		hash = hasher.HashBlockIDs(iotago.BlockIDs{blockID})
+	} else {
+		contained, err := p.AuditPath.ContainsValue(blockID)
+		if err != nil {
+			return err
+		}
+		if !contained {
+			return fmt.Errorf("%w: block %s is not part of the audit path", ErrInvalidProof, blockID.ToHex())
+		}
+		hash = p.AuditPath.Hash(hasher)
+	}
+
+	if !bytes.Equal(hash, merkleRoot) {
+		return fmt.Errorf("%w: audit path does not match the %s merkle root of milestone %d", ErrInvalidProof, p.Root, p.Milestone.Index)
+	}
+
+	return nil
+}
+
+// VerifyMilestone checks that the milestone of the proof is signed by enough of the given milestone public keys.
+func (p *Proof) VerifyMilestone(minSigThreshold int, applicablePubKeys iotago.MilestonePublicKeySet) error {
+	if p.Milestone == nil {
+		return fmt.Errorf("%w: milestone missing", ErrInvalidProof)
+	}
+
+	return p.Milestone.VerifySignatures(minSigThreshold, applicablePubKeys)
+}
@@ -0,0 +1,213 @@
+package proof
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/keymanager"
+	"github.com/iotaledger/iota.go/v3/merklehasher"
+)
+
+func testBlock(t *testing.T, tag byte) *iotago.Block {
+	t.Helper()
+
+	return &iotago.Block{
+		ProtocolVersion: 2,
+		Parents:         iotago.BlockIDs{{tag}},
+		Payload:         &iotago.TaggedData{Tag: []byte{tag}, Data: []byte("proof")},
+	}
+}
+
+type testMilestone struct {
+	keyManager *keymanager.KeyManager
+	privateKey ed25519.PrivateKey
+}
+
+func newTestMilestone(t *testing.T) *testMilestone {
+	t.Helper()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyManager := keymanager.New()
+	keyManager.AddKeyRange(publicKey, 0, 0)
+
+	return &testMilestone{keyManager: keyManager, privateKey: privateKey}
+}
+
+// proofOf creates an inclusion proof of the block at the given position of the blocks, signed by the test key.
+func (m *testMilestone) proofOf(t *testing.T, blocks []*iotago.Block, position int) *Proof {
+	t.Helper()
+
+	blockIDs := make(iotago.BlockIDs, len(blocks))
+	for i, block := range blocks {
+		blockIDs[i] = block.MustID()
+	}
+
+	hasher := merklehasher.NewHasher(crypto.BLAKE2b_256)
+
+	var inclusionMerkleRoot iotago.MilestoneMerkleProof
+	copy(inclusionMerkleRoot[:], hasher.HashBlockIDs(blockIDs))
+
+	milestone := iotago.NewMilestone(10, 1_000, 2, iotago.MilestoneID{}, iotago.BlockIDs{{1}}, inclusionMerkleRoot, iotago.MilestoneMerkleProof{})
+
+	var publicKey iotago.MilestonePublicKey
+	copy(publicKey[:], m.privateKey.Public().(ed25519.PublicKey))
+	if err := milestone.Sign([]iotago.MilestonePublicKey{publicKey}, iotago.InMemoryEd25519MilestoneSigner(iotago.MilestonePublicKeyMapping{publicKey: m.privateKey})); err != nil {
+		t.Fatal(err)
+	}
+
+	proof := &Proof{
+		Root:      MerkleRootInclusion,
+		Milestone: milestone,
+		Block:     blocks[position],
+	}
+
+	if len(blockIDs) > 1 {
+		auditPath, err := hasher.ComputeProof(blockIDs, blockIDs[position])
+		if err != nil {
+			t.Fatal(err)
+		}
+		proof.AuditPath = auditPath
+	}
+
+	return proof
+}
+
+func (m *testMilestone) verify(p *Proof) error {
+	if err := p.Verify(); err != nil {
+		return err
+	}
+
+	return p.VerifyMilestone(1, m.keyManager.PublicKeysSetForMilestoneIndex(p.Milestone.Index))
+}
+
+func TestProofVerify(t *testing.T) {
+	milestone := newTestMilestone(t)
+	blocks := []*iotago.Block{testBlock(t, 1), testBlock(t, 2), testBlock(t, 3), testBlock(t, 4), testBlock(t, 5)}
+
+	tests := []struct {
+		name    string
+		proof   func() *Proof
+		wantErr bool
+	}{
+		{
+			name:  "single leaf",
+			proof: func() *Proof { return milestone.proofOf(t, blocks[:1], 0) },
+		},
+		{
+			name:  "first leaf",
+			proof: func() *Proof { return milestone.proofOf(t, blocks, 0) },
+		},
+		{
+			name:  "last leaf",
+			proof: func() *Proof { return milestone.proofOf(t, blocks, len(blocks)-1) },
+		},
+		{
+			name: "other block",
+			proof: func() *Proof {
+				proof := milestone.proofOf(t, blocks, 1)
+				proof.Block = testBlock(t, 9)
+
+				return proof
+			},
+			wantErr: true,
+		},
+		{
+			name: "wrong merkle root",
+			proof: func() *Proof {
+				proof := milestone.proofOf(t, blocks, 1)
+				proof.Root = MerkleRootApplied
+
+				return proof
+			},
+			wantErr: true,
+		},
+		{
+			name: "forged milestone",
+			proof: func() *Proof {
+				proof := milestone.proofOf(t, blocks, 2)
+				// the merkle path still matches, but the milestone was not signed by the coordinator
+				forged := newTestMilestone(t).proofOf(t, blocks, 2)
+				proof.Milestone = forged.Milestone
+
+				return proof
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := milestone.verify(test.proof())
+			if test.wantErr && err == nil {
+				t.Fatal("expected an error")
+			}
+			if !test.wantErr && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestProofRoundTrip(t *testing.T) {
+	milestone := newTestMilestone(t)
+	blocks := []*iotago.Block{testBlock(t, 1), testBlock(t, 2), testBlock(t, 3)}
+
+	for _, proof := range []*Proof{milestone.proofOf(t, blocks[:1], 0), milestone.proofOf(t, blocks, 1)} {
+		data, err := proof.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoded, err := FromBytes(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := milestone.verify(decoded); err != nil {
+			t.Fatalf("decoded binary proof is invalid: %s", err)
+		}
+
+		jsonData, err := json.Marshal(proof)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decodedJSON := &Proof{}
+		if err := json.Unmarshal(jsonData, decodedJSON); err != nil {
+			t.Fatal(err)
+		}
+		if err := milestone.verify(decodedJSON); err != nil {
+			t.Fatalf("decoded JSON proof is invalid: %s", err)
+		}
+	}
+}
+
+func TestFromBytesInvalid(t *testing.T) {
+	proof := newTestMilestone(t).proofOf(t, []*iotago.Block{testBlock(t, 1), testBlock(t, 2)}, 0)
+	data, err := proof.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string][]byte{
+		"empty":           nil,
+		"unknown version": append([]byte{0xff}, data[1:]...),
+		"truncated":       data[:len(data)-1],
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := FromBytes(data); !errors.Is(err, ErrInvalidProof) {
+				t.Fatalf("expected ErrInvalidProof, got %v", err)
+			}
+		})
+	}
+}
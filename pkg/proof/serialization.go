@@ -0,0 +1,271 @@
+package proof
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/iotaledger/hive.go/serializer/v2"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/merklehasher"
+)
+
+const (
+	serializationVersion byte = 1
+
+	merkleRootInclusionByte byte = 0
+	merkleRootAppliedByte   byte = 1
+
+	auditPathNone byte = 0
+	auditPathNode byte = 1
+	auditPathLeaf byte = 2
+	auditPathHash byte = 3
+)
+
+// the JSON representation of the nodes of a merklehasher.Proof.
+type jsonAuditPathNode struct {
+	Left  *jsonAuditPathNode `json:"l,omitempty"`
+	Right *jsonAuditPathNode `json:"r,omitempty"`
+	Value string             `json:"value,omitempty"`
+	Hash  string             `json:"h,omitempty"`
+}
+
+func writeAuditPathNode(buf *bytes.Buffer, node *jsonAuditPathNode) error {
+	switch {
+	case node.Left != nil && node.Right != nil:
+		buf.WriteByte(auditPathNode)
+		if err := writeAuditPathNode(buf, node.Left); err != nil {
+			return err
+		}
+
+		return writeAuditPathNode(buf, node.Right)
+
+	case node.Value != "":
+		value, err := iotago.DecodeHex(node.Value)
+		if err != nil {
+			return err
+		}
+		buf.WriteByte(auditPathLeaf)
+		writeBytes(buf, value)
+
+	case node.Hash != "":
+		hash, err := iotago.DecodeHex(node.Hash)
+		if err != nil {
+			return err
+		}
+		buf.WriteByte(auditPathHash)
+		writeBytes(buf, hash)
+
+	default:
+		return fmt.Errorf("%w: malformed audit path", ErrInvalidProof)
+	}
+
+	return nil
+}
+
+func readAuditPathNode(r *bytes.Reader) (*jsonAuditPathNode, error) {
+	nodeType, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch nodeType {
+	case auditPathNode:
+		left, err := readAuditPathNode(r)
+		if err != nil {
+			return nil, err
+		}
+		right, err := readAuditPathNode(r)
+		if err != nil {
+			return nil, err
+		}
+
+		return &jsonAuditPathNode{Left: left, Right: right}, nil
+
+	case auditPathLeaf:
+		value, err := readBytes(r)
+		if err != nil {
+			return nil, err
+		}
+
+		return &jsonAuditPathNode{Value: iotago.EncodeHex(value)}, nil
+
+	case auditPathHash:
+		hash, err := readBytes(r)
+		if err != nil {
+			return nil, err
+		}
+
+		return &jsonAuditPathNode{Hash: iotago.EncodeHex(hash)}, nil
+
+	default:
+		return nil, fmt.Errorf("%w: unknown audit path node type %d", ErrInvalidProof, nodeType)
+	}
+}
+
+func writeBytes(buf *bytes.Buffer, data []byte) {
+	length := make([]byte, 4)
+	binary.LittleEndian.PutUint32(length, uint32(len(data)))
+	buf.Write(length)
+	buf.Write(data)
+}
+
+func readBytes(r *bytes.Reader) ([]byte, error) {
+	length := make([]byte, 4)
+	if _, err := io.ReadFull(r, length); err != nil {
+		return nil, err
+	}
+
+	size := binary.LittleEndian.Uint32(length)
+	if int64(size) > int64(r.Len()) {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Bytes returns the binary representation of the proof.
+// The milestone and the block are encoded with the IOTA serializer, so the proof can be verified in any language.
+func (p *Proof) Bytes() ([]byte, error) {
+	if p.Milestone == nil || p.Block == nil {
+		return nil, fmt.Errorf("%w: milestone or block missing", ErrInvalidProof)
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteByte(serializationVersion)
+
+	switch p.Root {
+	case MerkleRootInclusion:
+		buf.WriteByte(merkleRootInclusionByte)
+	case MerkleRootApplied:
+		buf.WriteByte(merkleRootAppliedByte)
+	default:
+		return nil, fmt.Errorf("%w: unknown merkle root \"%s\"", ErrInvalidProof, p.Root)
+	}
+
+	milestoneBytes, err := p.Milestone.Serialize(serializer.DeSeriModeNoValidation, nil)
+	if err != nil {
+		return nil, err
+	}
+	writeBytes(buf, milestoneBytes)
+
+	blockBytes, err := p.Block.Serialize(serializer.DeSeriModeNoValidation, nil)
+	if err != nil {
+		return nil, err
+	}
+	writeBytes(buf, blockBytes)
+
+	if p.AuditPath == nil {
+		buf.WriteByte(auditPathNone)
+
+		return buf.Bytes(), nil
+	}
+
+	// the nodes of the audit path are not exported, so they are walked via their JSON representation
+	auditPathJSON, err := json.Marshal(p.AuditPath)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &jsonAuditPathNode{}
+	if err := json.Unmarshal(auditPathJSON, root); err != nil {
+		return nil, err
+	}
+
+	if err := writeAuditPathNode(buf, root); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// FromBytes parses a proof from its binary representation.
+func FromBytes(data []byte) (*Proof, error) {
+	r := bytes.NewReader(data)
+
+	version, err := r.ReadByte()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidProof, err.Error())
+	}
+	if version != serializationVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidProof, version)
+	}
+
+	rootByte, err := r.ReadByte()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidProof, err.Error())
+	}
+
+	proof := &Proof{}
+	switch rootByte {
+	case merkleRootInclusionByte:
+		proof.Root = MerkleRootInclusion
+	case merkleRootAppliedByte:
+		proof.Root = MerkleRootApplied
+	default:
+		return nil, fmt.Errorf("%w: unknown merkle root %d", ErrInvalidProof, rootByte)
+	}
+
+	milestoneBytes, err := readBytes(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to read milestone: %s", ErrInvalidProof, err.Error())
+	}
+	proof.Milestone = &iotago.Milestone{}
+	if _, err := proof.Milestone.Deserialize(milestoneBytes, serializer.DeSeriModeNoValidation, nil); err != nil {
+		return nil, fmt.Errorf("%w: unable to parse milestone: %s", ErrInvalidProof, err.Error())
+	}
+
+	blockBytes, err := readBytes(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to read block: %s", ErrInvalidProof, err.Error())
+	}
+	proof.Block = &iotago.Block{}
+	if _, err := proof.Block.Deserialize(blockBytes, serializer.DeSeriModeNoValidation, nil); err != nil {
+		return nil, fmt.Errorf("%w: unable to parse block: %s", ErrInvalidProof, err.Error())
+	}
+
+	auditPathType, err := r.ReadByte()
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to read audit path: %s", ErrInvalidProof, err.Error())
+	}
+
+	if auditPathType == auditPathNone {
+		if r.Len() > 0 {
+			return nil, fmt.Errorf("%w: %d trailing bytes", ErrInvalidProof, r.Len())
+		}
+
+		return proof, nil
+	}
+
+	if err := r.UnreadByte(); err != nil {
+		return nil, err
+	}
+
+	root, err := readAuditPathNode(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to read audit path: %s", ErrInvalidProof, err.Error())
+	}
+
+	if r.Len() > 0 {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrInvalidProof, r.Len())
+	}
+
+	auditPathJSON, err := json.Marshal(root)
+	if err != nil {
+		return nil, err
+	}
+
+	proof.AuditPath = &merklehasher.Proof{}
+	if err := json.Unmarshal(auditPathJSON, proof.AuditPath); err != nil {
+		return nil, fmt.Errorf("%w: unable to parse audit path: %s", ErrInvalidProof, err.Error())
+	}
+
+	return proof, nil
+}
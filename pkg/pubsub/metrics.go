@@ -0,0 +1,110 @@
+package pubsub
+
+import (
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ConsumerStats are the counters of all subscriptions of a consumer.
+type ConsumerStats struct {
+	// Subscriptions is the amount of active subscriptions of the consumer.
+	Subscriptions int `json:"subscriptions"`
+	// Delivered is the amount of messages that were delivered to the consumer.
+	Delivered uint64 `json:"delivered"`
+	// Dropped is the amount of messages that were dropped because the consumer was too slow.
+	Dropped uint64 `json:"dropped"`
+	// Failed is the amount of messages the consumer failed to process.
+	Failed uint64 `json:"failed"`
+	// Pending is the amount of buffered messages the consumer did not receive yet, i.e. how far it is behind.
+	Pending int `json:"pending"`
+}
+
+// ConsumerStats returns the counters of the active subscriptions, per consumer.
+func (b *Bus) ConsumerStats() map[string]ConsumerStats {
+	b.subscriptionsMutex.RLock()
+	defer b.subscriptionsMutex.RUnlock()
+
+	stats := make(map[string]ConsumerStats)
+	for _, subscription := range b.subscriptions {
+		consumerStats := stats[subscription.consumer]
+		consumerStats.Subscriptions++
+		consumerStats.Delivered += subscription.Delivered()
+		consumerStats.Dropped += subscription.Dropped()
+		consumerStats.Failed += subscription.Failures()
+		consumerStats.Pending += subscription.Pending()
+		stats[subscription.consumer] = consumerStats
+	}
+
+	return stats
+}
+
+// Metrics exposes the counters of the subscriptions of a Bus, labeled by consumer.
+// Metrics implements prometheus.Collector, so it can be registered directly.
+type Metrics struct {
+	bus *Bus
+
+	deliveredDesc *prometheus.Desc
+	droppedDesc   *prometheus.Desc
+	failedDesc    *prometheus.Desc
+	pendingDesc   *prometheus.Desc
+}
+
+// NewMetrics creates new Metrics for the given Bus. The namespace is used as prefix of the prometheus metric names.
+// The counters only cover the active subscriptions, they decrease if a subscription of a consumer is canceled.
+func NewMetrics(bus *Bus, namespace string) *Metrics {
+	return &Metrics{
+		bus: bus,
+		deliveredDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "pubsub", "messages_delivered"),
+			"The amount of messages delivered to the active subscriptions of a consumer.",
+			[]string{"consumer"},
+			nil,
+		),
+		droppedDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "pubsub", "messages_dropped"),
+			"The amount of messages dropped because a consumer was too slow.",
+			[]string{"consumer"},
+			nil,
+		),
+		failedDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "pubsub", "messages_failed"),
+			"The amount of messages a consumer failed to process.",
+			[]string{"consumer"},
+			nil,
+		),
+		pendingDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "pubsub", "messages_pending"),
+			"The amount of buffered messages a consumer did not receive yet.",
+			[]string{"consumer"},
+			nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- m.deliveredDesc
+	ch <- m.droppedDesc
+	ch <- m.failedDesc
+	ch <- m.pendingDesc
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	stats := m.bus.ConsumerStats()
+
+	consumers := make([]string, 0, len(stats))
+	for consumer := range stats {
+		consumers = append(consumers, consumer)
+	}
+	sort.Strings(consumers)
+
+	for _, consumer := range consumers {
+		consumerStats := stats[consumer]
+		ch <- prometheus.MustNewConstMetric(m.deliveredDesc, prometheus.GaugeValue, float64(consumerStats.Delivered), consumer)
+		ch <- prometheus.MustNewConstMetric(m.droppedDesc, prometheus.GaugeValue, float64(consumerStats.Dropped), consumer)
+		ch <- prometheus.MustNewConstMetric(m.failedDesc, prometheus.GaugeValue, float64(consumerStats.Failed), consumer)
+		ch <- prometheus.MustNewConstMetric(m.pendingDesc, prometheus.GaugeValue, float64(consumerStats.Pending), consumer)
+	}
+}
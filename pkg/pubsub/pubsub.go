@@ -0,0 +1,268 @@
+package pubsub
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/inx-app/pkg/backpressure"
+)
+
+const (
+	// TopicSeparator separates the segments of a topic.
+	TopicSeparator = "."
+	// WildcardSingle matches exactly one segment of a topic.
+	WildcardSingle = "*"
+	// WildcardMulti matches all remaining segments of a topic (including none).
+	// It is only allowed as the last segment of a pattern.
+	WildcardMulti = "#"
+
+	// DefaultSubscriptionBufferSize is the amount of messages that are buffered per subscription.
+	DefaultSubscriptionBufferSize = 100
+
+	// DefaultConsumer is the consumer name of subscriptions without a name.
+	DefaultConsumer = "unnamed"
+)
+
+var (
+	// ErrInvalidTopic is returned when a topic or pattern is malformed.
+	ErrInvalidTopic = errors.New("invalid topic")
+)
+
+// Message is a message that was published on a topic.
+type Message struct {
+	// Topic is the topic the message was published on.
+	Topic string
+	// Payload is the published object.
+	Payload any
+}
+
+// Bus is an in-process publish/subscribe message bus.
+// Producers publish messages on dot-separated topics, consumers subscribe to topic patterns
+// that may contain wildcards, e.g. "ledger.output.created.*" or "milestone.#".
+type Bus struct {
+	subscriptionBufferSize int
+	backpressureMetrics    *backpressure.Metrics
+
+	subscriptionsMutex sync.RWMutex
+	subscriptions      map[uint64]*Subscription
+	nextSubscriptionID uint64
+}
+
+// WithSubscriptionBufferSize sets the default amount of messages that are buffered per subscription.
+// High throughput networks need bigger buffers to avoid dropping messages for slow consumers.
+// Sizes below 1 are raised to 1, an unbuffered subscription would drop every message.
+func WithSubscriptionBufferSize(bufferSize int) options.Option[Bus] {
+	return func(b *Bus) {
+		b.subscriptionBufferSize = validBufferSize(bufferSize)
+	}
+}
+
+// WithBackpressureMetrics counts the messages that are dropped because of full subscription buffers.
+func WithBackpressureMetrics(metrics *backpressure.Metrics) options.Option[Bus] {
+	return func(b *Bus) {
+		b.backpressureMetrics = metrics
+	}
+}
+
+// New creates a new Bus.
+func New(opts ...options.Option[Bus]) *Bus {
+	return options.Apply(&Bus{
+		subscriptionBufferSize: DefaultSubscriptionBufferSize,
+		subscriptions:          make(map[uint64]*Subscription),
+	}, opts)
+}
+
+// Subscription is a subscription to a topic pattern.
+type Subscription struct {
+	// the counters are accessed atomically and must stay 64-bit aligned.
+	delivered uint64
+	dropped   uint64
+	failed    uint64
+
+	bus        *Bus
+	id         uint64
+	consumer   string
+	pattern    []string
+	bufferSize int
+	messages   chan *Message
+	closeOnce  sync.Once
+}
+
+// WithBufferSize overrides the amount of messages that are buffered for the subscription.
+// Sizes below 1 are raised to 1.
+func WithBufferSize(bufferSize int) options.Option[Subscription] {
+	return func(s *Subscription) {
+		s.bufferSize = validBufferSize(bufferSize)
+	}
+}
+
+// validBufferSize raises the buffer size to at least 1, messages are published without blocking,
+// so they are only delivered to buffered subscriptions.
+func validBufferSize(bufferSize int) int {
+	if bufferSize < 1 {
+		return 1
+	}
+
+	return bufferSize
+}
+
+// WithConsumer sets the name of the subsystem consuming the subscription, e.g. "exporter" or "websocket".
+// The metrics of subscriptions are labeled with it, so operators know which consumer is falling behind.
+func WithConsumer(consumer string) options.Option[Subscription] {
+	return func(s *Subscription) {
+		s.consumer = consumer
+	}
+}
+
+// Consumer returns the name of the subsystem consuming the subscription.
+func (s *Subscription) Consumer() string {
+	return s.consumer
+}
+
+// Messages returns the channel the matching messages are delivered to.
+// The channel is closed once the subscription is canceled.
+func (s *Subscription) Messages() <-chan *Message {
+	return s.messages
+}
+
+// Dropped returns the amount of messages that were dropped because the buffer of the subscription was full.
+func (s *Subscription) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// Delivered returns the amount of messages that were delivered to the buffer of the subscription.
+func (s *Subscription) Delivered() uint64 {
+	return atomic.LoadUint64(&s.delivered)
+}
+
+// Pending returns the amount of messages in the buffer that were not received by the consumer yet.
+func (s *Subscription) Pending() int {
+	return len(s.messages)
+}
+
+// Failed records that the consumer failed to process a message of the subscription.
+func (s *Subscription) Failed() {
+	atomic.AddUint64(&s.failed, 1)
+}
+
+// Failures returns the amount of messages the consumer failed to process.
+func (s *Subscription) Failures() uint64 {
+	return atomic.LoadUint64(&s.failed)
+}
+
+// Unsubscribe cancels the subscription and closes the message channel.
+func (s *Subscription) Unsubscribe() {
+	s.bus.subscriptionsMutex.Lock()
+	defer s.bus.subscriptionsMutex.Unlock()
+
+	s.close()
+}
+
+func (s *Subscription) close() {
+	s.closeOnce.Do(func() {
+		delete(s.bus.subscriptions, s.id)
+		close(s.messages)
+	})
+}
+
+// Subscribe subscribes to all topics matching the given pattern.
+func (b *Bus) Subscribe(pattern string, opts ...options.Option[Subscription]) (*Subscription, error) {
+	segments, err := parsePattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	b.subscriptionsMutex.Lock()
+	defer b.subscriptionsMutex.Unlock()
+
+	b.nextSubscriptionID++
+	subscription := options.Apply(&Subscription{
+		bus:        b,
+		id:         b.nextSubscriptionID,
+		consumer:   DefaultConsumer,
+		pattern:    segments,
+		bufferSize: b.subscriptionBufferSize,
+	}, opts)
+	subscription.messages = make(chan *Message, subscription.bufferSize)
+	b.subscriptions[subscription.id] = subscription
+
+	return subscription, nil
+}
+
+// Publish publishes the payload on the given topic.
+// The message is delivered to all subscriptions with a matching pattern.
+// Publishing never blocks, messages for subscriptions with a full buffer are dropped.
+func (b *Bus) Publish(topic string, payload any) {
+	segments := strings.Split(topic, TopicSeparator)
+
+	b.subscriptionsMutex.RLock()
+	defer b.subscriptionsMutex.RUnlock()
+
+	var msg *Message
+	for _, subscription := range b.subscriptions {
+		if !matches(subscription.pattern, segments) {
+			continue
+		}
+
+		if msg == nil {
+			msg = &Message{Topic: topic, Payload: payload}
+		}
+
+		select {
+		case subscription.messages <- msg:
+			atomic.AddUint64(&subscription.delivered, 1)
+		default:
+			atomic.AddUint64(&subscription.dropped, 1)
+			b.backpressureMetrics.Dropped(backpressure.SourcePubSub)
+		}
+	}
+}
+
+// Close cancels all subscriptions.
+func (b *Bus) Close() {
+	b.subscriptionsMutex.Lock()
+	defer b.subscriptionsMutex.Unlock()
+
+	for _, subscription := range b.subscriptions {
+		subscription.close()
+	}
+}
+
+// Topic joins the given segments to a topic.
+func Topic(segments ...string) string {
+	return strings.Join(segments, TopicSeparator)
+}
+
+func parsePattern(pattern string) ([]string, error) {
+	segments := strings.Split(pattern, TopicSeparator)
+	for i, segment := range segments {
+		if segment == "" {
+			return nil, fmt.Errorf("%w: empty segment in \"%s\"", ErrInvalidTopic, pattern)
+		}
+		if segment == WildcardMulti && i != len(segments)-1 {
+			return nil, fmt.Errorf("%w: \"%s\" is only allowed as the last segment in \"%s\"", ErrInvalidTopic, WildcardMulti, pattern)
+		}
+	}
+
+	return segments, nil
+}
+
+func matches(pattern []string, topic []string) bool {
+	for i, segment := range pattern {
+		if segment == WildcardMulti {
+			return true
+		}
+		if i >= len(topic) {
+			return false
+		}
+		if segment != WildcardSingle && segment != topic[i] {
+			return false
+		}
+	}
+
+	return len(pattern) == len(topic)
+}
@@ -0,0 +1,160 @@
+package sampledlog
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/core/logger"
+)
+
+// keyState is the state of the messages logged with a single key in the current window.
+type keyState struct {
+	windowStart time.Time
+	count       int
+	suppressed  int
+}
+
+// Logger is a logger for hot paths that limits the amount of messages per key.
+// The first messages of a key in every window are logged, afterwards only every n-th message is sampled.
+// The amount of suppressed messages is appended to the next message that gets logged for the key,
+// so a burst of errors doesn't flood the logs and hide the message that started it.
+type Logger struct {
+	logger *logger.Logger
+
+	window     time.Duration
+	burst      int
+	sampleRate int
+
+	keysMutex   sync.Mutex
+	keys        map[string]*keyState
+	lastCleanup time.Time
+}
+
+// WithWindow sets the duration after which the message count of a key is reset.
+func WithWindow(window time.Duration) options.Option[Logger] {
+	return func(l *Logger) {
+		l.window = window
+	}
+}
+
+// WithBurst sets the amount of messages per key that are logged in every window.
+func WithBurst(burst int) options.Option[Logger] {
+	return func(l *Logger) {
+		l.burst = burst
+	}
+}
+
+// WithSampleRate sets that every n-th message per key is logged after the burst was exceeded.
+// A sample rate of 0 suppresses all messages after the burst until the window ends.
+func WithSampleRate(sampleRate int) options.Option[Logger] {
+	return func(l *Logger) {
+		l.sampleRate = sampleRate
+	}
+}
+
+// New creates a new Logger. A nil logger is allowed and drops all messages.
+func New(log *logger.Logger, opts ...options.Option[Logger]) *Logger {
+	return options.Apply(&Logger{
+		logger:     log,
+		window:     10 * time.Second,
+		burst:      5,
+		sampleRate: 100,
+		keys:       make(map[string]*keyState),
+	}, opts)
+}
+
+// allow reports whether a message with the given key should be logged,
+// and returns the amount of messages that were suppressed since the last logged one.
+func (l *Logger) allow(key string) (bool, int) {
+	l.keysMutex.Lock()
+	defer l.keysMutex.Unlock()
+
+	now := time.Now()
+	l.cleanupWithoutLocking(now)
+
+	state, exists := l.keys[key]
+	if !exists || now.Sub(state.windowStart) >= l.window {
+		suppressed := 0
+		if exists {
+			suppressed = state.suppressed
+		}
+		l.keys[key] = &keyState{windowStart: now, count: 1}
+
+		return true, suppressed
+	}
+
+	state.count++
+	if state.count <= l.burst || (l.sampleRate > 0 && (state.count-l.burst)%l.sampleRate == 0) {
+		suppressed := state.suppressed
+		state.suppressed = 0
+
+		return true, suppressed
+	}
+
+	state.suppressed++
+
+	return false, 0
+}
+
+// cleanupWithoutLocking removes the state of keys that didn't log anything for a whole window,
+// so keys that are only used once don't accumulate.
+func (l *Logger) cleanupWithoutLocking(now time.Time) {
+	if now.Sub(l.lastCleanup) < l.window {
+		return
+	}
+	l.lastCleanup = now
+
+	for key, state := range l.keys {
+		if state.suppressed == 0 && now.Sub(state.windowStart) >= l.window {
+			delete(l.keys, key)
+		}
+	}
+}
+
+func (l *Logger) format(key string, template string, args ...interface{}) (string, bool) {
+	if l.logger == nil {
+		return "", false
+	}
+
+	allowed, suppressed := l.allow(key)
+	if !allowed {
+		return "", false
+	}
+
+	message := fmt.Sprintf(template, args...)
+	if suppressed > 0 {
+		message = fmt.Sprintf("%s (suppressed %d similar messages)", message, suppressed)
+	}
+
+	return message, true
+}
+
+// LogDebugf uses fmt.Sprintf to log a templated message, limited by the given key.
+func (l *Logger) LogDebugf(key string, template string, args ...interface{}) {
+	if message, ok := l.format(key, template, args...); ok {
+		l.logger.Debug(message)
+	}
+}
+
+// LogInfof uses fmt.Sprintf to log a templated message, limited by the given key.
+func (l *Logger) LogInfof(key string, template string, args ...interface{}) {
+	if message, ok := l.format(key, template, args...); ok {
+		l.logger.Info(message)
+	}
+}
+
+// LogWarnf uses fmt.Sprintf to log a templated message, limited by the given key.
+func (l *Logger) LogWarnf(key string, template string, args ...interface{}) {
+	if message, ok := l.format(key, template, args...); ok {
+		l.logger.Warn(message)
+	}
+}
+
+// LogErrorf uses fmt.Sprintf to log a templated message, limited by the given key.
+func (l *Logger) LogErrorf(key string, template string, args ...interface{}) {
+	if message, ok := l.format(key, template, args...); ok {
+		l.logger.Error(message)
+	}
+}
@@ -0,0 +1,195 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidCronExpression is returned when a cron expression can't be parsed.
+	ErrInvalidCronExpression = errors.New("invalid cron expression")
+)
+
+// the search for the next activation is aborted after this duration,
+// this can only happen for expressions like "0 0 30 2 *".
+const maxCronSearchDuration = 5 * 366 * 24 * time.Hour
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type cronField struct {
+	name string
+	min  int
+	max  int
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day of week", min: 0, max: 6},
+}
+
+// CronSchedule is a parsed cron expression.
+type CronSchedule struct {
+	expression string
+
+	minutes     uint64
+	hours       uint64
+	daysOfMonth uint64
+	months      uint64
+	daysOfWeek  uint64
+
+	// if both day fields are restricted, a day matches if either of them matches.
+	daysOfMonthRestricted bool
+	daysOfWeekRestricted  bool
+}
+
+// ParseCron parses a standard 5 field cron expression ("minute hour day-of-month month day-of-week").
+// Every field supports "*", single values, ranges ("1-5"), lists ("1,3,5") and steps ("*/15", "0-30/5").
+// The descriptors "@yearly", "@monthly", "@weekly", "@daily" and "@hourly" are supported as well.
+func ParseCron(expression string) (*CronSchedule, error) {
+	normalized := strings.TrimSpace(expression)
+	if descriptor, exists := cronDescriptors[normalized]; exists {
+		normalized = descriptor
+	}
+
+	fields := strings.Fields(normalized)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("%w: \"%s\" has %d fields instead of %d", ErrInvalidCronExpression, expression, len(fields), len(cronFields))
+	}
+
+	bits := make([]uint64, len(cronFields))
+	for i, field := range fields {
+		fieldBits, err := parseCronField(field, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: \"%s\": %s", ErrInvalidCronExpression, expression, err.Error())
+		}
+		bits[i] = fieldBits
+	}
+
+	return &CronSchedule{
+		expression:            expression,
+		minutes:               bits[0],
+		hours:                 bits[1],
+		daysOfMonth:           bits[2],
+		months:                bits[3],
+		daysOfWeek:            bits[4],
+		daysOfMonthRestricted: fields[2] != "*",
+		daysOfWeekRestricted:  fields[4] != "*",
+	}, nil
+}
+
+func parseCronField(field string, def cronField) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart := part
+		step := 1
+
+		if i := strings.Index(part, "/"); i >= 0 {
+			parsedStep, err := strconv.Atoi(part[i+1:])
+			if err != nil || parsedStep <= 0 {
+				return 0, fmt.Errorf("invalid step in %s field: \"%s\"", def.name, part)
+			}
+			step = parsedStep
+			rangePart = part[:i]
+		}
+
+		start, end := def.min, def.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			parsedStart, errStart := strconv.Atoi(bounds[0])
+			parsedEnd, errEnd := strconv.Atoi(bounds[1])
+			if errStart != nil || errEnd != nil {
+				return 0, fmt.Errorf("invalid range in %s field: \"%s\"", def.name, part)
+			}
+			start, end = parsedStart, parsedEnd
+		default:
+			value, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value in %s field: \"%s\"", def.name, part)
+			}
+			start = value
+			if !strings.Contains(part, "/") {
+				end = value
+			}
+		}
+
+		if start < def.min || end > def.max || start > end {
+			return 0, fmt.Errorf("%s field out of range [%d-%d]: \"%s\"", def.name, def.min, def.max, part)
+		}
+
+		for value := start; value <= end; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+
+	return bits, nil
+}
+
+// String returns the original cron expression.
+func (s *CronSchedule) String() string {
+	return s.expression
+}
+
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	domMatches := s.daysOfMonth&(1<<uint(t.Day())) != 0
+	dowMatches := s.daysOfWeek&(1<<uint(t.Weekday())) != 0
+
+	if s.daysOfMonthRestricted && s.daysOfWeekRestricted {
+		return domMatches || dowMatches
+	}
+
+	return domMatches && dowMatches
+}
+
+// Next returns the next activation time strictly after the given time.
+// It returns the zero time if the schedule never activates.
+func (s *CronSchedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.Add(maxCronSearchDuration)
+
+	for t.Before(limit) {
+		if s.months&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+
+			continue
+		}
+
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+
+			continue
+		}
+
+		if s.hours&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+
+			continue
+		}
+
+		if s.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,250 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/hive.go/core/logger"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+)
+
+var (
+	// ErrJobAlreadyExists is returned when a job with the same name has already been added.
+	ErrJobAlreadyExists = errors.New("job already exists")
+	// ErrInvalidMilestoneInterval is returned when a milestone job is added with an interval of 0.
+	ErrInvalidMilestoneInterval = errors.New("milestone interval must be greater than 0")
+	// ErrMilestoneJobsWithoutNodeBridge is returned when a milestone job is added to a scheduler without a NodeBridge.
+	ErrMilestoneJobsWithoutNodeBridge = errors.New("milestone jobs need a NodeBridge")
+)
+
+// CronFunc is executed at the activation times of a cron job.
+type CronFunc func(ctx context.Context)
+
+// MilestoneFunc is executed for the confirmed milestones of a milestone job.
+type MilestoneFunc func(ctx context.Context, msIndex uint32)
+
+type job struct {
+	name string
+
+	running   sync.Mutex
+	schedule  *CronSchedule
+	cronFunc  CronFunc
+	interval  uint32
+	milestone MilestoneFunc
+	// the confirmed milestone index the milestone job saw last.
+	lastMilestoneIndex uint32
+}
+
+// milestoneDue tells whether the milestone job has to be executed for the confirmed milestone,
+// i.e. whether a multiple of its interval is in (lastMilestoneIndex, msIndex]. It remembers the milestone as seen.
+func (j *job) milestoneDue(msIndex uint32) bool {
+	lastMilestoneIndex := j.lastMilestoneIndex
+	if lastMilestoneIndex == 0 {
+		// only the current milestone is considered for jobs that saw no milestone yet
+		lastMilestoneIndex = msIndex - 1
+	}
+	j.lastMilestoneIndex = msIndex
+
+	return msIndex > lastMilestoneIndex && msIndex-msIndex%j.interval > lastMilestoneIndex
+}
+
+// Scheduler executes jobs bound to the wall clock (cron expressions) or to confirmed milestones.
+// A job is never executed concurrently with itself, an activation is skipped if the previous run has not finished yet.
+type Scheduler struct {
+	// the logger used to log events.
+	*logger.WrappedLogger
+
+	nodeBridge *nodebridge.NodeBridge
+
+	jobsMutex     sync.RWMutex
+	cronJobs      map[string]*job
+	milestoneJobs map[string]*job
+
+	// is used to wake up the cron loop if a new job was added.
+	cronJobsChanged chan struct{}
+}
+
+// NewScheduler creates a new Scheduler.
+// The nodeBridge is only needed for milestone jobs and may be nil otherwise.
+func NewScheduler(nodeBridge *nodebridge.NodeBridge, log *logger.Logger) *Scheduler {
+	return &Scheduler{
+		WrappedLogger:   logger.NewWrappedLogger(log),
+		nodeBridge:      nodeBridge,
+		cronJobs:        make(map[string]*job),
+		milestoneJobs:   make(map[string]*job),
+		cronJobsChanged: make(chan struct{}, 1),
+	}
+}
+
+func (s *Scheduler) hasJob(name string) bool {
+	_, cronJobExists := s.cronJobs[name]
+	_, milestoneJobExists := s.milestoneJobs[name]
+
+	return cronJobExists || milestoneJobExists
+}
+
+// AddCronJob adds a job that is executed at the activation times of the given cron expression.
+func (s *Scheduler) AddCronJob(name string, expression string, f CronFunc) error {
+	schedule, err := ParseCron(expression)
+	if err != nil {
+		return err
+	}
+
+	s.jobsMutex.Lock()
+	defer s.jobsMutex.Unlock()
+
+	if s.hasJob(name) {
+		return fmt.Errorf("%w: %s", ErrJobAlreadyExists, name)
+	}
+	s.cronJobs[name] = &job{name: name, schedule: schedule, cronFunc: f}
+
+	select {
+	case s.cronJobsChanged <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// AddMilestoneJob adds a job that is executed for every confirmed milestone whose index is a multiple of the given interval.
+// The confirmed milestone index may skip milestones, e.g. during catch-up. The job is then executed for the first
+// confirmed milestone after a skipped multiple.
+func (s *Scheduler) AddMilestoneJob(name string, interval uint32, f MilestoneFunc) error {
+	if s.nodeBridge == nil {
+		return ErrMilestoneJobsWithoutNodeBridge
+	}
+	if interval == 0 {
+		return ErrInvalidMilestoneInterval
+	}
+
+	s.jobsMutex.Lock()
+	defer s.jobsMutex.Unlock()
+
+	if s.hasJob(name) {
+		return fmt.Errorf("%w: %s", ErrJobAlreadyExists, name)
+	}
+	s.milestoneJobs[name] = &job{name: name, interval: interval, milestone: f}
+
+	return nil
+}
+
+// RemoveJob removes the job with the given name.
+// A currently running execution of the job is not interrupted.
+func (s *Scheduler) RemoveJob(name string) {
+	s.jobsMutex.Lock()
+	defer s.jobsMutex.Unlock()
+
+	delete(s.cronJobs, name)
+	delete(s.milestoneJobs, name)
+}
+
+// Run executes the jobs until the given context is done.
+func (s *Scheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	// wait for running executions after the milestone event was detached
+	defer wg.Wait()
+
+	execute := func(j *job, f func()) {
+		if !j.running.TryLock() {
+			s.LogWarnf("skipping execution of job \"%s\", previous execution is still running", j.name)
+
+			return
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer j.running.Unlock()
+			f()
+		}()
+	}
+
+	if s.nodeBridge != nil {
+		onMilestoneConfirmed := events.NewClosure(func(ms *nodebridge.Milestone) {
+			msIndex := ms.Milestone.Index
+
+			// the jobs are locked for writing, the last seen milestone indexes are updated
+			s.jobsMutex.Lock()
+			defer s.jobsMutex.Unlock()
+
+			for _, j := range s.milestoneJobs {
+				if !j.milestoneDue(msIndex) {
+					continue
+				}
+
+				milestoneFunc := j.milestone
+				execute(j, func() { milestoneFunc(ctx, msIndex) })
+			}
+		})
+
+		s.nodeBridge.Events.ConfirmedMilestoneChanged.Hook(onMilestoneConfirmed)
+		defer s.nodeBridge.Events.ConfirmedMilestoneChanged.Detach(onMilestoneConfirmed)
+	}
+
+	lastCheck := time.Now()
+	for {
+		next := s.nextCronActivation(lastCheck)
+
+		var timer *time.Timer
+		var timerChan <-chan time.Time
+		if !next.IsZero() {
+			timer = time.NewTimer(time.Until(next))
+			timerChan = timer.C
+		}
+		stopTimer := func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			stopTimer()
+
+			return
+
+		case <-s.cronJobsChanged:
+			stopTimer()
+
+			continue
+
+		case now := <-timerChan:
+			s.jobsMutex.RLock()
+			for _, j := range s.cronJobs {
+				// execute all jobs that had an activation since the last check
+				activation := j.schedule.Next(lastCheck)
+				if activation.IsZero() || activation.After(now) {
+					continue
+				}
+
+				cronFunc := j.cronFunc
+				execute(j, func() { cronFunc(ctx) })
+			}
+			s.jobsMutex.RUnlock()
+			lastCheck = now
+		}
+	}
+}
+
+// nextCronActivation returns the earliest activation of all cron jobs after the given time.
+func (s *Scheduler) nextCronActivation(after time.Time) time.Time {
+	s.jobsMutex.RLock()
+	defer s.jobsMutex.RUnlock()
+
+	var next time.Time
+	for _, j := range s.cronJobs {
+		activation := j.schedule.Next(after)
+		if activation.IsZero() {
+			continue
+		}
+		if next.IsZero() || activation.Before(next) {
+			next = activation
+		}
+	}
+
+	return next
+}
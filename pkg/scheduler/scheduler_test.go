@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"testing"
+)
+
+func TestMilestoneJobDue(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval uint32
+		indexes  []uint32
+		want     []uint32
+	}{
+		{name: "every milestone", interval: 1, indexes: []uint32{5, 6, 7}, want: []uint32{5, 6, 7}},
+		{name: "multiples", interval: 5, indexes: []uint32{9, 10, 11, 15}, want: []uint32{10, 15}},
+		{name: "first milestone is not a multiple", interval: 5, indexes: []uint32{12, 13}, want: nil},
+		// the confirmed index skips milestones during catch-up
+		{name: "skipped multiple", interval: 5, indexes: []uint32{9, 12, 18, 19, 21}, want: []uint32{12, 18, 21}},
+		{name: "several skipped multiples", interval: 5, indexes: []uint32{9, 31, 32}, want: []uint32{31}},
+		{name: "same milestone again", interval: 5, indexes: []uint32{10, 10, 11}, want: []uint32{10}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			j := &job{interval: test.interval}
+
+			var due []uint32
+			for _, msIndex := range test.indexes {
+				if j.milestoneDue(msIndex) {
+					due = append(due, msIndex)
+				}
+			}
+
+			if len(due) != len(test.want) {
+				t.Fatalf("expected executions for %v, got %v", test.want, due)
+			}
+			for i := range due {
+				if due[i] != test.want[i] {
+					t.Fatalf("expected executions for %v, got %v", test.want, due)
+				}
+			}
+		})
+	}
+}
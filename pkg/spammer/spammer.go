@@ -0,0 +1,237 @@
+// Package spammer issues tagged data blocks at a configurable rate, so operators can generate load on the network
+// from within their app, e.g. to test their setup.
+package spammer
+
+import (
+	"context"
+	"crypto/rand"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/core/logger"
+	"github.com/iotaledger/inx-app/pkg/issuer"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrTagTooLong is returned if the tag exceeds the max length of tagged data payloads.
+	ErrTagTooLong = errors.New("tag too long")
+	// ErrInvalidRate is returned if the rate is not positive.
+	ErrInvalidRate = errors.New("rate must be greater than zero")
+)
+
+// Stats are the counters of the spammer.
+type Stats struct {
+	// Running is whether the spammer is issuing blocks.
+	Running bool `json:"running"`
+	// BlocksPerSecond is the configured rate.
+	BlocksPerSecond float64 `json:"blocksPerSecond"`
+	// Issued is the total amount of issued blocks.
+	Issued uint64 `json:"issued"`
+	// Failed is the total amount of blocks that could not be issued.
+	Failed uint64 `json:"failed"`
+}
+
+// Spammer issues tagged data blocks via an Issuer.
+// Spammer implements prometheus.Collector, so it can be registered directly.
+type Spammer struct {
+	// the counters are accessed atomically and must stay 64-bit aligned.
+	issued uint64
+	failed uint64
+
+	*logger.WrappedLogger
+
+	issuer  *issuer.Issuer
+	limiter *rate.Limiter
+
+	tag         []byte
+	payloadSize int
+	workers     int
+
+	runningMutex sync.Mutex
+	running      bool
+
+	issuedDesc  *prometheus.Desc
+	failedDesc  *prometheus.Desc
+	runningDesc *prometheus.Desc
+	rateDesc    *prometheus.Desc
+}
+
+// WithTag sets the tag of the issued tagged data payloads.
+func WithTag(tag []byte) options.Option[Spammer] {
+	return func(s *Spammer) {
+		s.tag = tag
+	}
+}
+
+// WithPayloadSize sets the size of the random data of the issued tagged data payloads in bytes.
+func WithPayloadSize(payloadSize int) options.Option[Spammer] {
+	return func(s *Spammer) {
+		s.payloadSize = payloadSize
+	}
+}
+
+// WithWorkers sets the amount of blocks that are issued in parallel, e.g. to keep the rate while doing proof of work.
+func WithWorkers(workers int) options.Option[Spammer] {
+	return func(s *Spammer) {
+		s.workers = workers
+	}
+}
+
+// NewSpammer creates a new Spammer that issues the given amount of blocks per second.
+// The namespace is used as prefix of the prometheus metric names.
+func NewSpammer(log *logger.Logger, blockIssuer *issuer.Issuer, namespace string, blocksPerSecond float64, opts ...options.Option[Spammer]) (*Spammer, error) {
+	if blocksPerSecond <= 0 {
+		return nil, ErrInvalidRate
+	}
+
+	s := options.Apply(&Spammer{
+		WrappedLogger: logger.NewWrappedLogger(log),
+		issuer:        blockIssuer,
+		limiter:       rate.NewLimiter(rate.Limit(blocksPerSecond), 1),
+		tag:           []byte("inx-app spammer"),
+		workers:       1,
+		issuedDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "spammer", "blocks_issued_total"),
+			"The total amount of blocks issued by the spammer.",
+			nil,
+			nil,
+		),
+		failedDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "spammer", "blocks_failed_total"),
+			"The total amount of blocks the spammer failed to issue.",
+			nil,
+			nil,
+		),
+		runningDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "spammer", "running"),
+			"Whether the spammer is issuing blocks.",
+			nil,
+			nil,
+		),
+		rateDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "spammer", "blocks_per_second"),
+			"The configured rate of the spammer.",
+			nil,
+			nil,
+		),
+	}, opts)
+
+	if len(s.tag) > iotago.TaggedPayloadTagMaxLength {
+		return nil, errors.WithMessagef(ErrTagTooLong, "max. %d bytes but is %d", iotago.TaggedPayloadTagMaxLength, len(s.tag))
+	}
+	if s.workers < 1 {
+		s.workers = 1
+	}
+
+	return s, nil
+}
+
+// SetRate changes the amount of blocks issued per second.
+func (s *Spammer) SetRate(blocksPerSecond float64) error {
+	if blocksPerSecond <= 0 {
+		return ErrInvalidRate
+	}
+	s.limiter.SetLimit(rate.Limit(blocksPerSecond))
+
+	return nil
+}
+
+func (s *Spammer) setRunning(running bool) {
+	s.runningMutex.Lock()
+	defer s.runningMutex.Unlock()
+
+	s.running = running
+}
+
+func (s *Spammer) payload() (*iotago.TaggedData, error) {
+	data := make([]byte, s.payloadSize)
+	if _, err := rand.Read(data); err != nil {
+		return nil, err
+	}
+
+	return &iotago.TaggedData{Tag: s.tag, Data: data}, nil
+}
+
+func (s *Spammer) issue(ctx context.Context) {
+	payload, err := s.payload()
+	if err == nil {
+		_, err = s.issuer.IssuePayload(ctx, payload)
+	}
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		atomic.AddUint64(&s.failed, 1)
+		s.LogWarnf("issuing spam block failed: %s", err.Error())
+
+		return
+	}
+
+	atomic.AddUint64(&s.issued, 1)
+}
+
+// Run issues blocks until the given context is done.
+func (s *Spammer) Run(ctx context.Context) {
+	s.setRunning(true)
+	defer s.setRunning(false)
+
+	var wg sync.WaitGroup
+	for i := 0; i < s.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				if err := s.limiter.Wait(ctx); err != nil {
+					return
+				}
+				s.issue(ctx)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Stats returns the counters of the spammer.
+func (s *Spammer) Stats() Stats {
+	s.runningMutex.Lock()
+	running := s.running
+	s.runningMutex.Unlock()
+
+	return Stats{
+		Running:         running,
+		BlocksPerSecond: float64(s.limiter.Limit()),
+		Issued:          atomic.LoadUint64(&s.issued),
+		Failed:          atomic.LoadUint64(&s.failed),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (s *Spammer) Describe(ch chan<- *prometheus.Desc) {
+	ch <- s.issuedDesc
+	ch <- s.failedDesc
+	ch <- s.runningDesc
+	ch <- s.rateDesc
+}
+
+// Collect implements prometheus.Collector.
+func (s *Spammer) Collect(ch chan<- prometheus.Metric) {
+	stats := s.Stats()
+
+	running := 0.0
+	if stats.Running {
+		running = 1
+	}
+
+	ch <- prometheus.MustNewConstMetric(s.issuedDesc, prometheus.CounterValue, float64(stats.Issued))
+	ch <- prometheus.MustNewConstMetric(s.failedDesc, prometheus.CounterValue, float64(stats.Failed))
+	ch <- prometheus.MustNewConstMetric(s.runningDesc, prometheus.GaugeValue, running)
+	ch <- prometheus.MustNewConstMetric(s.rateDesc, prometheus.GaugeValue, stats.BlocksPerSecond)
+}
@@ -0,0 +1,234 @@
+package syncstate
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+)
+
+// Phase is the sync phase of the app.
+type Phase int
+
+const (
+	// PhaseInitializing is the phase until the app reported its first progress.
+	PhaseInitializing Phase = iota
+	// PhaseCatchingUp is the phase while the app processes historical milestones.
+	PhaseCatchingUp
+	// PhaseSynced is the phase while the app follows the confirmed milestones of a healthy node.
+	PhaseSynced
+	// PhaseDegraded is the phase if the app was synced before,
+	// but the node is unhealthy or unsynced, or the app fell behind.
+	PhaseDegraded
+)
+
+// String returns the name of the phase.
+func (p Phase) String() string {
+	switch p {
+	case PhaseInitializing:
+		return "initializing"
+	case PhaseCatchingUp:
+		return "catchingUp"
+	case PhaseSynced:
+		return "synced"
+	case PhaseDegraded:
+		return "degraded"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler, so the phase is rendered by name.
+func (p Phase) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// Status is a snapshot of the sync state that can be rendered by health endpoints and dashboards.
+type Status struct {
+	// Phase is the current phase.
+	Phase Phase `json:"phase"`
+	// Since is the time the current phase was entered.
+	Since time.Time `json:"since"`
+	// NodeHealthy tells whether the node reports to be healthy.
+	NodeHealthy bool `json:"nodeHealthy"`
+	// NodeSynced tells whether the node reports to be synced.
+	NodeSynced bool `json:"nodeSynced"`
+	// ProcessedIndex is the last milestone index that was processed by the app.
+	ProcessedIndex uint32 `json:"processedIndex"`
+	// ConfirmedIndex is the confirmed milestone index of the node.
+	ConfirmedIndex uint32 `json:"confirmedIndex"`
+	// Lag is the amount of confirmed milestones the app did not process yet.
+	Lag uint32 `json:"lag"`
+}
+
+// Events are the events issued by the Machine.
+type Events struct {
+	// PhaseChanged is triggered with the previous and the new *Status if the phase changed.
+	PhaseChanged *events.Event
+}
+
+// PhaseChangedCaller is the caller of the PhaseChanged event.
+func PhaseChangedCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(previous *Status, current *Status))(params[0].(*Status), params[1].(*Status))
+}
+
+// Machine tracks the sync phase of the app (Initializing → CatchingUp → Synced → Degraded).
+// The transitions are driven by the node status of the NodeBridge and the progress reported by the app.
+type Machine struct {
+	nodeBridge *nodebridge.NodeBridge
+
+	maxSyncedLag   uint32
+	updateInterval time.Duration
+
+	statusMutex    sync.RWMutex
+	initialized    bool
+	wasSynced      bool
+	processedIndex uint32
+	phase          Phase
+	since          time.Time
+
+	Events *Events
+}
+
+// WithMaxSyncedLag sets the maximum amount of unprocessed confirmed milestones for the app to be considered synced.
+func WithMaxSyncedLag(maxSyncedLag uint32) options.Option[Machine] {
+	return func(m *Machine) {
+		m.maxSyncedLag = maxSyncedLag
+	}
+}
+
+// WithUpdateInterval sets the interval in which the node status is checked for health changes.
+func WithUpdateInterval(updateInterval time.Duration) options.Option[Machine] {
+	return func(m *Machine) {
+		m.updateInterval = updateInterval
+	}
+}
+
+// NewMachine creates a new Machine.
+func NewMachine(nodeBridge *nodebridge.NodeBridge, opts ...options.Option[Machine]) *Machine {
+	return options.Apply(&Machine{
+		nodeBridge:     nodeBridge,
+		maxSyncedLag:   2,
+		updateInterval: time.Second,
+		phase:          PhaseInitializing,
+		since:          time.Now(),
+		Events: &Events{
+			PhaseChanged: events.NewEvent(PhaseChangedCaller),
+		},
+	}, opts)
+}
+
+// UpdateProgress reports the last milestone index that was processed by the app, e.g. after a checkpoint was stored.
+func (m *Machine) UpdateProgress(processedIndex uint32) {
+	m.statusMutex.Lock()
+	m.initialized = true
+	m.processedIndex = processedIndex
+	m.statusMutex.Unlock()
+
+	m.evaluate()
+}
+
+// Phase returns the current phase.
+func (m *Machine) Phase() Phase {
+	m.statusMutex.RLock()
+	defer m.statusMutex.RUnlock()
+
+	return m.phase
+}
+
+// Status returns a snapshot of the current sync state.
+func (m *Machine) Status() *Status {
+	m.statusMutex.RLock()
+	defer m.statusMutex.RUnlock()
+
+	return m.statusWithoutLocking()
+}
+
+func (m *Machine) statusWithoutLocking() *Status {
+	confirmedIndex := m.nodeBridge.ConfirmedMilestoneIndex()
+
+	var lag uint32
+	if confirmedIndex > m.processedIndex {
+		lag = confirmedIndex - m.processedIndex
+	}
+
+	return &Status{
+		Phase:          m.phase,
+		Since:          m.since,
+		NodeHealthy:    m.nodeBridge.IsNodeHealthy(),
+		NodeSynced:     m.nodeBridge.IsNodeSynced(),
+		ProcessedIndex: m.processedIndex,
+		ConfirmedIndex: confirmedIndex,
+		Lag:            lag,
+	}
+}
+
+// nextPhase determines the phase for the given status.
+func (m *Machine) nextPhase(status *Status) Phase {
+	if !m.initialized {
+		return PhaseInitializing
+	}
+
+	if status.NodeHealthy && status.NodeSynced && status.Lag <= m.maxSyncedLag {
+		return PhaseSynced
+	}
+
+	if m.wasSynced {
+		return PhaseDegraded
+	}
+
+	return PhaseCatchingUp
+}
+
+// evaluate checks the current state and triggers the PhaseChanged event if the phase changed.
+func (m *Machine) evaluate() {
+	previous, current := func() (*Status, *Status) {
+		m.statusMutex.Lock()
+		defer m.statusMutex.Unlock()
+
+		previous := m.statusWithoutLocking()
+
+		phase := m.nextPhase(previous)
+		if phase == m.phase {
+			return nil, nil
+		}
+
+		m.phase = phase
+		m.since = time.Now()
+		if phase == PhaseSynced {
+			m.wasSynced = true
+		}
+
+		return previous, m.statusWithoutLocking()
+	}()
+
+	if current != nil {
+		m.Events.PhaseChanged.Trigger(previous, current)
+	}
+}
+
+// Run evaluates the sync state on every confirmed milestone and in the configured interval until the given context is done.
+func (m *Machine) Run(ctx context.Context) {
+	onMilestoneConfirmed := events.NewClosure(func(_ *nodebridge.Milestone) {
+		m.evaluate()
+	})
+
+	m.nodeBridge.Events.ConfirmedMilestoneChanged.Hook(onMilestoneConfirmed)
+	defer m.nodeBridge.Events.ConfirmedMilestoneChanged.Detach(onMilestoneConfirmed)
+
+	ticker := time.NewTicker(m.updateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.evaluate()
+		}
+	}
+}
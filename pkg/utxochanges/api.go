@@ -0,0 +1,119 @@
+package utxochanges
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/inx-app/pkg/httpserver"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+)
+
+const (
+	// ParameterAddress is used to identify an address by its bech32 representation.
+	ParameterAddress = "address"
+
+	// QueryParameterStartIndex is used to specify the milestone index to start from.
+	// It defaults to the oldest milestone index in the window.
+	QueryParameterStartIndex = "startIndex"
+
+	// QueryParameterPageSize is used to specify the maximum amount of milestones with changes in the response.
+	QueryParameterPageSize = "pageSize"
+
+	// RouteAddressUTXOChanges is the route to get the UTXO changes of an address.
+	// GET returns the outputs created and consumed for the address per milestone, oldest first.
+	RouteAddressUTXOChanges = "/addresses/:" + ParameterAddress + "/utxo-changes"
+)
+
+const (
+	// DefaultPageSize is the page size used if none is specified.
+	DefaultPageSize = 100
+	// MaxPageSize is the maximum allowed page size.
+	MaxPageSize = 1000
+)
+
+// MilestoneChangesResponse defines the UTXO changes of an address in a single milestone.
+type MilestoneChangesResponse struct {
+	// Index is the index of the milestone.
+	Index uint32 `json:"index"`
+	// CreatedOutputs are the hex encoded IDs of the outputs created for the address.
+	CreatedOutputs []string `json:"createdOutputs"`
+	// ConsumedOutputs are the hex encoded IDs of the outputs of the address that were consumed.
+	ConsumedOutputs []string `json:"consumedOutputs"`
+}
+
+// AddressUTXOChangesResponse defines the response of a GET RouteAddressUTXOChanges REST API call.
+type AddressUTXOChangesResponse struct {
+	// Address is the bech32 encoded address.
+	Address string `json:"address"`
+	// Changes are the changes per milestone, oldest first.
+	Changes []*MilestoneChangesResponse `json:"changes"`
+	// NextIndex is the startIndex of the next page, it is omitted on the last page.
+	NextIndex uint32 `json:"nextIndex,omitempty"`
+}
+
+// RegisterRoutes registers the UTXO changes routes on the given group.
+func RegisterRoutes(routeGroup *echo.Group, nodeBridge *nodebridge.NodeBridge, window *Window) {
+	routeGroup.GET(RouteAddressUTXOChanges, AddressUTXOChangesHandler(nodeBridge, window))
+}
+
+// AddressUTXOChangesHandler returns a handler that serves the UTXO changes of an address from the window.
+func AddressUTXOChangesHandler(nodeBridge *nodebridge.NodeBridge, window *Window) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if mimeType, _ := httpserver.GetAcceptHeaderContentType(c, httpserver.MIMEApplicationVendorIOTASerializerV1); mimeType != "" {
+			// the changes have no binary representation, everything else is served as JSON
+			return httpserver.ErrNotAcceptable
+		}
+
+		hrp := nodeBridge.ProtocolParameters().Bech32HRP
+		address, err := httpserver.ParseBech32AddressParam(c, hrp, ParameterAddress)
+		if err != nil {
+			return err
+		}
+
+		oldestIndex, _, ok := window.Range()
+		if !ok {
+			return errors.WithMessage(echo.ErrServiceUnavailable, "no ledger updates received yet")
+		}
+
+		startIndex := oldestIndex
+		if c.QueryParam(QueryParameterStartIndex) != "" {
+			if startIndex, err = httpserver.ParseUint32QueryParam(c, QueryParameterStartIndex); err != nil {
+				return err
+			}
+		}
+
+		pageSize, err := httpserver.ParsePageSizeQueryParam(c, DefaultPageSize, MaxPageSize)
+		if err != nil {
+			return err
+		}
+
+		changes, nextIndex, err := window.ChangesByAddress(address, startIndex, pageSize)
+		if err != nil {
+			if errors.Is(err, ErrIndexOutsideWindow) {
+				return httpserver.WithErrorDetails(
+					errors.WithMessage(httpserver.ErrInvalidParameter, err.Error()),
+					httpserver.HTTPErrorDetail{Field: QueryParameterStartIndex, Reason: httpserver.ErrorReasonOutOfRange, Value: c.QueryParam(QueryParameterStartIndex)},
+				)
+			}
+
+			return err
+		}
+
+		response := &AddressUTXOChangesResponse{
+			Address:   address.Bech32(hrp),
+			Changes:   make([]*MilestoneChangesResponse, 0, len(changes)),
+			NextIndex: nextIndex,
+		}
+		for _, change := range changes {
+			response.Changes = append(response.Changes, &MilestoneChangesResponse{
+				Index:           change.MilestoneIndex,
+				CreatedOutputs:  change.CreatedOutputs.ToHex(),
+				ConsumedOutputs: change.ConsumedOutputs.ToHex(),
+			})
+		}
+
+		return httpserver.JSONResponse(c, http.StatusOK, response)
+	}
+}
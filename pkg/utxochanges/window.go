@@ -0,0 +1,167 @@
+package utxochanges
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/iotaledger/hive.go/serializer/v2"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+	inx "github.com/iotaledger/inx/go"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrIndexOutsideWindow is returned if changes were requested for a milestone index that is not kept in the window.
+	ErrIndexOutsideWindow = errors.New("milestone index outside of the window")
+	// ErrInvalidMaxResults is returned if less than one result was requested, the returned index would never advance.
+	ErrInvalidMaxResults = errors.New("max results must be at least 1")
+)
+
+// AddressChanges are the outputs created and consumed for an address in a single milestone.
+type AddressChanges struct {
+	// MilestoneIndex is the index of the milestone that confirmed the changes.
+	MilestoneIndex iotago.MilestoneIndex
+	// CreatedOutputs are the IDs of the outputs created for the address.
+	CreatedOutputs iotago.OutputIDs
+	// ConsumedOutputs are the IDs of the outputs of the address that were consumed.
+	ConsumedOutputs iotago.OutputIDs
+}
+
+type milestoneChanges struct {
+	index     iotago.MilestoneIndex
+	addresses map[string]*AddressChanges
+}
+
+// Window keeps the UTXO changes of the last milestones per address in memory.
+// It is fed by the ledger updates of the node and serves the changes of an address since a milestone index.
+type Window struct {
+	size uint32
+
+	milestonesMutex sync.RWMutex
+	// ordered by milestone index, gaps are not allowed
+	milestones []*milestoneChanges
+}
+
+// NewWindow creates a new Window that keeps the changes of the last size milestones.
+func NewWindow(size uint32) *Window {
+	return &Window{
+		size:       size,
+		milestones: make([]*milestoneChanges, 0, size),
+	}
+}
+
+// Range returns the oldest and the newest milestone index kept in the window.
+// It returns false if the window is empty.
+func (w *Window) Range() (iotago.MilestoneIndex, iotago.MilestoneIndex, bool) {
+	w.milestonesMutex.RLock()
+	defer w.milestonesMutex.RUnlock()
+
+	if len(w.milestones) == 0 {
+		return 0, 0, false
+	}
+
+	return w.milestones[0].index, w.milestones[len(w.milestones)-1].index, true
+}
+
+func (c *milestoneChanges) address(address iotago.Address) *AddressChanges {
+	changes, exists := c.addresses[address.Key()]
+	if !exists {
+		changes = &AddressChanges{MilestoneIndex: c.index}
+		c.addresses[address.Key()] = changes
+	}
+
+	return changes
+}
+
+func outputAddresses(ledgerOutput *inx.LedgerOutput) ([]iotago.Address, error) {
+	output, err := ledgerOutput.UnwrapOutput(serializer.DeSeriModeNoValidation, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return nodebridge.OutputAddresses(output), nil
+}
+
+// ConsumeLedgerUpdate adds the changes of the ledger update to the window and drops the milestones that left it.
+// It can be passed to NodeBridge.ListenToLedgerUpdates or used as part of another consumer.
+func (w *Window) ConsumeLedgerUpdate(update *nodebridge.LedgerUpdate) error {
+	changes := &milestoneChanges{
+		index:     update.MilestoneIndex,
+		addresses: make(map[string]*AddressChanges),
+	}
+
+	for _, spent := range update.Consumed {
+		addresses, err := outputAddresses(spent.GetOutput())
+		if err != nil {
+			return fmt.Errorf("processing consumed output failed: %w", err)
+		}
+		for _, address := range addresses {
+			addressChanges := changes.address(address)
+			addressChanges.ConsumedOutputs = append(addressChanges.ConsumedOutputs, spent.GetOutput().UnwrapOutputID())
+		}
+	}
+
+	for _, created := range update.Created {
+		addresses, err := outputAddresses(created)
+		if err != nil {
+			return fmt.Errorf("processing created output failed: %w", err)
+		}
+		for _, address := range addresses {
+			addressChanges := changes.address(address)
+			addressChanges.CreatedOutputs = append(addressChanges.CreatedOutputs, created.UnwrapOutputID())
+		}
+	}
+
+	w.milestonesMutex.Lock()
+	defer w.milestonesMutex.Unlock()
+
+	if len(w.milestones) > 0 && w.milestones[len(w.milestones)-1].index+1 != update.MilestoneIndex {
+		// the window only serves gapless ranges, start over
+		w.milestones = w.milestones[:0]
+	}
+
+	w.milestones = append(w.milestones, changes)
+	if uint32(len(w.milestones)) > w.size {
+		w.milestones = w.milestones[uint32(len(w.milestones))-w.size:]
+	}
+
+	return nil
+}
+
+// ChangesByAddress returns the changes of the address in the milestones from startIndex on, oldest first.
+// At most maxResults milestones with changes are returned, the second return value is the index to continue from,
+// or 0 if there are no more changes in the window.
+func (w *Window) ChangesByAddress(address iotago.Address, startIndex iotago.MilestoneIndex, maxResults int) ([]*AddressChanges, iotago.MilestoneIndex, error) {
+	if maxResults < 1 {
+		return nil, 0, fmt.Errorf("%w: %d", ErrInvalidMaxResults, maxResults)
+	}
+
+	w.milestonesMutex.RLock()
+	defer w.milestonesMutex.RUnlock()
+
+	if len(w.milestones) == 0 {
+		return nil, 0, fmt.Errorf("%w: window is empty", ErrIndexOutsideWindow)
+	}
+
+	oldestIndex := w.milestones[0].index
+	newestIndex := w.milestones[len(w.milestones)-1].index
+	if startIndex < oldestIndex || startIndex > newestIndex {
+		return nil, 0, fmt.Errorf("%w: %d, window is %d-%d", ErrIndexOutsideWindow, startIndex, oldestIndex, newestIndex)
+	}
+
+	results := make([]*AddressChanges, 0)
+	for _, milestone := range w.milestones[startIndex-oldestIndex:] {
+		changes, exists := milestone.addresses[address.Key()]
+		if !exists {
+			continue
+		}
+
+		if len(results) == maxResults {
+			return results, milestone.index, nil
+		}
+		results = append(results, changes)
+	}
+
+	return results, 0, nil
+}
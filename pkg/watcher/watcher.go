@@ -0,0 +1,237 @@
+package watcher
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/hive.go/core/kvstore"
+	"github.com/iotaledger/hive.go/core/logger"
+	"github.com/iotaledger/hive.go/serializer/v2"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+	"github.com/iotaledger/inx-app/pkg/pubsub"
+	inx "github.com/iotaledger/inx/go"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// NotificationTypeCreated is the type of notifications about created outputs.
+	NotificationTypeCreated = "created"
+	// NotificationTypeConsumed is the type of notifications about consumed outputs.
+	NotificationTypeConsumed = "consumed"
+)
+
+// ErrInvalidAddress is returned when a persisted watched address can't be parsed.
+var ErrInvalidAddress = errors.New("invalid watched address")
+
+// TopicNotification returns the topic a *Notification for the given bech32 address is published on.
+func TopicNotification(bech32Address string) string {
+	return pubsub.Topic("watcher", bech32Address)
+}
+
+// Notification is emitted whenever an output was created or consumed for a watched address.
+type Notification struct {
+	// Type is either NotificationTypeCreated or NotificationTypeConsumed.
+	Type string `json:"type"`
+	// Address is the bech32 encoded watched address.
+	Address string `json:"address"`
+	// MilestoneIndex is the index of the milestone that confirmed the ledger change.
+	MilestoneIndex uint32 `json:"milestoneIndex"`
+	// OutputID is the hex encoded ID of the output.
+	OutputID string `json:"outputId"`
+	// BlockID is the hex encoded ID of the block that created the output.
+	BlockID string `json:"blockId"`
+	// TransactionIDSpent is the hex encoded ID of the transaction that consumed the output.
+	TransactionIDSpent string `json:"transactionIdSpent,omitempty"`
+	// Output is the output itself.
+	Output iotago.Output `json:"output"`
+}
+
+func NotificationCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(notification *Notification))(params[0].(*Notification))
+}
+
+type Events struct {
+	// Notification is triggered for every created or consumed output of a watched address.
+	Notification *events.Event
+}
+
+// Watcher keeps a persisted set of watched addresses and emits notifications
+// whenever outputs are created or consumed for them.
+// Notifications are triggered as events and, if a bus is given, published on TopicNotification,
+// so they can be forwarded to webhooks or websocket clients.
+type Watcher struct {
+	// the logger used to log events.
+	*logger.WrappedLogger
+
+	store  kvstore.KVStore
+	hrp    iotago.NetworkPrefix
+	pubSub *pubsub.Bus
+
+	addressesMutex sync.RWMutex
+	addresses      map[string]iotago.Address
+
+	Events *Events
+}
+
+// New creates a new Watcher that persists the watched addresses in the given store.
+// The bech32 HRP is used to encode the addresses in the notifications, the bus is optional.
+func New(store kvstore.KVStore, log *logger.Logger, hrp iotago.NetworkPrefix, bus *pubsub.Bus) (*Watcher, error) {
+	w := &Watcher{
+		WrappedLogger: logger.NewWrappedLogger(log),
+		store:         store,
+		hrp:           hrp,
+		pubSub:        bus,
+		addresses:     make(map[string]iotago.Address),
+		Events: &Events{
+			Notification: events.NewEvent(NotificationCaller),
+		},
+	}
+
+	var innerErr error
+	if err := store.IterateKeys(kvstore.EmptyPrefix, func(key kvstore.Key) bool {
+		address, err := addressFromKey(key)
+		if err != nil {
+			innerErr = err
+
+			return false
+		}
+		w.addresses[address.Key()] = address
+
+		return true
+	}); err != nil {
+		return nil, err
+	}
+	if innerErr != nil {
+		return nil, innerErr
+	}
+
+	return w, nil
+}
+
+func addressFromKey(key kvstore.Key) (iotago.Address, error) {
+	if len(key) == 0 {
+		return nil, fmt.Errorf("%w: empty key", ErrInvalidAddress)
+	}
+
+	address, err := iotago.AddressSelector(uint32(key[0]))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAddress, err.Error())
+	}
+
+	if _, err := address.Deserialize(key, serializer.DeSeriModePerformValidation, nil); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAddress, err.Error())
+	}
+
+	return address, nil
+}
+
+// Watch adds the address to the watched addresses.
+func (w *Watcher) Watch(address iotago.Address) error {
+	key, err := address.Serialize(serializer.DeSeriModeNoValidation, nil)
+	if err != nil {
+		return err
+	}
+
+	w.addressesMutex.Lock()
+	defer w.addressesMutex.Unlock()
+
+	if err := w.store.Set(key, []byte{}); err != nil {
+		return err
+	}
+	w.addresses[address.Key()] = address.Clone()
+
+	return nil
+}
+
+// Unwatch removes the address from the watched addresses.
+func (w *Watcher) Unwatch(address iotago.Address) error {
+	key, err := address.Serialize(serializer.DeSeriModeNoValidation, nil)
+	if err != nil {
+		return err
+	}
+
+	w.addressesMutex.Lock()
+	defer w.addressesMutex.Unlock()
+
+	if err := w.store.Delete(key); err != nil {
+		return err
+	}
+	delete(w.addresses, address.Key())
+
+	return nil
+}
+
+// IsWatched reports whether the address is watched.
+func (w *Watcher) IsWatched(address iotago.Address) bool {
+	w.addressesMutex.RLock()
+	defer w.addressesMutex.RUnlock()
+
+	_, watched := w.addresses[address.Key()]
+
+	return watched
+}
+
+// WatchedAddresses returns all watched addresses.
+func (w *Watcher) WatchedAddresses() []iotago.Address {
+	w.addressesMutex.RLock()
+	defer w.addressesMutex.RUnlock()
+
+	addresses := make([]iotago.Address, 0, len(w.addresses))
+	for _, address := range w.addresses {
+		addresses = append(addresses, address)
+	}
+
+	return addresses
+}
+
+func (w *Watcher) notify(notificationType string, milestoneIndex uint32, ledgerOutput *inx.LedgerOutput, transactionIDSpent string) error {
+	output, err := ledgerOutput.UnwrapOutput(serializer.DeSeriModeNoValidation, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, address := range nodebridge.OutputAddresses(output) {
+		if !w.IsWatched(address) {
+			continue
+		}
+
+		notification := &Notification{
+			Type:               notificationType,
+			Address:            address.Bech32(w.hrp),
+			MilestoneIndex:     milestoneIndex,
+			OutputID:           ledgerOutput.UnwrapOutputID().ToHex(),
+			BlockID:            ledgerOutput.UnwrapBlockID().ToHex(),
+			TransactionIDSpent: transactionIDSpent,
+			Output:             output,
+		}
+
+		w.Events.Notification.Trigger(notification)
+		if w.pubSub != nil {
+			w.pubSub.Publish(TopicNotification(notification.Address), notification)
+		}
+	}
+
+	return nil
+}
+
+// ConsumeLedgerUpdate emits the notifications for all watched addresses affected by the ledger update.
+// It can be passed to NodeBridge.ListenToLedgerUpdates or used as part of another consumer.
+func (w *Watcher) ConsumeLedgerUpdate(update *nodebridge.LedgerUpdate) error {
+	for _, spent := range update.Consumed {
+		transactionIDSpent := spent.UnwrapTransactionIDSpent()
+		if err := w.notify(NotificationTypeConsumed, update.MilestoneIndex, spent.GetOutput(), iotago.EncodeHex(transactionIDSpent[:])); err != nil {
+			return fmt.Errorf("processing consumed output failed: %w", err)
+		}
+	}
+
+	for _, created := range update.Created {
+		if err := w.notify(NotificationTypeCreated, update.MilestoneIndex, created, ""); err != nil {
+			return fmt.Errorf("processing created output failed: %w", err)
+		}
+	}
+
+	return nil
+}
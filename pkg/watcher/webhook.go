@@ -0,0 +1,117 @@
+package watcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/inx-app/pkg/backpressure"
+	"github.com/iotaledger/inx-app/pkg/jobqueue"
+)
+
+// JobTypeWebhook is the job type used to deliver notifications to webhook targets.
+const JobTypeWebhook = "watcher.webhook"
+
+// webhookJob is the payload of a webhook delivery job.
+type webhookJob struct {
+	URL          string          `json:"url"`
+	Notification json.RawMessage `json:"notification"`
+}
+
+// Webhook delivers the notifications of a Watcher to HTTP endpoints.
+// Deliveries are durable jobs, so they are retried with backoff and survive restarts.
+type Webhook struct {
+	queue   *jobqueue.Queue
+	client  *http.Client
+	urls    []string
+	metrics *backpressure.Metrics
+
+	onNotification *events.Closure
+}
+
+// NewWebhook creates a new Webhook that posts every notification as JSON to the given URLs.
+// It registers its handler on the queue, the backpressure metrics are optional.
+func NewWebhook(queue *jobqueue.Queue, timeout time.Duration, metrics *backpressure.Metrics, urls ...string) *Webhook {
+	webhook := &Webhook{
+		queue:   queue,
+		client:  &http.Client{Timeout: timeout},
+		urls:    urls,
+		metrics: metrics,
+	}
+	queue.RegisterHandler(JobTypeWebhook, webhook.deliver)
+
+	return webhook
+}
+
+// Attach enqueues a delivery for every notification of the watcher.
+func (h *Webhook) Attach(w *Watcher) {
+	h.onNotification = events.NewClosure(func(notification *Notification) {
+		if err := h.Enqueue(notification); err != nil {
+			w.LogWarnf("enqueuing webhook notification for %s failed: %s", notification.Address, err)
+		}
+	})
+	w.Events.Notification.Hook(h.onNotification)
+}
+
+// Detach stops enqueuing deliveries for the notifications of the watcher.
+func (h *Webhook) Detach(w *Watcher) {
+	if h.onNotification == nil {
+		return
+	}
+	w.Events.Notification.Detach(h.onNotification)
+}
+
+// Enqueue enqueues the delivery of the notification to all URLs.
+func (h *Webhook) Enqueue(notification *Notification) error {
+	notificationJSON, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	for _, url := range h.urls {
+		payload, err := json.Marshal(&webhookJob{URL: url, Notification: notificationJSON})
+		if err != nil {
+			return err
+		}
+
+		if _, err := h.queue.Enqueue(JobTypeWebhook, payload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *Webhook) deliver(ctx context.Context, job *jobqueue.Job) error {
+	if job.Attempts > 0 {
+		// the target did not accept the notification in time
+		h.metrics.Delayed(backpressure.SourceWebhook)
+	}
+
+	delivery := &webhookJob{}
+	if err := json.Unmarshal(job.Payload, delivery); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Notification))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s responded with status code %d", delivery.URL, resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package workerpool
+
+import (
+	"context"
+	"sync"
+)
+
+// Group runs functions concurrently, bounded by a limit, it is meant for short lived fan-outs
+// like batched lookups. Use a Pool for long running workers.
+type Group struct {
+	semaphore chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewGroup creates a new Group that runs at most limit functions at the same time.
+func NewGroup(limit int) *Group {
+	if limit < 1 {
+		limit = 1
+	}
+
+	return &Group{
+		semaphore: make(chan struct{}, limit),
+	}
+}
+
+// Go runs the function once a slot is free, it blocks until then.
+// If the context is done before, the function is not run and the error of the context is returned.
+func (g *Group) Go(ctx context.Context, f func()) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case g.semaphore <- struct{}{}:
+	}
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer func() { <-g.semaphore }()
+
+		f()
+	}()
+
+	return nil
+}
+
+// Wait blocks until all functions finished.
+func (g *Group) Wait() {
+	g.wg.Wait()
+}
@@ -0,0 +1,59 @@
+package workerpool
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics exposes the counters of pools, labeled by the name of the pool.
+// Metrics implements prometheus.Collector, so it can be registered directly.
+type Metrics struct {
+	pools []*Pool
+
+	workersDesc   *prometheus.Desc
+	activeDesc    *prometheus.Desc
+	queuedDesc    *prometheus.Desc
+	submittedDesc *prometheus.Desc
+	completedDesc *prometheus.Desc
+	panickedDesc  *prometheus.Desc
+}
+
+// NewMetrics creates new Metrics for the given pools. The namespace is used as prefix of the prometheus metric names.
+func NewMetrics(namespace string, pools ...*Pool) *Metrics {
+	newDesc := func(name string, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "workerpool", name), help, []string{"pool"}, nil)
+	}
+
+	return &Metrics{
+		pools:         pools,
+		workersDesc:   newDesc("workers", "The amount of workers of the pool."),
+		activeDesc:    newDesc("tasks_active", "The amount of tasks that are executed at the moment."),
+		queuedDesc:    newDesc("tasks_queued", "The amount of submitted tasks that wait for a worker."),
+		submittedDesc: newDesc("tasks_submitted_total", "The total amount of submitted tasks."),
+		completedDesc: newDesc("tasks_completed_total", "The total amount of executed tasks."),
+		panickedDesc:  newDesc("tasks_panicked_total", "The total amount of tasks that panicked."),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- m.workersDesc
+	ch <- m.activeDesc
+	ch <- m.queuedDesc
+	ch <- m.submittedDesc
+	ch <- m.completedDesc
+	ch <- m.panickedDesc
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	for _, pool := range m.pools {
+		stats := pool.Stats()
+
+		ch <- prometheus.MustNewConstMetric(m.workersDesc, prometheus.GaugeValue, float64(stats.Workers), pool.name)
+		ch <- prometheus.MustNewConstMetric(m.activeDesc, prometheus.GaugeValue, float64(stats.Active), pool.name)
+		ch <- prometheus.MustNewConstMetric(m.queuedDesc, prometheus.GaugeValue, float64(stats.Queued), pool.name)
+		ch <- prometheus.MustNewConstMetric(m.submittedDesc, prometheus.CounterValue, float64(stats.Submitted), pool.name)
+		ch <- prometheus.MustNewConstMetric(m.completedDesc, prometheus.CounterValue, float64(stats.Completed), pool.name)
+		ch <- prometheus.MustNewConstMetric(m.panickedDesc, prometheus.CounterValue, float64(stats.Panicked), pool.name)
+	}
+}
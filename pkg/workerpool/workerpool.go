@@ -0,0 +1,223 @@
+// Package workerpool provides bounded worker pools, so apps don't need to roll their own goroutine pools
+// around the consume callbacks of the node bridge.
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+
+	"github.com/iotaledger/hive.go/core/daemon"
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/core/logger"
+)
+
+// ErrPoolStopped is returned when a task is submitted to a pool that was shut down.
+var ErrPoolStopped = errors.New("worker pool stopped")
+
+// Task is a unit of work executed by a worker of the pool.
+// The context is done once the pool is shutting down.
+type Task func(ctx context.Context)
+
+// Stats are the counters of a pool.
+type Stats struct {
+	// Workers is the amount of workers of the pool.
+	Workers int `json:"workers"`
+	// Active is the amount of tasks that are executed at the moment.
+	Active int64 `json:"active"`
+	// Queued is the amount of submitted tasks that wait for a worker.
+	Queued int `json:"queued"`
+	// Submitted is the total amount of submitted tasks.
+	Submitted uint64 `json:"submitted"`
+	// Completed is the total amount of executed tasks, including the ones that panicked.
+	Completed uint64 `json:"completed"`
+	// Panicked is the total amount of tasks that panicked.
+	Panicked uint64 `json:"panicked"`
+}
+
+// Pool executes tasks with a bounded amount of workers.
+// Submitting blocks if all workers are busy and the queue is full, which applies backpressure to the producer.
+// Panics of tasks are recovered and logged, so a single faulty task does not crash the app.
+type Pool struct {
+	// the counters are accessed atomically and must stay 64-bit aligned.
+	submitted uint64
+	completed uint64
+	panicked  uint64
+	active    int64
+
+	// the logger used to log events.
+	*logger.WrappedLogger
+
+	name         string
+	workerCount  int
+	queueSize    int
+	panicHandler func(recovered any)
+
+	tasks   chan Task
+	pending sync.WaitGroup
+
+	stateMutex sync.RWMutex
+	stopped    bool
+}
+
+// WithWorkerCount sets the amount of tasks that are executed in parallel, it defaults to the amount of CPUs.
+func WithWorkerCount(workerCount int) options.Option[Pool] {
+	return func(p *Pool) {
+		p.workerCount = workerCount
+	}
+}
+
+// WithQueueSize sets the amount of submitted tasks that are buffered while all workers are busy.
+// It defaults to the amount of workers.
+func WithQueueSize(queueSize int) options.Option[Pool] {
+	return func(p *Pool) {
+		p.queueSize = queueSize
+	}
+}
+
+// WithPanicHandler sets a handler that is called with the recovered value if a task panics, after it was logged.
+func WithPanicHandler(panicHandler func(recovered any)) options.Option[Pool] {
+	return func(p *Pool) {
+		p.panicHandler = panicHandler
+	}
+}
+
+// New creates a new Pool. The name identifies the pool in logs, metrics and the daemon.
+func New(name string, log *logger.Logger, opts ...options.Option[Pool]) *Pool {
+	p := options.Apply(&Pool{
+		WrappedLogger: logger.NewWrappedLogger(log),
+		name:          name,
+		workerCount:   runtime.NumCPU(),
+		queueSize:     -1,
+	}, opts)
+
+	if p.workerCount < 1 {
+		p.workerCount = 1
+	}
+	if p.queueSize < 0 {
+		p.queueSize = p.workerCount
+	}
+	p.tasks = make(chan Task, p.queueSize)
+
+	return p
+}
+
+// Name returns the name of the pool.
+func (p *Pool) Name() string {
+	return p.name
+}
+
+// Submit submits the task, it blocks until the task was queued or the given context is done.
+func (p *Pool) Submit(ctx context.Context, task Task) error {
+	p.stateMutex.RLock()
+	defer p.stateMutex.RUnlock()
+
+	if p.stopped {
+		return ErrPoolStopped
+	}
+
+	p.pending.Add(1)
+	select {
+	case p.tasks <- task:
+		atomic.AddUint64(&p.submitted, 1)
+
+		return nil
+	case <-ctx.Done():
+		p.pending.Done()
+
+		return ctx.Err()
+	}
+}
+
+// TrySubmit submits the task if it can be queued without blocking and reports whether it was submitted.
+func (p *Pool) TrySubmit(task Task) bool {
+	p.stateMutex.RLock()
+	defer p.stateMutex.RUnlock()
+
+	if p.stopped {
+		return false
+	}
+
+	p.pending.Add(1)
+	select {
+	case p.tasks <- task:
+		atomic.AddUint64(&p.submitted, 1)
+
+		return true
+	default:
+		p.pending.Done()
+
+		return false
+	}
+}
+
+// Wait blocks until all submitted tasks were executed.
+func (p *Pool) Wait() {
+	p.pending.Wait()
+}
+
+func (p *Pool) execute(ctx context.Context, task Task) {
+	atomic.AddInt64(&p.active, 1)
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			atomic.AddUint64(&p.panicked, 1)
+			p.LogErrorf("task of worker pool %s panicked: %v\n%s", p.name, recovered, debug.Stack())
+
+			if p.panicHandler != nil {
+				p.panicHandler(recovered)
+			}
+		}
+
+		atomic.AddInt64(&p.active, -1)
+		atomic.AddUint64(&p.completed, 1)
+		p.pending.Done()
+	}()
+
+	task(ctx)
+}
+
+// Run executes the submitted tasks until the given context is done.
+// On shutdown no new tasks are accepted, the queued tasks are still executed with the done context,
+// so they can abort early, and Run returns once all of them finished.
+func (p *Pool) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < p.workerCount; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for task := range p.tasks {
+				p.execute(ctx, task)
+			}
+		}()
+	}
+
+	<-ctx.Done()
+
+	p.stateMutex.Lock()
+	p.stopped = true
+	close(p.tasks)
+	p.stateMutex.Unlock()
+
+	wg.Wait()
+}
+
+// RegisterBackgroundWorker runs the pool as a background worker of the daemon,
+// so it is shut down, and its queued tasks are finished, in the given shutdown order.
+func (p *Pool) RegisterBackgroundWorker(d daemon.Daemon, shutdownOrder int) error {
+	return d.BackgroundWorker(p.name, p.Run, shutdownOrder)
+}
+
+// Stats returns the counters of the pool.
+func (p *Pool) Stats() Stats {
+	return Stats{
+		Workers:   p.workerCount,
+		Active:    atomic.LoadInt64(&p.active),
+		Queued:    len(p.tasks),
+		Submitted: atomic.LoadUint64(&p.submitted),
+		Completed: atomic.LoadUint64(&p.completed),
+		Panicked:  atomic.LoadUint64(&p.panicked),
+	}
+}